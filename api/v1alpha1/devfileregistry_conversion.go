@@ -0,0 +1,155 @@
+//
+// Copyright (c) 2020 Red Hat, Inc.
+// This program and the accompanying materials are made
+// available under the terms of the Eclipse Public License 2.0
+// which is available at https://www.eclipse.org/legal/epl-2.0/
+//
+// SPDX-License-Identifier: EPL-2.0
+//
+// Contributors:
+//   Red Hat, Inc. - initial API and implementation
+
+package v1alpha1
+
+import (
+	"encoding/json"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	"github.com/devfile/registry-operator/api/v1beta1"
+)
+
+// ConversionDataAnnotation holds the v1beta1 spec of a DevfileRegistry that was converted to v1alpha1, when it has
+// fields v1alpha1 can't represent. It lets those fields survive a round trip through v1alpha1.
+const ConversionDataAnnotation = "registry.devfile.io/v1beta1-spec"
+
+// ConversionStatusAnnotation holds the v1beta1 status fields of a DevfileRegistry that v1alpha1 can't represent, such
+// as the garbage collection and storage usage status, when it's converted to v1alpha1
+const ConversionStatusAnnotation = "registry.devfile.io/v1beta1-status"
+
+// ConvertTo converts this DevfileRegistry to the hub version (v1beta1)
+func (src *DevfileRegistry) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*v1beta1.DevfileRegistry)
+
+	src.ObjectMeta.DeepCopyInto(&dst.ObjectMeta)
+	dst.Spec = v1beta1.DevfileRegistrySpec{}
+	if data, ok := dst.Annotations[ConversionDataAnnotation]; ok {
+		// Start from the v1beta1 spec this object was converted from, the v1alpha1 fields are applied on top of it
+		if err := json.Unmarshal([]byte(data), &dst.Spec); err != nil {
+			return err
+		}
+		removeAnnotation(&dst.ObjectMeta, ConversionDataAnnotation)
+	}
+	src.Spec.convertTo(&dst.Spec)
+
+	dst.Status = v1beta1.DevfileRegistryStatus{}
+	if data, ok := dst.Annotations[ConversionStatusAnnotation]; ok {
+		if err := json.Unmarshal([]byte(data), &dst.Status); err != nil {
+			return err
+		}
+		removeAnnotation(&dst.ObjectMeta, ConversionStatusAnnotation)
+	}
+	dst.Status.URL = src.Status.URL
+	dst.Status.Phase = v1beta1.DevfileRegistryPhase(src.Status.Phase)
+	dst.Status.ObservedGeneration = src.Status.ObservedGeneration
+	dst.Status.Conditions = copyConditions(src.Status.Conditions)
+	return nil
+}
+
+// ConvertFrom converts the hub version (v1beta1) to this DevfileRegistry
+func (dst *DevfileRegistry) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*v1beta1.DevfileRegistry)
+
+	src.ObjectMeta.DeepCopyInto(&dst.ObjectMeta)
+	dst.Spec = DevfileRegistrySpec{
+		DevfileIndexImage: src.Spec.DevfileIndex.Image,
+		OciRegistryImage:  src.Spec.OCIRegistry.Image,
+		Storage: DevfileRegistrySpecStorage{
+			Enabled:            copyBool(src.Spec.Storage.Enabled),
+			RegistryVolumeSize: src.Spec.Storage.Size,
+		},
+		TLS: DevfileRegistrySpecTLS{
+			Enabled:    copyBool(src.Spec.TLS.Enabled),
+			SecretName: src.Spec.TLS.SecretName,
+		},
+		K8s: DevfileRegistrySpecK8sOnly{
+			IngressDomain: src.Spec.Exposure.Ingress.Domain,
+		},
+	}
+
+	// Keep the whole v1beta1 spec around if some of it was lost in the conversion
+	converted := v1beta1.DevfileRegistrySpec{}
+	dst.Spec.convertTo(&converted)
+	if !equality.Semantic.DeepEqual(converted, src.Spec) {
+		data, err := json.Marshal(src.Spec)
+		if err != nil {
+			return err
+		}
+		if dst.Annotations == nil {
+			dst.Annotations = map[string]string{}
+		}
+		dst.Annotations[ConversionDataAnnotation] = string(data)
+	}
+
+	// Likewise for the status fields only v1beta1 has
+	lost := v1beta1.DevfileRegistryStatus{
+		GarbageCollection: src.Status.GarbageCollection,
+		Storage:           src.Status.Storage,
+	}
+	if !equality.Semantic.DeepEqual(lost, v1beta1.DevfileRegistryStatus{}) {
+		data, err := json.Marshal(lost)
+		if err != nil {
+			return err
+		}
+		if dst.Annotations == nil {
+			dst.Annotations = map[string]string{}
+		}
+		dst.Annotations[ConversionStatusAnnotation] = string(data)
+	}
+
+	dst.Status = DevfileRegistryStatus{
+		URL:                src.Status.URL,
+		Phase:              DevfileRegistryPhase(src.Status.Phase),
+		ObservedGeneration: src.Status.ObservedGeneration,
+		Conditions:         copyConditions(src.Status.Conditions),
+	}
+	return nil
+}
+
+// convertTo sets the fields of a v1beta1 spec that have an equivalent in this spec
+func (src *DevfileRegistrySpec) convertTo(dst *v1beta1.DevfileRegistrySpec) {
+	dst.DevfileIndex.Image = src.DevfileIndexImage
+	dst.OCIRegistry.Image = src.OciRegistryImage
+	dst.Storage.Enabled = copyBool(src.Storage.Enabled)
+	dst.Storage.Size = src.Storage.RegistryVolumeSize
+	dst.TLS.Enabled = copyBool(src.TLS.Enabled)
+	dst.TLS.SecretName = src.TLS.SecretName
+	dst.Exposure.Ingress.Domain = src.K8s.IngressDomain
+}
+
+// removeAnnotation deletes an annotation holding conversion data, leaving no empty annotations behind
+func removeAnnotation(meta *metav1.ObjectMeta, key string) {
+	delete(meta.Annotations, key)
+	if len(meta.Annotations) == 0 {
+		meta.Annotations = nil
+	}
+}
+
+func copyBool(b *bool) *bool {
+	if b == nil {
+		return nil
+	}
+	out := *b
+	return &out
+}
+
+func copyConditions(conditions []metav1.Condition) []metav1.Condition {
+	if conditions == nil {
+		return nil
+	}
+	out := make([]metav1.Condition, len(conditions))
+	copy(out, conditions)
+	return out
+}
@@ -0,0 +1,216 @@
+//
+// Copyright (c) 2020 Red Hat, Inc.
+// This program and the accompanying materials are made
+// available under the terms of the Eclipse Public License 2.0
+// which is available at https://www.eclipse.org/legal/epl-2.0/
+//
+// SPDX-License-Identifier: EPL-2.0
+//
+// Contributors:
+//   Red Hat, Inc. - initial API and implementation
+
+package v1alpha1
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/devfile/registry-operator/api/v1beta1"
+)
+
+func TestConvertRoundTripFromV1alpha1(t *testing.T) {
+	enabled := true
+	disabled := false
+
+	tests := []struct {
+		name string
+		cr   DevfileRegistry
+	}{
+		{
+			name: "Case 1: Empty spec",
+			cr: DevfileRegistry{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-registry"},
+			},
+		},
+		{
+			name: "Case 2: Every field set",
+			cr: DevfileRegistry{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "test-registry",
+					Annotations: map[string]string{"test": "annotation"},
+				},
+				Spec: DevfileRegistrySpec{
+					DevfileIndexImage: "quay.io/test/devfile-index:next",
+					OciRegistryImage:  "registry:latest",
+					Storage: DevfileRegistrySpecStorage{
+						Enabled:            &disabled,
+						RegistryVolumeSize: "5Gi",
+					},
+					TLS: DevfileRegistrySpecTLS{
+						Enabled:    &enabled,
+						SecretName: "tls-secret",
+					},
+					K8s: DevfileRegistrySpecK8sOnly{
+						IngressDomain: "example.com",
+					},
+				},
+				Status: DevfileRegistryStatus{
+					URL:                "https://devfile-registry.example.com",
+					Phase:              DevfileRegistryPhaseReady,
+					ObservedGeneration: 2,
+					Conditions: []metav1.Condition{
+						{Type: ConditionReady, Status: metav1.ConditionTrue, Reason: "AllConditionsReady"},
+					},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hub := &v1beta1.DevfileRegistry{}
+			if err := tt.cr.ConvertTo(hub); err != nil {
+				t.Fatalf("TestConvertRoundTripFromV1alpha1 error: unexpected error converting to v1beta1: %v", err)
+			}
+			if hub.Spec.Storage.Size != tt.cr.Spec.Storage.RegistryVolumeSize {
+				t.Errorf("TestConvertRoundTripFromV1alpha1 error: storage size mismatch, expected: %v got: %v", tt.cr.Spec.Storage.RegistryVolumeSize, hub.Spec.Storage.Size)
+			}
+			if hub.Spec.Exposure.Ingress.Domain != tt.cr.Spec.K8s.IngressDomain {
+				t.Errorf("TestConvertRoundTripFromV1alpha1 error: ingress domain mismatch, expected: %v got: %v", tt.cr.Spec.K8s.IngressDomain, hub.Spec.Exposure.Ingress.Domain)
+			}
+
+			got := DevfileRegistry{}
+			if err := got.ConvertFrom(hub); err != nil {
+				t.Fatalf("TestConvertRoundTripFromV1alpha1 error: unexpected error converting from v1beta1: %v", err)
+			}
+			if !equality.Semantic.DeepEqual(got, tt.cr) {
+				t.Errorf("TestConvertRoundTripFromV1alpha1 error: round trip mismatch, expected: %+v got: %+v", tt.cr, got)
+			}
+		})
+	}
+}
+
+func TestConvertRoundTripFromV1beta1(t *testing.T) {
+	enabled := true
+	resources := &corev1.ResourceRequirements{
+		Limits: corev1.ResourceList{
+			corev1.ResourceMemory: resource.MustParse("512Mi"),
+		},
+	}
+
+	lastRunTime := metav1.Unix(1700000000, 0)
+	freedBytes := int64(1024)
+
+	tests := []struct {
+		name                 string
+		cr                   v1beta1.DevfileRegistry
+		wantAnnotation       bool
+		wantStatusAnnotation bool
+	}{
+		{
+			name: "Case 1: Spec fully representable in v1alpha1",
+			cr: v1beta1.DevfileRegistry{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-registry"},
+				Spec: v1beta1.DevfileRegistrySpec{
+					DevfileIndex: v1beta1.DevfileRegistryDevfileIndex{
+						Image: "quay.io/test/devfile-index:next",
+					},
+					Storage: v1beta1.DevfileRegistryStorage{
+						Enabled: &enabled,
+						Size:    "5Gi",
+					},
+					TLS: v1beta1.DevfileRegistryTLS{
+						SecretName: "tls-secret",
+					},
+					Exposure: v1beta1.DevfileRegistryExposure{
+						Ingress: v1beta1.DevfileRegistryIngress{
+							Domain: "example.com",
+						},
+					},
+				},
+			},
+			wantAnnotation: false,
+		},
+		{
+			name: "Case 2: Resources and reclaim policy only available in v1beta1",
+			cr: v1beta1.DevfileRegistry{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "test-registry",
+					Annotations: map[string]string{"test": "annotation"},
+				},
+				Spec: v1beta1.DevfileRegistrySpec{
+					DevfileIndex: v1beta1.DevfileRegistryDevfileIndex{
+						Image:     "quay.io/test/devfile-index:next",
+						Resources: resources,
+					},
+					OCIRegistry: v1beta1.DevfileRegistryOCIRegistry{
+						Resources: resources,
+					},
+					Storage: v1beta1.DevfileRegistryStorage{
+						ReclaimPolicy: v1beta1.ReclaimPolicySnapshot,
+					},
+				},
+			},
+			wantAnnotation: true,
+		},
+		{
+			name: "Case 3: Garbage collection status only available in v1beta1",
+			cr: v1beta1.DevfileRegistry{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-registry"},
+				Status: v1beta1.DevfileRegistryStatus{
+					URL:                "https://registry.example.com",
+					Phase:              v1beta1.DevfileRegistryPhaseReady,
+					ObservedGeneration: 2,
+					GarbageCollection: &v1beta1.DevfileRegistryGarbageCollectionStatus{
+						LastJob:        "test-registry-gc-1",
+						LastRunTime:    &lastRunTime,
+						LastFreedBytes: &freedBytes,
+					},
+				},
+			},
+			wantAnnotation:       false,
+			wantStatusAnnotation: true,
+		},
+		{
+			name: "Case 4: Storage usage only available in v1beta1",
+			cr: v1beta1.DevfileRegistry{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-registry"},
+				Status: v1beta1.DevfileRegistryStatus{
+					URL: "https://registry.example.com",
+					Storage: &v1beta1.DevfileRegistryStorageStatus{
+						UsedBytes:        512,
+						CapacityBytes:    1024,
+						LastMeasuredTime: &lastRunTime,
+					},
+				},
+			},
+			wantAnnotation:       false,
+			wantStatusAnnotation: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spoke := &DevfileRegistry{}
+			if err := spoke.ConvertFrom(&tt.cr); err != nil {
+				t.Fatalf("TestConvertRoundTripFromV1beta1 error: unexpected error converting from v1beta1: %v", err)
+			}
+			if _, ok := spoke.Annotations[ConversionDataAnnotation]; ok != tt.wantAnnotation {
+				t.Errorf("TestConvertRoundTripFromV1beta1 error: conversion annotation mismatch, expected: %v got: %v", tt.wantAnnotation, ok)
+			}
+			if _, ok := spoke.Annotations[ConversionStatusAnnotation]; ok != tt.wantStatusAnnotation {
+				t.Errorf("TestConvertRoundTripFromV1beta1 error: status conversion annotation mismatch, expected: %v got: %v", tt.wantStatusAnnotation, ok)
+			}
+
+			got := v1beta1.DevfileRegistry{}
+			if err := spoke.ConvertTo(&got); err != nil {
+				t.Fatalf("TestConvertRoundTripFromV1beta1 error: unexpected error converting to v1beta1: %v", err)
+			}
+			if !equality.Semantic.DeepEqual(got, tt.cr) {
+				t.Errorf("TestConvertRoundTripFromV1beta1 error: round trip mismatch, expected: %+v got: %+v", tt.cr, got)
+			}
+		})
+	}
+}
@@ -24,10 +24,12 @@ type DevfileRegistrySpec struct {
 	// Important: Run "make" to regenerate code after modifying this file
 
 	// Sets the container image containing devfile stacks to be deployed on the Devfile Registry
+	// Defaults to the devfile index image specified by the operator.
+	// +optional
 	DevfileIndexImage string `json:"devfileIndexImage,omitempty"`
 
 	// Overrides the container image used for the OCI registry.
-	// Recommended to leave blank and default to the image specified by the operator.
+	// Defaults to the image specified by the operator.
 	// +optional
 	OciRegistryImage string                     `json:"ociRegistryImage,omitempty"`
 	Storage          DevfileRegistrySpecStorage `json:"storage,omitempty"`
@@ -66,11 +68,56 @@ type DevfileRegistrySpecK8sOnly struct {
 	IngressDomain string `json:"ingressDomain,omitempty"`
 }
 
+// DevfileRegistryPhase is a high-level summary of where the DevfileRegistry is in its lifecycle
+type DevfileRegistryPhase string
+
+const (
+	// DevfileRegistryPhasePending means the operator has not yet created the registry's resources
+	DevfileRegistryPhasePending DevfileRegistryPhase = "Pending"
+	// DevfileRegistryPhaseDeploying means the registry's resources exist, but the registry is not yet serving requests
+	DevfileRegistryPhaseDeploying DevfileRegistryPhase = "Deploying"
+	// DevfileRegistryPhaseReady means the registry is deployed and reachable at status.url
+	DevfileRegistryPhaseReady DevfileRegistryPhase = "Ready"
+	// DevfileRegistryPhaseFailed means the operator hit an error reconciling one of the registry's resources
+	DevfileRegistryPhaseFailed DevfileRegistryPhase = "Failed"
+	// DevfileRegistryPhaseDeleting means the registry is being deleted, and its storage is being reclaimed
+	DevfileRegistryPhaseDeleting DevfileRegistryPhase = "Deleting"
+)
+
+// Condition types reported in the DevfileRegistry status
+const (
+	// ConditionServiceReady indicates whether the registry's Service exists
+	ConditionServiceReady = "ServiceReady"
+	// ConditionStorageReady indicates whether the registry's storage is provisioned, or not needed
+	ConditionStorageReady = "StorageReady"
+	// ConditionDeploymentAvailable indicates whether the registry's Deployment has minimum availability
+	ConditionDeploymentAvailable = "DeploymentAvailable"
+	// ConditionExposed indicates whether the registry is exposed outside of the cluster through a Route or Ingress
+	ConditionExposed = "Exposed"
+	// ConditionServerReachable indicates whether the registry server responded on its URL
+	ConditionServerReachable = "ServerReachable"
+	// ConditionReady summarizes the other conditions, and is true once the registry is fully operational
+	ConditionReady = "Ready"
+)
+
 // DevfileRegistryStatus defines the observed state of DevfileRegistry
 type DevfileRegistryStatus struct {
-	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
-	// Important: Run "make" to regenerate code after modifying this file
+	// URL the devfile registry is served from
 	URL string `json:"url"`
+
+	// Phase is a high-level summary of the registry's state
+	// +optional
+	Phase DevfileRegistryPhase `json:"phase,omitempty"`
+
+	// ObservedGeneration is the most recent generation of the DevfileRegistry observed by the operator
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Conditions represent the latest available observations of the registry's state
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -79,7 +126,10 @@ type DevfileRegistryStatus struct {
 // DevfileRegistry is the Schema for the devfileregistries API
 // +kubebuilder:resource:path=devfileregistries,shortName=devreg;dr
 // +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase",description="The lifecycle phase of the Devfile Registry"
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].status",description="Whether the Devfile Registry is ready"
 // +kubebuilder:printcolumn:name="URL",type="string",JSONPath=".status.url",description="The URL for the Devfile Registry"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 type DevfileRegistry struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
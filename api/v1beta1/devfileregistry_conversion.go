@@ -0,0 +1,15 @@
+//
+// Copyright (c) 2020 Red Hat, Inc.
+// This program and the accompanying materials are made
+// available under the terms of the Eclipse Public License 2.0
+// which is available at https://www.eclipse.org/legal/epl-2.0/
+//
+// SPDX-License-Identifier: EPL-2.0
+//
+// Contributors:
+//   Red Hat, Inc. - initial API and implementation
+
+package v1beta1
+
+// Hub marks v1beta1 as the version every other DevfileRegistry version is converted through
+func (*DevfileRegistry) Hub() {}
@@ -0,0 +1,514 @@
+//
+// Copyright (c) 2020 Red Hat, Inc.
+// This program and the accompanying materials are made
+// available under the terms of the Eclipse Public License 2.0
+// which is available at https://www.eclipse.org/legal/epl-2.0/
+//
+// SPDX-License-Identifier: EPL-2.0
+//
+// Contributors:
+//   Red Hat, Inc. - initial API and implementation
+
+package v1beta1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DevfileRegistrySpec defines the desired state of DevfileRegistry
+type DevfileRegistrySpec struct {
+	// Configures the container serving the index of devfile stacks
+	// +optional
+	DevfileIndex DevfileRegistryDevfileIndex `json:"devfileIndex,omitempty"`
+
+	// Configures the container running the OCI registry that stores the devfile stacks
+	// +optional
+	OCIRegistry DevfileRegistryOCIRegistry `json:"ociRegistry,omitempty"`
+
+	// Configures the persistent storage for the OCI registry
+	// +optional
+	Storage DevfileRegistryStorage `json:"storage,omitempty"`
+
+	// Configures TLS for the routes or ingress exposing the registry
+	// +optional
+	TLS DevfileRegistryTLS `json:"tls,omitempty"`
+
+	// Configures how the registry is exposed outside of the cluster
+	// +optional
+	Exposure DevfileRegistryExposure `json:"exposure,omitempty"`
+
+	// Number of pods running the registry. More than one replica needs storage shared by every pod: a persistent
+	// volume claim with the ReadWriteMany access mode, or an S3 bucket. Defaults to 1.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Replicas *int32 `json:"replicas,omitempty"`
+}
+
+// DevfileRegistryDevfileIndex defines the desired state of the devfile index container
+type DevfileRegistryDevfileIndex struct {
+	// Sets the container image containing devfile stacks to be deployed on the Devfile Registry
+	// Defaults to the devfile index image specified by the operator.
+	// +optional
+	Image string `json:"image,omitempty"`
+
+	// Compute resources of the devfile index container. Defaults to the resources specified by the operator.
+	// +optional
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+}
+
+// DevfileRegistryOCIRegistry defines the desired state of the OCI registry container
+type DevfileRegistryOCIRegistry struct {
+	// Overrides the container image used for the OCI registry.
+	// Defaults to the image specified by the operator.
+	// +optional
+	Image string `json:"image,omitempty"`
+
+	// Compute resources of the OCI registry container. Defaults to the resources specified by the operator.
+	// +optional
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// Settings rendered into the configuration file of the OCI registry
+	// +optional
+	Config DevfileRegistryOCIRegistryConfig `json:"config,omitempty"`
+
+	// Schedules the deletion of the blobs no longer referenced by any manifest, which the OCI registry never
+	// reclaims on its own. The registry is switched to read-only mode while the garbage is collected. Garbage isn't
+	// collected if not set.
+	// +optional
+	GarbageCollection *DevfileRegistryGarbageCollection `json:"garbageCollection,omitempty"`
+}
+
+// DevfileRegistryGarbageCollection defines when the garbage of the OCI registry is collected
+type DevfileRegistryGarbageCollection struct {
+	// Cron schedule the garbage is collected on, in the time zone of the kube-controller-manager
+	Schedule string `json:"schedule"`
+
+	// Also deletes the manifests that are no longer tagged, along with the blobs only they reference
+	// +optional
+	DeleteUntagged bool `json:"deleteUntagged,omitempty"`
+}
+
+// DevfileRegistryOCIRegistryConfig defines the configuration of the distribution registry serving the OCI artifacts
+type DevfileRegistryOCIRegistryConfig struct {
+	// Log level of the OCI registry: error, warn, info or debug. Defaults to info.
+	// +kubebuilder:validation:Enum=error;warn;info;debug
+	// +optional
+	LogLevel string `json:"logLevel,omitempty"`
+
+	// Allows deleting images and blobs from the OCI registry by digest. Disabled by default.
+	// +optional
+	DeleteEnabled bool `json:"deleteEnabled,omitempty"`
+
+	// Interval between health checks of the registry storage. The registry reports itself unhealthy after three
+	// failed checks in a row. Storage health checks are disabled if not set.
+	// +optional
+	StorageHealthCheckInterval *metav1.Duration `json:"storageHealthCheckInterval,omitempty"`
+}
+
+// DevfileRegistryStorage defines the desired state of the storage for the DevfileRegistry
+type DevfileRegistryStorage struct {
+	// Instructs the operator to deploy the DevfileRegistry with persistent storage
+	// Enabled by default. Disabling is only recommended for development or test.
+	// +optional
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// Where the OCI registry persists its content: pvc, a persistent volume claim, or s3, an S3-compatible bucket.
+	// Defaults to pvc. No persistent volume claim is created with s3.
+	// +optional
+	Type DevfileRegistryStorageType `json:"type,omitempty"`
+
+	// Configures the S3-compatible bucket the registry content is stored in, when type is s3
+	// +optional
+	S3 DevfileRegistryS3Storage `json:"s3,omitempty"`
+
+	// Configures the size of the devfile registry's persistent volume, if enabled.
+	// Defaults to 1Gi. It can be increased later on when the StorageClass allows volume expansion, but never reduced.
+	// +kubebuilder:validation:Pattern=`^(\+|-)?(([0-9]+(\.[0-9]*)?)|(\.[0-9]+))(([KMGTPE]i)|[numkMGTPE]|([eE](\+|-)?(([0-9]+(\.[0-9]*)?)|(\.[0-9]+))))?$`
+	// +optional
+	Size string `json:"size,omitempty"`
+
+	// Name of the StorageClass the persistent volume claim is provisioned from. The default StorageClass of the
+	// cluster is used when it's not set. Can't be changed once the claim exists.
+	// +optional
+	StorageClassName *string `json:"storageClassName,omitempty"`
+
+	// Name of a VolumeSnapshot in the same namespace to provision the persistent volume claim from, e.g. one taken by
+	// a DevfileRegistryBackup. Only used when the claim is created.
+	// +optional
+	RestoreFrom string `json:"restoreFrom,omitempty"`
+
+	// Access modes of the persistent volume claim, ReadWriteOnce or ReadWriteMany.
+	// Defaults to ReadWriteOnce. Can't be changed once the claim exists.
+	// +optional
+	AccessModes []corev1.PersistentVolumeAccessMode `json:"accessModes,omitempty"`
+
+	// What happens to the persistent volume claim when the DevfileRegistry is deleted. Delete removes it along with
+	// the registry, Retain keeps it, and Snapshot takes a VolumeSnapshot of it before removing it.
+	// Defaults to Delete.
+	// +optional
+	ReclaimPolicy DevfileRegistryReclaimPolicy `json:"reclaimPolicy,omitempty"`
+
+	// Percentage of the persistent volume in use above which the StorageNearlyFull condition is set and a warning
+	// event is emitted. Defaults to 80. Only applies when the operator measures storage usage, which is disabled by
+	// default.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	UsageAlertThreshold *int32 `json:"usageAlertThreshold,omitempty"`
+
+	// Expands the persistent volume claim by half its capacity whenever it's nearly full. The StorageClass has to
+	// allow volume expansion. The claim isn't expanded if not set, or if the operator doesn't measure storage usage.
+	// +optional
+	AutoExpansion *DevfileRegistryStorageAutoExpansion `json:"autoExpansion,omitempty"`
+}
+
+// DevfileRegistryStorageAutoExpansion defines how far the operator expands a nearly full persistent volume claim
+type DevfileRegistryStorageAutoExpansion struct {
+	// Size the persistent volume claim is never expanded beyond
+	// +kubebuilder:validation:Pattern=`^(\+|-)?(([0-9]+(\.[0-9]*)?)|(\.[0-9]+))(([KMGTPE]i)|[numkMGTPE]|([eE](\+|-)?(([0-9]+(\.[0-9]*)?)|(\.[0-9]+))))?$`
+	MaxSize string `json:"maxSize"`
+}
+
+// DevfileRegistryStorageType is where the DevfileRegistry persists its content
+// +kubebuilder:validation:Enum=pvc;s3
+type DevfileRegistryStorageType string
+
+const (
+	// StorageTypePVC stores the registry content in a persistent volume claim
+	StorageTypePVC DevfileRegistryStorageType = "pvc"
+	// StorageTypeS3 stores the registry content in an S3-compatible bucket
+	StorageTypeS3 DevfileRegistryStorageType = "s3"
+)
+
+// DevfileRegistryS3Storage defines the S3-compatible bucket the DevfileRegistry stores its content in
+type DevfileRegistryS3Storage struct {
+	// Name of the bucket
+	// +optional
+	Bucket string `json:"bucket,omitempty"`
+
+	// Region of the bucket. Defaults to us-east-1.
+	// +optional
+	Region string `json:"region,omitempty"`
+
+	// URL of the S3-compatible service, such as http://minio.minio.svc:9000. Uses AWS S3 if not set.
+	// Plain HTTP is used when the URL has the http scheme.
+	// +optional
+	Endpoint string `json:"endpoint,omitempty"`
+
+	// Directory of the bucket the registry content is stored under. Defaults to the root of the bucket.
+	// +optional
+	RootDirectory string `json:"rootDirectory,omitempty"`
+
+	// Name of the secret holding the credentials to the bucket, in its AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY
+	// keys
+	// +optional
+	CredentialsSecret string `json:"credentialsSecret,omitempty"`
+}
+
+// DevfileRegistryReclaimPolicy describes what happens to the registry's storage when the DevfileRegistry is deleted
+// +kubebuilder:validation:Enum=Delete;Retain;Snapshot
+type DevfileRegistryReclaimPolicy string
+
+const (
+	// ReclaimPolicyDelete deletes the persistent volume claim with the DevfileRegistry
+	ReclaimPolicyDelete DevfileRegistryReclaimPolicy = "Delete"
+	// ReclaimPolicyRetain orphans the persistent volume claim, so that it's kept after the DevfileRegistry is deleted
+	ReclaimPolicyRetain DevfileRegistryReclaimPolicy = "Retain"
+	// ReclaimPolicySnapshot takes a VolumeSnapshot of the persistent volume claim, then deletes the claim
+	ReclaimPolicySnapshot DevfileRegistryReclaimPolicy = "Snapshot"
+)
+
+// DevfileRegistryTLS defines the desired state for TLS in the DevfileRegistry
+type DevfileRegistryTLS struct {
+	// Instructs the operator to deploy the DevfileRegistry with TLS enabled.
+	// Enabled by default. Disabling is only recommended for development or test.
+	// +optional
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// Name of an optional, pre-existing TLS secret to use for TLS termination on ingress/route resources.
+	// On OpenShift, the certificate, key and the CA in the ca.crt key of the secret are copied into the routes.
+	// +optional
+	SecretName string `json:"secretName,omitempty"`
+
+	// Where the OpenShift routes terminate TLS: edge, reencrypt or passthrough. Defaults to edge, or to reencrypt
+	// when inPod is set.
+	// Passthrough routes can't route by path, so the OCI registry is then only reachable from inside the cluster.
+	// +optional
+	Termination DevfileRegistryTLSTermination `json:"termination,omitempty"`
+
+	// What the OpenShift routes do with plain HTTP requests: None, Allow or Redirect. Defaults to None.
+	// Passthrough routes don't accept Allow.
+	// +optional
+	InsecureEdgeTerminationPolicy DevfileRegistryInsecureEdgeTerminationPolicy `json:"insecureEdgeTerminationPolicy,omitempty"`
+
+	// Issuer of the cert-manager Certificate requested for the ingress hostname, when no secretName is set.
+	// Requires cert-manager to be installed on the cluster.
+	// +optional
+	Issuer DevfileRegistryTLSIssuer `json:"issuer,omitempty"`
+
+	// Instructs the operator to generate its own CA and a serving certificate for the registry, when neither
+	// secretName nor issuer is set. The CA is published in the <name>-ca-bundle ConfigMap so that clients can verify
+	// the registry. Meant for development clusters without cert-manager. Routes present the router's certificate, so
+	// on OpenShift it requires inPod with re-encrypting routes; it can't be used with Gateway exposure.
+	// +optional
+	SelfSigned bool `json:"selfSigned,omitempty"`
+
+	// Instructs the registry containers to serve TLS themselves, so that traffic from the router or ingress controller
+	// to the registry is encrypted too. The containers use the certificate from secretName, issuer or selfSigned,
+	// which must be valid for the service DNS names. Routes then re-encrypt by default. Not supported with the
+	// Gateway exposure type.
+	// +optional
+	InPod bool `json:"inPod,omitempty"`
+}
+
+// DevfileRegistryTLSTermination is where the routes exposing the DevfileRegistry terminate TLS
+// +kubebuilder:validation:Enum=edge;reencrypt;passthrough
+type DevfileRegistryTLSTermination string
+
+const (
+	// TLSTerminationEdge terminates TLS at the router, which talks to the registry over plain HTTP
+	TLSTerminationEdge DevfileRegistryTLSTermination = "edge"
+	// TLSTerminationReencrypt terminates TLS at the router, which opens a new TLS connection to the registry
+	TLSTerminationReencrypt DevfileRegistryTLSTermination = "reencrypt"
+	// TLSTerminationPassthrough sends the TLS connection through to the registry
+	TLSTerminationPassthrough DevfileRegistryTLSTermination = "passthrough"
+)
+
+// DevfileRegistryInsecureEdgeTerminationPolicy is how the routes exposing the DevfileRegistry handle plain HTTP requests
+// +kubebuilder:validation:Enum=None;Allow;Redirect
+type DevfileRegistryInsecureEdgeTerminationPolicy string
+
+const (
+	// InsecureEdgeTerminationPolicyNone rejects plain HTTP requests
+	InsecureEdgeTerminationPolicyNone DevfileRegistryInsecureEdgeTerminationPolicy = "None"
+	// InsecureEdgeTerminationPolicyAllow serves plain HTTP requests too
+	InsecureEdgeTerminationPolicyAllow DevfileRegistryInsecureEdgeTerminationPolicy = "Allow"
+	// InsecureEdgeTerminationPolicyRedirect redirects plain HTTP requests to HTTPS
+	InsecureEdgeTerminationPolicyRedirect DevfileRegistryInsecureEdgeTerminationPolicy = "Redirect"
+)
+
+// DevfileRegistryTLSIssuer references the cert-manager issuer signing the DevfileRegistry's certificate
+type DevfileRegistryTLSIssuer struct {
+	// Name of the Issuer or ClusterIssuer
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// Kind of the issuer: an Issuer in the namespace of the DevfileRegistry, or a ClusterIssuer. Defaults to Issuer.
+	// +kubebuilder:validation:Enum=Issuer;ClusterIssuer
+	// +optional
+	Kind string `json:"kind,omitempty"`
+}
+
+// DevfileRegistryExposure defines how the DevfileRegistry is exposed outside of the cluster
+type DevfileRegistryExposure struct {
+	// How the registry is exposed: through an OpenShift Route, an Ingress, a Gateway API HTTPRoute, a NodePort or
+	// LoadBalancer Service, or not at all with None, which leaves the registry reachable only from inside the cluster.
+	// Detected from the cluster if not set: Route on OpenShift, Gateway when a gateway is configured and the cluster
+	// serves the Gateway API, Ingress otherwise.
+	// +optional
+	Type DevfileRegistryExposureType `json:"type,omitempty"`
+
+	// Configures the ingress used to expose the registry on Kubernetes
+	// +optional
+	Ingress DevfileRegistryIngress `json:"ingress,omitempty"`
+
+	// Configures the HTTPRoute used to expose the registry through a Gateway
+	// +optional
+	Gateway DevfileRegistryGateway `json:"gateway,omitempty"`
+}
+
+// DevfileRegistryExposureType is the kind of resource exposing the DevfileRegistry
+// +kubebuilder:validation:Enum=None;NodePort;LoadBalancer;Ingress;Route;Gateway
+type DevfileRegistryExposureType string
+
+const (
+	// ExposureTypeNone only exposes the registry inside the cluster, through its ClusterIP Service
+	ExposureTypeNone DevfileRegistryExposureType = "None"
+	// ExposureTypeNodePort exposes the registry on a port of every node, through a NodePort Service
+	ExposureTypeNodePort DevfileRegistryExposureType = "NodePort"
+	// ExposureTypeLoadBalancer exposes the registry through a LoadBalancer Service
+	ExposureTypeLoadBalancer DevfileRegistryExposureType = "LoadBalancer"
+	// ExposureTypeRoute exposes the registry through OpenShift Routes
+	ExposureTypeRoute DevfileRegistryExposureType = "Route"
+	// ExposureTypeIngress exposes the registry through an Ingress
+	ExposureTypeIngress DevfileRegistryExposureType = "Ingress"
+	// ExposureTypeGateway exposes the registry through a Gateway API HTTPRoute
+	ExposureTypeGateway DevfileRegistryExposureType = "Gateway"
+)
+
+// DevfileRegistryIngress defines the desired state of the ingress exposing the DevfileRegistry
+type DevfileRegistryIngress struct {
+	// Ingress domain for a Kubernetes cluster. This MUST be explicitly specified on Kubernetes. There are no defaults
+	// +optional
+	Domain string `json:"domain,omitempty"`
+
+	// Name of the IngressClass of the ingress controller serving the registry. Uses the cluster's default ingress
+	// class if not set.
+	// +optional
+	ClassName string `json:"className,omitempty"`
+
+	// Annotations added to the ingress, to configure the ingress controller serving the registry
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// DevfileRegistryGateway defines the Gateway the DevfileRegistry's HTTPRoute attaches to
+type DevfileRegistryGateway struct {
+	// Name of the Gateway
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// Namespace of the Gateway. Defaults to the namespace of the DevfileRegistry.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// Name of the Gateway listener to attach to. Attaches to every listener allowing it if not set.
+	// +optional
+	SectionName string `json:"sectionName,omitempty"`
+
+	// Hostname the registry is served under. It must be accepted by the Gateway listener.
+	// +optional
+	Hostname string `json:"hostname,omitempty"`
+}
+
+// DevfileRegistryPhase is a high-level summary of where the DevfileRegistry is in its lifecycle
+type DevfileRegistryPhase string
+
+const (
+	// DevfileRegistryPhasePending means the operator has not yet created the registry's resources
+	DevfileRegistryPhasePending DevfileRegistryPhase = "Pending"
+	// DevfileRegistryPhaseDeploying means the registry's resources exist, but the registry is not yet serving requests
+	DevfileRegistryPhaseDeploying DevfileRegistryPhase = "Deploying"
+	// DevfileRegistryPhaseReady means the registry is deployed and reachable at status.url
+	DevfileRegistryPhaseReady DevfileRegistryPhase = "Ready"
+	// DevfileRegistryPhaseFailed means the operator hit an error reconciling one of the registry's resources
+	DevfileRegistryPhaseFailed DevfileRegistryPhase = "Failed"
+	// DevfileRegistryPhaseDeleting means the registry is being deleted, and its storage is being reclaimed
+	DevfileRegistryPhaseDeleting DevfileRegistryPhase = "Deleting"
+)
+
+// Condition types reported in the DevfileRegistry status
+const (
+	// ConditionServiceReady indicates whether the registry's Service exists
+	ConditionServiceReady = "ServiceReady"
+	// ConditionStorageReady indicates whether the registry's storage is provisioned, or not needed
+	ConditionStorageReady = "StorageReady"
+	// ConditionDeploymentAvailable indicates whether the registry's Deployment has minimum availability
+	ConditionDeploymentAvailable = "DeploymentAvailable"
+	// ConditionExposed indicates whether the registry is exposed as requested by spec.exposure.type
+	ConditionExposed = "Exposed"
+	// ConditionServerReachable indicates whether the registry server responded on its URL
+	ConditionServerReachable = "ServerReachable"
+	// ConditionGarbageCollection indicates whether the garbage collection of the OCI registry is scheduled, and
+	// whether it last succeeded. It's not part of the Ready condition.
+	ConditionGarbageCollection = "GarbageCollection"
+	// ConditionStorageNearlyFull is true when more of the persistent volume is used than
+	// spec.storage.usageAlertThreshold allows. It's not part of the Ready condition.
+	ConditionStorageNearlyFull = "StorageNearlyFull"
+	// ConditionReady summarizes the other conditions, and is true once the registry is fully operational
+	ConditionReady = "Ready"
+	// ConditionStorageReclaimed is only reported once the registry is being deleted, and indicates whether its
+	// storage was reclaimed according to spec.storage.reclaimPolicy
+	ConditionStorageReclaimed = "StorageReclaimed"
+)
+
+// DevfileRegistryStatus defines the observed state of DevfileRegistry
+type DevfileRegistryStatus struct {
+	// URL the devfile registry is served from
+	URL string `json:"url"`
+
+	// Phase is a high-level summary of the registry's state
+	// +optional
+	Phase DevfileRegistryPhase `json:"phase,omitempty"`
+
+	// ObservedGeneration is the most recent generation of the DevfileRegistry observed by the operator
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Conditions represent the latest available observations of the registry's state
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// GarbageCollection reports on the garbage collection of the OCI registry
+	// +optional
+	GarbageCollection *DevfileRegistryGarbageCollectionStatus `json:"garbageCollection,omitempty"`
+
+	// Storage reports how much of the persistent volume of the registry is used
+	// +optional
+	Storage *DevfileRegistryStorageStatus `json:"storage,omitempty"`
+}
+
+// DevfileRegistryStorageStatus defines the observed usage of the persistent volume of the registry
+type DevfileRegistryStorageStatus struct {
+	// UsedBytes is the space used on the persistent volume, as reported by the kubelet
+	UsedBytes int64 `json:"usedBytes"`
+
+	// CapacityBytes is the size of the filesystem of the persistent volume, as reported by the kubelet
+	CapacityBytes int64 `json:"capacityBytes"`
+
+	// LastMeasuredTime is when the usage was last measured
+	// +optional
+	LastMeasuredTime *metav1.Time `json:"lastMeasuredTime,omitempty"`
+}
+
+// DevfileRegistryGarbageCollectionStatus defines the observed state of the garbage collection of the OCI registry
+type DevfileRegistryGarbageCollectionStatus struct {
+	// ActiveJob is the Job collecting garbage. The registry is in read-only mode while it's set.
+	// +optional
+	ActiveJob string `json:"activeJob,omitempty"`
+
+	// ReadOnly is true once every registry pod serves in read-only mode, which the active Job waits for before
+	// collecting garbage
+	// +optional
+	ReadOnly bool `json:"readOnly,omitempty"`
+
+	// LastJob is the latest Job that finished collecting garbage
+	// +optional
+	LastJob string `json:"lastJob,omitempty"`
+
+	// LastRunTime is when the latest Job finished
+	// +optional
+	LastRunTime *metav1.Time `json:"lastRunTime,omitempty"`
+
+	// LastRunFailed is true if the latest Job failed
+	// +optional
+	LastRunFailed bool `json:"lastRunFailed,omitempty"`
+
+	// LastFreedBytes is the space the latest Job freed on the persistent volume. It's not reported for S3 storage.
+	// +optional
+	LastFreedBytes *int64 `json:"lastFreedBytes,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:storageversion
+
+// DevfileRegistry is the Schema for the devfileregistries API
+// +kubebuilder:resource:path=devfileregistries,shortName=devreg;dr
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase",description="The lifecycle phase of the Devfile Registry"
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].status",description="Whether the Devfile Registry is ready"
+// +kubebuilder:printcolumn:name="URL",type="string",JSONPath=".status.url",description="The URL for the Devfile Registry"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+type DevfileRegistry struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   DevfileRegistrySpec   `json:"spec,omitempty"`
+	Status DevfileRegistryStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// DevfileRegistryList contains a list of DevfileRegistry
+type DevfileRegistryList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []DevfileRegistry `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&DevfileRegistry{}, &DevfileRegistryList{})
+}
@@ -0,0 +1,97 @@
+//
+// Copyright (c) 2020 Red Hat, Inc.
+// This program and the accompanying materials are made
+// available under the terms of the Eclipse Public License 2.0
+// which is available at https://www.eclipse.org/legal/epl-2.0/
+//
+// SPDX-License-Identifier: EPL-2.0
+//
+// Contributors:
+//   Red Hat, Inc. - initial API and implementation
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DevfileRegistryBackupSpec defines the desired state of DevfileRegistryBackup
+type DevfileRegistryBackupSpec struct {
+	// Name of the DevfileRegistry to back up, in the same namespace. Its content must be stored in a persistent
+	// volume claim.
+	RegistryName string `json:"registryName"`
+
+	// Cron schedule of the backups, e.g. "0 2 * * *". A single snapshot is taken when it's not set.
+	// +optional
+	Schedule string `json:"schedule,omitempty"`
+
+	// Number of snapshots to keep, the oldest ones are deleted first. The snapshots are kept when the
+	// DevfileRegistryBackup is deleted. Defaults to 3.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Retain *int32 `json:"retain,omitempty"`
+
+	// Name of the VolumeSnapshotClass to take the snapshots with. The default class of the CSI driver is used when
+	// it's not set.
+	// +optional
+	VolumeSnapshotClassName *string `json:"volumeSnapshotClassName,omitempty"`
+}
+
+// Condition types reported in the DevfileRegistryBackup status
+const (
+	// ConditionBackupReady indicates whether the latest snapshot of the registry is ready to be restored from
+	ConditionBackupReady = "Ready"
+)
+
+// DevfileRegistryBackupStatus defines the observed state of DevfileRegistryBackup
+type DevfileRegistryBackupStatus struct {
+	// Names of the VolumeSnapshots taken of the registry that are kept, from the newest to the oldest
+	// +optional
+	Snapshots []string `json:"snapshots,omitempty"`
+
+	// When the latest snapshot was taken
+	// +optional
+	LastBackupTime *metav1.Time `json:"lastBackupTime,omitempty"`
+
+	// When the next snapshot is due, for scheduled backups
+	// +optional
+	NextBackupTime *metav1.Time `json:"nextBackupTime,omitempty"`
+
+	// Conditions represent the latest available observations of the backup's state
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+
+// DevfileRegistryBackup is the Schema for the devfileregistrybackups API. It takes VolumeSnapshots of the persistent
+// volume claim of a DevfileRegistry, once or on a schedule.
+// +kubebuilder:resource:path=devfileregistrybackups,shortName=drb
+// +kubebuilder:printcolumn:name="Registry",type="string",JSONPath=".spec.registryName",description="The Devfile Registry backed up"
+// +kubebuilder:printcolumn:name="Schedule",type="string",JSONPath=".spec.schedule",description="The cron schedule of the backups"
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].status",description="Whether the latest snapshot is ready"
+// +kubebuilder:printcolumn:name="Last Backup",type="date",JSONPath=".status.lastBackupTime",description="When the latest snapshot was taken"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+type DevfileRegistryBackup struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   DevfileRegistryBackupSpec   `json:"spec,omitempty"`
+	Status DevfileRegistryBackupStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// DevfileRegistryBackupList contains a list of DevfileRegistryBackup
+type DevfileRegistryBackupList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []DevfileRegistryBackup `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&DevfileRegistryBackup{}, &DevfileRegistryBackupList{})
+}
@@ -0,0 +1,141 @@
+//
+// Copyright (c) 2020 Red Hat, Inc.
+// This program and the accompanying materials are made
+// available under the terms of the Eclipse Public License 2.0
+// which is available at https://www.eclipse.org/legal/epl-2.0/
+//
+// SPDX-License-Identifier: EPL-2.0
+//
+// Contributors:
+//   Red Hat, Inc. - initial API and implementation
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DevfileRegistryExportSpec defines the desired state of DevfileRegistryExport
+type DevfileRegistryExportSpec struct {
+	// Name of the DevfileRegistry to export the stacks of, or to import them into, in the same namespace
+	RegistryName string `json:"registryName"`
+
+	// Export writes every stack of the registry's OCI registry into the archive, Import pushes every stack of the
+	// archive into it.
+	// Defaults to Export.
+	// +optional
+	Mode DevfileRegistryExportMode `json:"mode,omitempty"`
+
+	// Where the archive is written to, or read from
+	Archive DevfileRegistryArchive `json:"archive"`
+}
+
+// DevfileRegistryExportMode is the direction the stacks are copied in
+// +kubebuilder:validation:Enum=Export;Import
+type DevfileRegistryExportMode string
+
+const (
+	// ExportModeExport copies the stacks of the registry into the archive
+	ExportModeExport DevfileRegistryExportMode = "Export"
+	// ExportModeImport copies the stacks of the archive into the registry
+	ExportModeImport DevfileRegistryExportMode = "Import"
+)
+
+// DevfileRegistryArchive defines where an archive of the registry stacks, in the OCI image layout format, is stored.
+// Exactly one of persistentVolumeClaim and s3 must be set.
+type DevfileRegistryArchive struct {
+	// Name of the archive file, or of the object in the bucket.
+	// Defaults to the name of the DevfileRegistryExport with a .tar extension.
+	// +optional
+	FileName string `json:"fileName,omitempty"`
+
+	// Persistent volume claim the archive file is stored in
+	// +optional
+	PersistentVolumeClaim *DevfileRegistryArchivePVC `json:"persistentVolumeClaim,omitempty"`
+
+	// S3-compatible bucket the archive object is stored in. The object is stored under rootDirectory when it's set.
+	// +optional
+	S3 *DevfileRegistryS3Storage `json:"s3,omitempty"`
+}
+
+// DevfileRegistryArchivePVC defines the persistent volume claim an archive is stored in
+type DevfileRegistryArchivePVC struct {
+	// Name of the persistent volume claim, in the same namespace
+	ClaimName string `json:"claimName"`
+}
+
+// DevfileRegistryExportPhase is the lifecycle phase of a DevfileRegistryExport
+type DevfileRegistryExportPhase string
+
+const (
+	// ExportPhasePending means the export waits for its DevfileRegistry to be ready
+	ExportPhasePending DevfileRegistryExportPhase = "Pending"
+	// ExportPhaseRunning means the job copying the stacks is running
+	ExportPhaseRunning DevfileRegistryExportPhase = "Running"
+	// ExportPhaseSucceeded means every stack was copied
+	ExportPhaseSucceeded DevfileRegistryExportPhase = "Succeeded"
+	// ExportPhaseFailed means the stacks couldn't be copied
+	ExportPhaseFailed DevfileRegistryExportPhase = "Failed"
+)
+
+// Condition types reported in the DevfileRegistryExport status
+const (
+	// ConditionExportComplete indicates whether the job copying the stacks is done, and whether it succeeded
+	ConditionExportComplete = "Complete"
+)
+
+// DevfileRegistryExportStatus defines the observed state of DevfileRegistryExport
+type DevfileRegistryExportStatus struct {
+	// The lifecycle phase of the export
+	// +optional
+	Phase DevfileRegistryExportPhase `json:"phase,omitempty"`
+
+	// Name of the job copying the stacks
+	// +optional
+	JobName string `json:"jobName,omitempty"`
+
+	// When the job started
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// When the job succeeded or failed
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// Conditions represent the latest available observations of the export's state
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+
+// DevfileRegistryExport is the Schema for the devfileregistryexports API. It runs a job copying every stack of a
+// DevfileRegistry into an archive, or from an archive into a DevfileRegistry, to migrate registries between clusters.
+// +kubebuilder:resource:path=devfileregistryexports,shortName=dre
+// +kubebuilder:printcolumn:name="Registry",type="string",JSONPath=".spec.registryName",description="The Devfile Registry the stacks are copied from or into"
+// +kubebuilder:printcolumn:name="Mode",type="string",JSONPath=".spec.mode",description="Whether the stacks are exported or imported"
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase",description="The lifecycle phase of the export"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+type DevfileRegistryExport struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   DevfileRegistryExportSpec   `json:"spec,omitempty"`
+	Status DevfileRegistryExportStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// DevfileRegistryExportList contains a list of DevfileRegistryExport
+type DevfileRegistryExportList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []DevfileRegistryExport `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&DevfileRegistryExport{}, &DevfileRegistryExportList{})
+}
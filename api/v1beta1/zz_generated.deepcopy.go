@@ -0,0 +1,687 @@
+// +build !ignore_autogenerated
+
+//
+// Copyright (c) 2020 Red Hat, Inc.
+// This program and the accompanying materials are made
+// available under the terms of the Eclipse Public License 2.0
+// which is available at https://www.eclipse.org/legal/epl-2.0/
+//
+// SPDX-License-Identifier: EPL-2.0
+//
+// Contributors:
+//   Red Hat, Inc. - initial API and implementation
+
+// Code generated by controller-gen. DO NOT EDIT.
+
+package v1beta1
+
+import (
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+)
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DevfileRegistry) DeepCopyInto(out *DevfileRegistry) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DevfileRegistry.
+func (in *DevfileRegistry) DeepCopy() *DevfileRegistry {
+	if in == nil {
+		return nil
+	}
+	out := new(DevfileRegistry)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DevfileRegistry) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DevfileRegistryArchive) DeepCopyInto(out *DevfileRegistryArchive) {
+	*out = *in
+	if in.PersistentVolumeClaim != nil {
+		in, out := &in.PersistentVolumeClaim, &out.PersistentVolumeClaim
+		*out = new(DevfileRegistryArchivePVC)
+		**out = **in
+	}
+	if in.S3 != nil {
+		in, out := &in.S3, &out.S3
+		*out = new(DevfileRegistryS3Storage)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DevfileRegistryArchive.
+func (in *DevfileRegistryArchive) DeepCopy() *DevfileRegistryArchive {
+	if in == nil {
+		return nil
+	}
+	out := new(DevfileRegistryArchive)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DevfileRegistryArchivePVC) DeepCopyInto(out *DevfileRegistryArchivePVC) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DevfileRegistryArchivePVC.
+func (in *DevfileRegistryArchivePVC) DeepCopy() *DevfileRegistryArchivePVC {
+	if in == nil {
+		return nil
+	}
+	out := new(DevfileRegistryArchivePVC)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DevfileRegistryBackup) DeepCopyInto(out *DevfileRegistryBackup) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DevfileRegistryBackup.
+func (in *DevfileRegistryBackup) DeepCopy() *DevfileRegistryBackup {
+	if in == nil {
+		return nil
+	}
+	out := new(DevfileRegistryBackup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DevfileRegistryBackup) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DevfileRegistryBackupList) DeepCopyInto(out *DevfileRegistryBackupList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]DevfileRegistryBackup, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DevfileRegistryBackupList.
+func (in *DevfileRegistryBackupList) DeepCopy() *DevfileRegistryBackupList {
+	if in == nil {
+		return nil
+	}
+	out := new(DevfileRegistryBackupList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DevfileRegistryBackupList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DevfileRegistryBackupSpec) DeepCopyInto(out *DevfileRegistryBackupSpec) {
+	*out = *in
+	if in.Retain != nil {
+		in, out := &in.Retain, &out.Retain
+		*out = new(int32)
+		**out = **in
+	}
+	if in.VolumeSnapshotClassName != nil {
+		in, out := &in.VolumeSnapshotClassName, &out.VolumeSnapshotClassName
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DevfileRegistryBackupSpec.
+func (in *DevfileRegistryBackupSpec) DeepCopy() *DevfileRegistryBackupSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DevfileRegistryBackupSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DevfileRegistryBackupStatus) DeepCopyInto(out *DevfileRegistryBackupStatus) {
+	*out = *in
+	if in.Snapshots != nil {
+		in, out := &in.Snapshots, &out.Snapshots
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastBackupTime != nil {
+		in, out := &in.LastBackupTime, &out.LastBackupTime
+		*out = (*in).DeepCopy()
+	}
+	if in.NextBackupTime != nil {
+		in, out := &in.NextBackupTime, &out.NextBackupTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DevfileRegistryBackupStatus.
+func (in *DevfileRegistryBackupStatus) DeepCopy() *DevfileRegistryBackupStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DevfileRegistryBackupStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DevfileRegistryDevfileIndex) DeepCopyInto(out *DevfileRegistryDevfileIndex) {
+	*out = *in
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(v1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DevfileRegistryDevfileIndex.
+func (in *DevfileRegistryDevfileIndex) DeepCopy() *DevfileRegistryDevfileIndex {
+	if in == nil {
+		return nil
+	}
+	out := new(DevfileRegistryDevfileIndex)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DevfileRegistryExport) DeepCopyInto(out *DevfileRegistryExport) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DevfileRegistryExport.
+func (in *DevfileRegistryExport) DeepCopy() *DevfileRegistryExport {
+	if in == nil {
+		return nil
+	}
+	out := new(DevfileRegistryExport)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DevfileRegistryExport) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DevfileRegistryExportList) DeepCopyInto(out *DevfileRegistryExportList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]DevfileRegistryExport, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DevfileRegistryExportList.
+func (in *DevfileRegistryExportList) DeepCopy() *DevfileRegistryExportList {
+	if in == nil {
+		return nil
+	}
+	out := new(DevfileRegistryExportList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DevfileRegistryExportList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DevfileRegistryExportSpec) DeepCopyInto(out *DevfileRegistryExportSpec) {
+	*out = *in
+	in.Archive.DeepCopyInto(&out.Archive)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DevfileRegistryExportSpec.
+func (in *DevfileRegistryExportSpec) DeepCopy() *DevfileRegistryExportSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DevfileRegistryExportSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DevfileRegistryExportStatus) DeepCopyInto(out *DevfileRegistryExportStatus) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DevfileRegistryExportStatus.
+func (in *DevfileRegistryExportStatus) DeepCopy() *DevfileRegistryExportStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DevfileRegistryExportStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DevfileRegistryExposure) DeepCopyInto(out *DevfileRegistryExposure) {
+	*out = *in
+	in.Ingress.DeepCopyInto(&out.Ingress)
+	out.Gateway = in.Gateway
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DevfileRegistryExposure.
+func (in *DevfileRegistryExposure) DeepCopy() *DevfileRegistryExposure {
+	if in == nil {
+		return nil
+	}
+	out := new(DevfileRegistryExposure)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DevfileRegistryGarbageCollection) DeepCopyInto(out *DevfileRegistryGarbageCollection) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DevfileRegistryGarbageCollection.
+func (in *DevfileRegistryGarbageCollection) DeepCopy() *DevfileRegistryGarbageCollection {
+	if in == nil {
+		return nil
+	}
+	out := new(DevfileRegistryGarbageCollection)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DevfileRegistryGarbageCollectionStatus) DeepCopyInto(out *DevfileRegistryGarbageCollectionStatus) {
+	*out = *in
+	if in.LastRunTime != nil {
+		in, out := &in.LastRunTime, &out.LastRunTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastFreedBytes != nil {
+		in, out := &in.LastFreedBytes, &out.LastFreedBytes
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DevfileRegistryGarbageCollectionStatus.
+func (in *DevfileRegistryGarbageCollectionStatus) DeepCopy() *DevfileRegistryGarbageCollectionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DevfileRegistryGarbageCollectionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DevfileRegistryGateway) DeepCopyInto(out *DevfileRegistryGateway) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DevfileRegistryGateway.
+func (in *DevfileRegistryGateway) DeepCopy() *DevfileRegistryGateway {
+	if in == nil {
+		return nil
+	}
+	out := new(DevfileRegistryGateway)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DevfileRegistryIngress) DeepCopyInto(out *DevfileRegistryIngress) {
+	*out = *in
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DevfileRegistryIngress.
+func (in *DevfileRegistryIngress) DeepCopy() *DevfileRegistryIngress {
+	if in == nil {
+		return nil
+	}
+	out := new(DevfileRegistryIngress)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DevfileRegistryList) DeepCopyInto(out *DevfileRegistryList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]DevfileRegistry, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DevfileRegistryList.
+func (in *DevfileRegistryList) DeepCopy() *DevfileRegistryList {
+	if in == nil {
+		return nil
+	}
+	out := new(DevfileRegistryList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DevfileRegistryList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DevfileRegistryOCIRegistry) DeepCopyInto(out *DevfileRegistryOCIRegistry) {
+	*out = *in
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(v1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	in.Config.DeepCopyInto(&out.Config)
+	if in.GarbageCollection != nil {
+		in, out := &in.GarbageCollection, &out.GarbageCollection
+		*out = new(DevfileRegistryGarbageCollection)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DevfileRegistryOCIRegistry.
+func (in *DevfileRegistryOCIRegistry) DeepCopy() *DevfileRegistryOCIRegistry {
+	if in == nil {
+		return nil
+	}
+	out := new(DevfileRegistryOCIRegistry)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DevfileRegistryOCIRegistryConfig) DeepCopyInto(out *DevfileRegistryOCIRegistryConfig) {
+	*out = *in
+	if in.StorageHealthCheckInterval != nil {
+		in, out := &in.StorageHealthCheckInterval, &out.StorageHealthCheckInterval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DevfileRegistryOCIRegistryConfig.
+func (in *DevfileRegistryOCIRegistryConfig) DeepCopy() *DevfileRegistryOCIRegistryConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(DevfileRegistryOCIRegistryConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DevfileRegistryS3Storage) DeepCopyInto(out *DevfileRegistryS3Storage) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DevfileRegistryS3Storage.
+func (in *DevfileRegistryS3Storage) DeepCopy() *DevfileRegistryS3Storage {
+	if in == nil {
+		return nil
+	}
+	out := new(DevfileRegistryS3Storage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DevfileRegistrySpec) DeepCopyInto(out *DevfileRegistrySpec) {
+	*out = *in
+	in.DevfileIndex.DeepCopyInto(&out.DevfileIndex)
+	in.OCIRegistry.DeepCopyInto(&out.OCIRegistry)
+	in.Storage.DeepCopyInto(&out.Storage)
+	in.TLS.DeepCopyInto(&out.TLS)
+	in.Exposure.DeepCopyInto(&out.Exposure)
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DevfileRegistrySpec.
+func (in *DevfileRegistrySpec) DeepCopy() *DevfileRegistrySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DevfileRegistrySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DevfileRegistryStatus) DeepCopyInto(out *DevfileRegistryStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.GarbageCollection != nil {
+		in, out := &in.GarbageCollection, &out.GarbageCollection
+		*out = new(DevfileRegistryGarbageCollectionStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Storage != nil {
+		in, out := &in.Storage, &out.Storage
+		*out = new(DevfileRegistryStorageStatus)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DevfileRegistryStatus.
+func (in *DevfileRegistryStatus) DeepCopy() *DevfileRegistryStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DevfileRegistryStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DevfileRegistryStorage) DeepCopyInto(out *DevfileRegistryStorage) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	out.S3 = in.S3
+	if in.StorageClassName != nil {
+		in, out := &in.StorageClassName, &out.StorageClassName
+		*out = new(string)
+		**out = **in
+	}
+	if in.AccessModes != nil {
+		in, out := &in.AccessModes, &out.AccessModes
+		*out = make([]v1.PersistentVolumeAccessMode, len(*in))
+		copy(*out, *in)
+	}
+	if in.UsageAlertThreshold != nil {
+		in, out := &in.UsageAlertThreshold, &out.UsageAlertThreshold
+		*out = new(int32)
+		**out = **in
+	}
+	if in.AutoExpansion != nil {
+		in, out := &in.AutoExpansion, &out.AutoExpansion
+		*out = new(DevfileRegistryStorageAutoExpansion)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DevfileRegistryStorage.
+func (in *DevfileRegistryStorage) DeepCopy() *DevfileRegistryStorage {
+	if in == nil {
+		return nil
+	}
+	out := new(DevfileRegistryStorage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DevfileRegistryStorageAutoExpansion) DeepCopyInto(out *DevfileRegistryStorageAutoExpansion) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DevfileRegistryStorageAutoExpansion.
+func (in *DevfileRegistryStorageAutoExpansion) DeepCopy() *DevfileRegistryStorageAutoExpansion {
+	if in == nil {
+		return nil
+	}
+	out := new(DevfileRegistryStorageAutoExpansion)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DevfileRegistryStorageStatus) DeepCopyInto(out *DevfileRegistryStorageStatus) {
+	*out = *in
+	if in.LastMeasuredTime != nil {
+		in, out := &in.LastMeasuredTime, &out.LastMeasuredTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DevfileRegistryStorageStatus.
+func (in *DevfileRegistryStorageStatus) DeepCopy() *DevfileRegistryStorageStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DevfileRegistryStorageStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DevfileRegistryTLS) DeepCopyInto(out *DevfileRegistryTLS) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	out.Issuer = in.Issuer
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DevfileRegistryTLS.
+func (in *DevfileRegistryTLS) DeepCopy() *DevfileRegistryTLS {
+	if in == nil {
+		return nil
+	}
+	out := new(DevfileRegistryTLS)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DevfileRegistryTLSIssuer) DeepCopyInto(out *DevfileRegistryTLSIssuer) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DevfileRegistryTLSIssuer.
+func (in *DevfileRegistryTLSIssuer) DeepCopy() *DevfileRegistryTLSIssuer {
+	if in == nil {
+		return nil
+	}
+	out := new(DevfileRegistryTLSIssuer)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -0,0 +1,38 @@
+//
+// Copyright (c) 2020 Red Hat, Inc.
+// This program and the accompanying materials are made
+// available under the terms of the Eclipse Public License 2.0
+// which is available at https://www.eclipse.org/legal/epl-2.0/
+//
+// SPDX-License-Identifier: EPL-2.0
+//
+// Contributors:
+//   Red Hat, Inc. - initial API and implementation
+
+package controllers
+
+import (
+	"context"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+// fieldManager is the field manager the operator uses when applying the resources it owns. Fields it sets belong to it,
+// so changes made to them by anyone else are reverted on the next reconcile.
+const fieldManager = "registry-operator"
+
+// applyObject creates or updates obj through server-side apply, so that the object on the cluster matches the desired
+// state generated from the DevfileRegistry. On success, obj is updated with the object returned by the API server.
+func (r *DevfileRegistryReconciler) applyObject(ctx context.Context, obj client.Object) error {
+	// Apply requests need the apiVersion and kind, which the generated objects don't set
+	gvk, err := apiutil.GVKForObject(obj, r.Scheme)
+	if err != nil {
+		return err
+	}
+	obj.GetObjectKind().SetGroupVersionKind(gvk)
+	obj.SetManagedFields(nil)
+	obj.SetResourceVersion("")
+
+	return r.Patch(ctx, obj, client.Apply, client.FieldOwner(fieldManager), client.ForceOwnership)
+}
@@ -13,22 +13,37 @@ package controllers
 
 import (
 	"context"
+	"net/http"
 	"time"
 
 	"github.com/go-logr/logr"
 	routev1 "github.com/openshift/api/route/v1"
 	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
 	corev1 "k8s.io/api/core/v1"
-	"k8s.io/api/extensions/v1beta1"
+	networkingv1 "k8s.io/api/networking/v1"
+	networkingv1beta1 "k8s.io/api/networking/v1beta1"
+	policyv1 "k8s.io/api/policy/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
-	registryv1alpha1 "github.com/devfile/registry-operator/api/v1alpha1"
+	registryv1beta1 "github.com/devfile/registry-operator/api/v1beta1"
 	"github.com/devfile/registry-operator/pkg/cluster"
 	"github.com/devfile/registry-operator/pkg/config"
+	"github.com/devfile/registry-operator/pkg/metrics"
 	"github.com/devfile/registry-operator/pkg/registry"
 	"github.com/devfile/registry-operator/pkg/util"
 )
@@ -36,8 +51,20 @@ import (
 // DevfileRegistryReconciler reconciles a DevfileRegistry object
 type DevfileRegistryReconciler struct {
 	client.Client
-	Log    logr.Logger
-	Scheme *runtime.Scheme
+	Log      logr.Logger
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+	// HTTPClient is used to probe the devfile registry servers
+	HTTPClient *http.Client
+	// APIReader reads objects the manager doesn't cache straight from the API server
+	APIReader client.Reader
+	// RESTClient reaches the kubelets through the API server, to measure the storage usage of the registries
+	RESTClient rest.Interface
+	// StorageUsageInterval is how often the storage usage of a registry is measured, it isn't measured when zero
+	StorageUsageInterval time.Duration
+
+	// probeClients holds the clients probing the registries serving the operator's self-signed certificate
+	probeClients util.RootCAClients
 }
 
 // +kubebuilder:rbac:groups=registry.devfile.io,resources=devfileregistries,verbs=get;list;watch;create;update;patch;delete
@@ -45,15 +72,24 @@ type DevfileRegistryReconciler struct {
 // +kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=core,resources=services;persistentvolumeclaims,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;
-// +kubebuilder:rbac:groups=extensions,resources=ingresses,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+// +kubebuilder:rbac:groups=core,resources=nodes,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=secrets;configmaps,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=batch,resources=cronjobs,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch
+// +kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=storage.k8s.io,resources=storageclasses,verbs=get;list;watch
+// +kubebuilder:rbac:groups=snapshot.storage.k8s.io,resources=volumesnapshots,verbs=get;list;watch;create
+// +kubebuilder:rbac:groups=networking.k8s.io,resources=ingresses,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=cert-manager.io,resources=certificates,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=httproutes,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=route.openshift.io,resources=routes;routes/custom-host,verbs=get;list;watch;create;update;patch;delete
 
-func (r *DevfileRegistryReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
-	ctx := context.Background()
+func (r *DevfileRegistryReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, reterr error) {
 	log := r.Log.WithValues("devfileregistry", req.NamespacedName)
 
 	// Fetch the DevfileRegistry instance
-	devfileRegistry := &registryv1alpha1.DevfileRegistry{}
+	devfileRegistry := &registryv1beta1.DevfileRegistry{}
 	err := r.Get(ctx, req.NamespacedName, devfileRegistry)
 	if err != nil {
 		if errors.IsNotFound(err) {
@@ -61,112 +97,255 @@ func (r *DevfileRegistryReconciler) Reconcile(req ctrl.Request) (ctrl.Result, er
 			// Owned objects are automatically garbage collected. For additional cleanup logic use finalizers.
 			// Return and don't requeue
 			log.Info("DevfileRegistry resource not found. Ignoring since object must be deleted")
+			metrics.DeleteRegistry(req.Namespace, req.Name)
+			r.probeClients.Delete(req.NamespacedName.String())
 			return ctrl.Result{}, nil
 		}
 		// Error reading the object - requeue the request.
 		log.Error(err, "Failed to get DevfileRegistry")
+		metrics.IncReconcileErrors(req.Namespace, req.Name, metrics.StepFetch)
+		return ctrl.Result{}, err
+	}
+
+	// Conditions are recorded on the CR as each resource is reconciled, write them back however this reconcile ends
+	oldStatus := devfileRegistry.Status.DeepCopy()
+	defer func() {
+		// The DevfileRegistry is gone once its finalizer is removed, so there's no status left to update
+		if err := r.updateStatus(ctx, devfileRegistry, oldStatus); client.IgnoreNotFound(err) != nil {
+			log.Error(err, "Failed to update DevfileRegistry status")
+			r.Recorder.Eventf(devfileRegistry, corev1.EventTypeWarning, eventReasonStatusUpdateFailed, "Failed to update the status: %v", err)
+			metrics.IncReconcileErrors(devfileRegistry.Namespace, devfileRegistry.Name, metrics.StepStatus)
+			if reterr == nil {
+				reterr = err
+			}
+		}
+	}()
+
+	// A DevfileRegistry being deleted only needs its storage reclaimed, its other resources are garbage collected
+	if !devfileRegistry.DeletionTimestamp.IsZero() {
+		result, err := r.finalizeDevfileRegistry(ctx, devfileRegistry)
+		if err != nil {
+			log.Error(err, "Failed to reclaim the DevfileRegistry storage")
+			metrics.IncReconcileErrors(devfileRegistry.Namespace, devfileRegistry.Name, metrics.StepFinalize)
+		}
+		return *result, err
+	}
+
+	if err := r.ensureFinalizer(ctx, devfileRegistry); err != nil {
+		log.Error(err, "Failed to add the finalizer to the DevfileRegistry")
+		metrics.IncReconcileErrors(devfileRegistry.Namespace, devfileRegistry.Name, metrics.StepFinalize)
 		return ctrl.Result{}, err
 	}
 
 	// Generate labels for any subresources generated by the operator
 	labels := registry.LabelsForDevfileRegistry(devfileRegistry.Name)
 
-	// Check if the service already exists, if not create a new one
-	result, err := r.ensureService(ctx, devfileRegistry, labels)
+	result, err := r.reconcileChild(ctx, devfileRegistry, r.serviceResource(devfileRegistry, labels))
+	if result != nil {
+		return *result, err
+	}
+
+	// Generate the certificates of the devfile registry, or delete them if it no longer uses self-signed certificates
+	for _, child := range r.selfSignedResources(devfileRegistry, labels) {
+		result, err = r.reconcileChild(ctx, devfileRegistry, child)
+		if result != nil {
+			return *result, err
+		}
+	}
+
+	// If storage is enabled, the persistent volume claim has to exist before the deployment mounts it
+	pvc := r.pvcResource(devfileRegistry, labels)
+	if !pvc.disabled {
+		// The claim can't be created without a size, wait for the spec to be fixed
+		if _, err := registry.ParsePVCSize(devfileRegistry); err != nil {
+			log.Error(err, "Invalid storage size")
+			setFailedCondition(devfileRegistry, registryv1beta1.ConditionStorageReady, err)
+			return ctrl.Result{}, nil
+		}
+		result, err = r.reconcileChild(ctx, devfileRegistry, pvc)
+		if result != nil {
+			return *result, err
+		}
+	}
+
+	// A running garbage collection switches the OCI registry to read-only mode through its configuration
+	if err := r.trackGarbageCollection(ctx, devfileRegistry); err != nil {
+		log.Error(err, "Failed to track the garbage collection jobs")
+		metrics.IncReconcileErrors(devfileRegistry.Namespace, devfileRegistry.Name, metrics.StepGarbageCollection)
+		setFailedCondition(devfileRegistry, registryv1beta1.ConditionGarbageCollection, err)
+		return ctrl.Result{}, err
+	}
+
+	// The OCI registry configuration has to exist before the deployment mounts it
+	result, err = r.reconcileChild(ctx, devfileRegistry, r.ociConfigResource(devfileRegistry, labels))
 	if result != nil {
 		return *result, err
 	}
 
-	// If storage is enabled, create a persistent volume claim
-	if registry.IsStorageEnabled(devfileRegistry) {
-		// Check if the persistentvolumeclaim already exists, if not create a new one
-		result, err = r.ensurePVC(ctx, devfileRegistry, labels)
+	// Replicas share the HTTP secret of the OCI registry, which has to exist before the deployment references it
+	httpSecret := r.httpSecretResource(devfileRegistry, labels)
+	if !httpSecret.disabled {
+		result, err = r.reconcileChild(ctx, devfileRegistry, httpSecret)
 		if result != nil {
 			return *result, err
 		}
 	}
 
-	result, err = r.ensureDeployment(ctx, devfileRegistry, labels)
+	result, err = r.reconcileChild(ctx, devfileRegistry, r.podDisruptionBudgetResource(devfileRegistry, labels))
+	if result != nil {
+		return *result, err
+	}
+
+	// The pods are rolled when the certificate they serve is renewed
+	servingSecret, err := r.getServingTLSSecret(ctx, devfileRegistry)
+	if err != nil {
+		log.Error(err, "Failed to get the TLS secret of the deployment")
+		metrics.IncReconcileErrors(devfileRegistry.Namespace, devfileRegistry.Name, metrics.StepDeployment)
+		setFailedCondition(devfileRegistry, registryv1beta1.ConditionDeploymentAvailable, err)
+		return ctrl.Result{}, err
+	}
+	result, err = r.reconcileChild(ctx, devfileRegistry, r.deploymentResource(devfileRegistry, servingSecret, labels))
+	if result != nil {
+		return *result, err
+	}
+
+	// A single replica no longer needs the HTTP secret once the deployment stopped referencing it
+	if httpSecret.disabled {
+		result, err = r.reconcileChild(ctx, devfileRegistry, httpSecret)
+		if result != nil {
+			return *result, err
+		}
+	}
+
+	// Garbage collection jobs mount the registry's storage and configuration
+	result, err = r.reconcileChild(ctx, devfileRegistry, r.gcCronJobResource(devfileRegistry, labels))
 	if result != nil {
 		return *result, err
 	}
 
 	// Check to see if there's an old PVC that needs to be deleted
 	// Has to happen AFTER the deployment has been updated to remove the volume mount
-	if !registry.IsStorageEnabled(devfileRegistry) {
-		err = r.deletePVCIfNeeded(ctx, devfileRegistry)
+	if pvc.disabled {
+		result, err = r.reconcileChild(ctx, devfileRegistry, pvc)
+		if result != nil {
+			return *result, err
+		}
+	}
+
+	// Create/update the resources exposing the devfile registry, and work out the URL it's served at
+	var devfileRegistryServer string
+	exposureType := registry.GetExposureType(devfileRegistry)
+	switch exposureType {
+	case registryv1beta1.ExposureTypeNone:
+		devfileRegistryServer = registry.GetServiceURL(devfileRegistry)
+		setCondition(devfileRegistry, registryv1beta1.ConditionExposed, metav1.ConditionTrue, reasonInClusterOnly, "The devfile registry is only exposed inside the cluster, through Service "+registry.ServiceName(devfileRegistry.Name))
+	case registryv1beta1.ExposureTypeNodePort, registryv1beta1.ExposureTypeLoadBalancer:
+		devfileRegistryServer, err = r.getServiceExposureURL(ctx, devfileRegistry, exposureType)
 		if err != nil {
+			log.Error(err, "Failed to get the address of the Service")
+			metrics.IncReconcileErrors(devfileRegistry.Namespace, devfileRegistry.Name, metrics.StepService)
+			setFailedCondition(devfileRegistry, registryv1beta1.ConditionExposed, err)
+			return ctrl.Result{}, err
+		}
+		if devfileRegistryServer == "" {
+			// The service is owned by the controller, so it's reconciled again once the address is allocated
+			setCondition(devfileRegistry, registryv1beta1.ConditionExposed, metav1.ConditionFalse, reasonHostPending, "Waiting for an address to be allocated to "+string(exposureType)+" Service "+registry.ServiceName(devfileRegistry.Name))
+			return ctrl.Result{}, nil
+		}
+		setCondition(devfileRegistry, registryv1beta1.ConditionExposed, metav1.ConditionTrue, reasonReconciled, "The devfile registry is exposed at "+devfileRegistryServer+" by "+string(exposureType)+" Service "+registry.ServiceName(devfileRegistry.Name))
+	case registryv1beta1.ExposureTypeRoute:
+		tlsSecret, err := r.getRouteTLSSecret(ctx, devfileRegistry)
+		if err != nil {
+			log.Error(err, "Failed to get the TLS secret of the routes")
+			r.Recorder.Eventf(devfileRegistry, corev1.EventTypeWarning, eventReasonGetFailed, "Failed to get TLS Secret %s: %v", devfileRegistry.Spec.TLS.SecretName, err)
+			metrics.IncReconcileErrors(devfileRegistry.Namespace, devfileRegistry.Name, metrics.StepDevfilesRoute)
+			setFailedCondition(devfileRegistry, registryv1beta1.ConditionExposed, err)
 			return ctrl.Result{}, err
 		}
-	}
 
-	// Create/update the ingress/route for the devfile registry
-	hostname := devfileRegistry.Spec.K8s.IngressDomain
-	if config.ControllerCfg.IsOpenShift() {
 		// Check if the route exposing the devfile index exists
-		result, err = r.ensureDevfilesRoute(ctx, devfileRegistry, labels)
+		result, err = r.reconcileChild(ctx, devfileRegistry, r.devfilesRouteResource(devfileRegistry, tlsSecret, labels))
 		if result != nil {
 			return *result, err
 		}
 
 		// If the route hostname was autodiscovered by OpenShift, need to retrieve the generated hostname.
 		// This is so that we can re-use the hostname in the second route and allows us to expose both routes under the same hostname
+		hostname := devfileRegistry.Spec.Exposure.Ingress.Domain
 		if hostname == "" {
 			// Get the hostname of the devfiles route
 			devfilesRoute := &routev1.Route{}
-			err = r.Get(ctx, types.NamespacedName{Name: devfileRegistry.Name + "-devfiles", Namespace: devfileRegistry.Namespace}, devfilesRoute)
+			err = r.Get(ctx, types.NamespacedName{Name: registry.DevfilesRouteName(devfileRegistry.Name), Namespace: devfileRegistry.Namespace}, devfilesRoute)
 			if err != nil {
 				// Log an error, but requeue, as the controller's cached kube client likely hasn't registered the new route yet.
 				// See https://github.com/operator-framework/operator-sdk/issues/4013#issuecomment-707267616 for an explanation on why we requeue rather than error out here
 				log.Error(err, "Failed to get Route")
+				setCondition(devfileRegistry, registryv1beta1.ConditionExposed, metav1.ConditionFalse, reasonHostPending, "Waiting for the hostname of Route "+registry.DevfilesRouteName(devfileRegistry.Name))
 				return ctrl.Result{Requeue: true}, nil
 			}
 			hostname = devfilesRoute.Spec.Host
 		}
 
 		// Check if the route exposing the devfile index exists
-		result, err = r.ensureOCIRoute(ctx, devfileRegistry, hostname, labels)
+		result, err = r.reconcileChild(ctx, devfileRegistry, r.ociRouteResource(devfileRegistry, hostname, tlsSecret, labels))
 		if result != nil {
 			return *result, err
 		}
-	} else {
+		devfileRegistryServer = registry.GetHostURL(devfileRegistry, hostname)
+	case registryv1beta1.ExposureTypeGateway:
+		// Create/update the HTTPRoute attaching the devfile registry to the gateway
+		result, err = r.reconcileChild(ctx, devfileRegistry, r.httpRouteResource(devfileRegistry, labels))
+		if result != nil {
+			return *result, err
+		}
+		devfileRegistryServer = registry.GetHostURL(devfileRegistry, devfileRegistry.Spec.Exposure.Gateway.Hostname)
+	default:
 		// Create/update the ingress for the devfile registry
-		hostname = registry.GetDevfileRegistryIngress(devfileRegistry)
-		result, err = r.ensureIngress(ctx, devfileRegistry, hostname, labels)
+		hostname := registry.GetDevfileRegistryIngress(devfileRegistry)
+
+		// Request a certificate for the hostname from cert-manager, and only serve it once it's issued
+		if config.ControllerCfg.HasCertManager() {
+			result, err = r.reconcileChild(ctx, devfileRegistry, r.certificateResource(devfileRegistry, hostname, labels))
+			if result != nil {
+				return *result, err
+			}
+			if registry.IsCertificateRequested(devfileRegistry) && !meta.IsStatusConditionTrue(devfileRegistry.Status.Conditions, registryv1beta1.ConditionExposed) {
+				// The certificate is owned by the controller, so it's reconciled again once the certificate is issued
+				return ctrl.Result{}, nil
+			}
+		}
+
+		result, err = r.reconcileChild(ctx, devfileRegistry, r.ingressResource(devfileRegistry, hostname, labels))
 		if result != nil {
 			return *result, err
 		}
+		devfileRegistryServer = registry.GetIngressURL(devfileRegistry, hostname)
 	}
 
-	var devfileRegistryServer string
-	if registry.IsTLSEnabled(devfileRegistry) {
-		devfileRegistryServer = "https://" + hostname
-	} else {
-		devfileRegistryServer = "http://" + hostname
+	// Remove what exposed the devfile registry before the exposure type was changed
+	for _, child := range r.staleExposureResources(devfileRegistry, exposureType) {
+		result, err = r.reconcileChild(ctx, devfileRegistry, child)
+		if result != nil {
+			return *result, err
+		}
 	}
 
-	if devfileRegistry.Status.URL != devfileRegistryServer {
-		// Check to see if the registry is active, and if so, update the status to reflect the URL
-
-		err = util.WaitForServer(devfileRegistryServer, 30*time.Second)
-		if err != nil {
-			log.Error(err, "Devfile registry server failed to start after 30 seconds, requeing...")
-			return ctrl.Result{Requeue: true}, err
-		}
+	// Check to see if the registry is active, and if so, update the status to reflect the URL
+	result, err = r.ensureServerReachable(ctx, devfileRegistry, devfileRegistryServer)
+	if result != nil {
+		return *result, err
+	}
 
-		// Update the status
-		devfileRegistry.Status.URL = devfileRegistryServer
-		err := r.Status().Update(ctx, devfileRegistry)
-		if err != nil {
-			log.Error(err, "Failed to update DevfileRegistry status")
-			return ctrl.Result{Requeue: true}, err
-		}
+	// Measure how full the registry storage is, and expand it if it's nearly full
+	result, err = r.ensureStorageUsage(ctx, devfileRegistry)
+	if result != nil {
+		return *result, err
 	}
 
 	return ctrl.Result{}, nil
 }
 
-func (r *DevfileRegistryReconciler) SetupWithManager(mgr ctrl.Manager) error {
+func (r *DevfileRegistryReconciler) SetupWithManager(mgr ctrl.Manager, maxConcurrentReconciles int) error {
 	// Check if we're running on OpenShift
 	isOS, err := cluster.IsOpenShift()
 	if err != nil {
@@ -174,18 +353,96 @@ func (r *DevfileRegistryReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	}
 	config.ControllerCfg.SetIsOpenShift(isOS)
 
+	// Check if volume snapshots can be taken of the registry storage
+	volumeSnapshotAPIVersion, err := cluster.VolumeSnapshotAPIVersion()
+	if err != nil {
+		return err
+	}
+	config.ControllerCfg.SetVolumeSnapshotAPIVersion(volumeSnapshotAPIVersion)
+
+	// Check which API ingresses are served through
+	hasIngressV1, err := cluster.HasIngressV1()
+	if err != nil {
+		return err
+	}
+	config.ControllerCfg.SetHasIngressV1(hasIngressV1)
+
+	// Check which API pod disruption budgets are served through
+	hasPDBV1, err := cluster.HasPodDisruptionBudgetV1()
+	if err != nil {
+		return err
+	}
+	config.ControllerCfg.SetHasPodDisruptionBudgetV1(hasPDBV1)
+
+	// Check which API cron jobs are served through
+	hasCronJobV1, err := cluster.HasCronJobV1()
+	if err != nil {
+		return err
+	}
+	config.ControllerCfg.SetHasCronJobV1(hasCronJobV1)
+
+	// Check if HTTPRoutes can be attached to Gateways
+	gatewayAPIVersion, err := cluster.GatewayAPIVersion()
+	if err != nil {
+		return err
+	}
+	config.ControllerCfg.SetGatewayAPIVersion(gatewayAPIVersion)
+
+	// Check if certificates can be requested from cert-manager
+	hasCertManager, err := cluster.HasCertManager()
+	if err != nil {
+		return err
+	}
+	config.ControllerCfg.SetHasCertManager(hasCertManager)
+
 	builder := ctrl.NewControllerManagedBy(mgr).
-		For(&registryv1alpha1.DevfileRegistry{}).
+		For(&registryv1beta1.DevfileRegistry{}).
 		Owns(&appsv1.Deployment{}).
 		Owns(&corev1.Service{}).
 		Owns(&corev1.PersistentVolumeClaim{}).
-		Owns(&v1beta1.Ingress{})
+		Owns(&corev1.Secret{}).
+		Owns(&corev1.ConfigMap{}).
+		Watches(&source.Kind{Type: &corev1.Secret{}}, handler.EnqueueRequestsFromMapFunc(r.registriesForSecret)).
+		Watches(&source.Kind{Type: &batchv1.Job{}}, handler.EnqueueRequestsFromMapFunc(r.registriesForGCJob)).
+		WithOptions(controller.Options{MaxConcurrentReconciles: maxConcurrentReconciles})
+
+	if config.ControllerCfg.HasIngressV1() {
+		builder.Owns(&networkingv1.Ingress{})
+	} else {
+		builder.Owns(&networkingv1beta1.Ingress{})
+	}
+
+	if config.ControllerCfg.HasPodDisruptionBudgetV1() {
+		builder.Owns(&policyv1.PodDisruptionBudget{})
+	} else {
+		builder.Owns(&policyv1beta1.PodDisruptionBudget{})
+	}
+
+	if config.ControllerCfg.HasCronJobV1() {
+		builder.Owns(&batchv1.CronJob{})
+	} else {
+		builder.Owns(&batchv1beta1.CronJob{})
+	}
 
 	// If on OpenShift, mark routes as owned by the controller
 	if config.ControllerCfg.IsOpenShift() {
 		builder.Owns(&routev1.Route{})
 	}
 
+	// If the Gateway API is served, mark HTTPRoutes as owned by the controller
+	if config.ControllerCfg.GatewayAPIVersion() != "" {
+		httpRoute := &unstructured.Unstructured{}
+		httpRoute.SetGroupVersionKind(registry.HTTPRouteGVK())
+		builder.Owns(httpRoute)
+	}
+
+	// If cert-manager is installed, mark certificates as owned by the controller
+	if config.ControllerCfg.HasCertManager() {
+		certificate := &unstructured.Unstructured{}
+		certificate.SetGroupVersionKind(registry.CertificateGVK)
+		builder.Owns(certificate)
+	}
+
 	return builder.Complete(r)
 
 }
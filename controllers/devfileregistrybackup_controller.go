@@ -0,0 +1,276 @@
+//
+// Copyright (c) 2020 Red Hat, Inc.
+// This program and the accompanying materials are made
+// available under the terms of the Eclipse Public License 2.0
+// which is available at https://www.eclipse.org/legal/epl-2.0/
+//
+// SPDX-License-Identifier: EPL-2.0
+//
+// Contributors:
+//   Red Hat, Inc. - initial API and implementation
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	registryv1beta1 "github.com/devfile/registry-operator/api/v1beta1"
+	"github.com/devfile/registry-operator/pkg/cluster"
+	"github.com/devfile/registry-operator/pkg/config"
+	"github.com/devfile/registry-operator/pkg/registry"
+)
+
+// Reasons used for the conditions on the DevfileRegistryBackup status
+const (
+	reasonInvalidSpec         = "InvalidSpec"
+	reasonSnapshotUnsupported = "SnapshotUnsupported"
+	reasonRegistryNotFound    = "RegistryNotFound"
+	reasonNoVolume            = "NoVolume"
+)
+
+// DevfileRegistryBackupReconciler reconciles a DevfileRegistryBackup object
+type DevfileRegistryBackupReconciler struct {
+	client.Client
+	Log      logr.Logger
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+}
+
+// +kubebuilder:rbac:groups=registry.devfile.io,resources=devfileregistrybackups,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=registry.devfile.io,resources=devfileregistrybackups/status;devfileregistrybackups/finalizers,verbs=get;update;patch
+// +kubebuilder:rbac:groups=snapshot.storage.k8s.io,resources=volumesnapshots,verbs=get;list;watch;create;delete
+
+func (r *DevfileRegistryBackupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.WithValues("devfileregistrybackup", req.NamespacedName)
+
+	backup := &registryv1beta1.DevfileRegistryBackup{}
+	if err := r.Get(ctx, req.NamespacedName, backup); err != nil {
+		if errors.IsNotFound(err) {
+			// The snapshots of a deleted backup are kept, so that the registry can still be restored from them
+			return ctrl.Result{}, nil
+		}
+		log.Error(err, "Failed to get DevfileRegistryBackup")
+		return ctrl.Result{}, err
+	}
+	if !backup.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	oldStatus := backup.Status.DeepCopy()
+	result, err := r.reconcileBackup(ctx, backup, time.Now())
+	if err != nil {
+		log.Error(err, "Failed to back up the DevfileRegistry")
+		setBackupCondition(backup, metav1.ConditionFalse, reasonReconcileFailed, err.Error())
+	}
+	if !equality.Semantic.DeepEqual(oldStatus, &backup.Status) {
+		if statusErr := r.Status().Update(ctx, backup); statusErr != nil {
+			log.Error(statusErr, "Failed to update DevfileRegistryBackup status")
+			if err == nil {
+				err = statusErr
+			}
+		}
+	}
+	return result, err
+}
+
+// reconcileBackup takes the snapshot of the registry that is due, if any, and deletes the snapshots that are no
+// longer retained
+func (r *DevfileRegistryBackupReconciler) reconcileBackup(ctx context.Context, backup *registryv1beta1.DevfileRegistryBackup, now time.Time) (ctrl.Result, error) {
+	if errs := registry.ValidateDevfileRegistryBackup(backup); len(errs) > 0 {
+		setBackupCondition(backup, metav1.ConditionFalse, reasonInvalidSpec, errs.ToAggregate().Error())
+		return ctrl.Result{}, nil
+	}
+	if !config.ControllerCfg.HasVolumeSnapshots() {
+		setBackupCondition(backup, metav1.ConditionFalse, reasonSnapshotUnsupported, "The cluster doesn't serve the VolumeSnapshot API")
+		return ctrl.Result{}, nil
+	}
+
+	// The registry is watched, so the backup is reconciled again once the registry can be backed up
+	cr := &registryv1beta1.DevfileRegistry{}
+	if err := r.Get(ctx, types.NamespacedName{Name: backup.Spec.RegistryName, Namespace: backup.Namespace}, cr); err != nil {
+		if errors.IsNotFound(err) {
+			setBackupCondition(backup, metav1.ConditionFalse, reasonRegistryNotFound, "DevfileRegistry "+backup.Spec.RegistryName+" doesn't exist")
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+	if !registry.IsPVCEnabled(cr) {
+		setBackupCondition(backup, metav1.ConditionFalse, reasonNoVolume, "DevfileRegistry "+cr.Name+" doesn't store its content in a PersistentVolumeClaim")
+		return ctrl.Result{}, nil
+	}
+
+	snapshots, err := r.listBackupSnapshots(ctx, backup)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	due, ok, err := registry.GetNextBackupTime(backup)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if ok && !now.Before(due) {
+		snapshot, err := r.takeSnapshot(ctx, backup, due)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if !containsSnapshot(snapshots, snapshot.GetName()) {
+			snapshots = append([]unstructured.Unstructured{*snapshot}, snapshots...)
+		}
+		backup.Status.LastBackupTime = &metav1.Time{Time: now}
+		due, ok, _ = registry.GetNextBackupTime(backup)
+	}
+
+	var result ctrl.Result
+	backup.Status.NextBackupTime = nil
+	if ok {
+		backup.Status.NextBackupTime = &metav1.Time{Time: due}
+		result.RequeueAfter = due.Sub(now)
+	}
+
+	kept, pruned := registry.GetSnapshotsToPrune(snapshots, registry.GetBackupRetain(backup))
+	for i := range pruned {
+		if err := r.Delete(ctx, &pruned[i]); client.IgnoreNotFound(err) != nil {
+			return ctrl.Result{}, err
+		}
+		r.Recorder.Eventf(backup, corev1.EventTypeNormal, eventReasonDeleted, "Deleted VolumeSnapshot %s, which is no longer retained", pruned[i].GetName())
+	}
+	backup.Status.Snapshots = nil
+	for _, snapshot := range kept {
+		backup.Status.Snapshots = append(backup.Status.Snapshots, snapshot.GetName())
+	}
+
+	if len(kept) == 0 {
+		setBackupCondition(backup, metav1.ConditionFalse, reasonSnapshotPending, "No VolumeSnapshot of DevfileRegistry "+cr.Name+" is retained")
+		return result, nil
+	}
+	latest := &kept[0]
+	ready, message := registry.IsVolumeSnapshotReady(latest)
+	switch {
+	case ready:
+		setBackupCondition(backup, metav1.ConditionTrue, reasonSnapshotReady, "VolumeSnapshot "+latest.GetName()+" is ready")
+	case message != "":
+		setBackupCondition(backup, metav1.ConditionFalse, reasonSnapshotFailed, "VolumeSnapshot "+latest.GetName()+" failed: "+message)
+	default:
+		setBackupCondition(backup, metav1.ConditionFalse, reasonSnapshotPending, "Waiting for VolumeSnapshot "+latest.GetName()+" to be ready")
+		if result.RequeueAfter == 0 || result.RequeueAfter > snapshotPollInterval {
+			result.RequeueAfter = snapshotPollInterval
+		}
+	}
+	return result, nil
+}
+
+// takeSnapshot creates the volume snapshot of the backup due at the given time. Snapshots are named after the time
+// they're due, so a snapshot that was already created is returned as is.
+func (r *DevfileRegistryBackupReconciler) takeSnapshot(ctx context.Context, backup *registryv1beta1.DevfileRegistryBackup, due time.Time) (*unstructured.Unstructured, error) {
+	snapshot := registry.GenerateBackupSnapshot(backup, due)
+	err := r.Create(ctx, snapshot)
+	if errors.IsAlreadyExists(err) {
+		return snapshot, r.Get(ctx, types.NamespacedName{Name: snapshot.GetName(), Namespace: snapshot.GetNamespace()}, snapshot)
+	} else if err != nil {
+		return nil, err
+	}
+	r.Log.Info("Created VolumeSnapshot", "devfileregistrybackup", types.NamespacedName{Name: backup.Name, Namespace: backup.Namespace}, "name", snapshot.GetName())
+	r.Recorder.Eventf(backup, corev1.EventTypeNormal, eventReasonCreated, "Created VolumeSnapshot %s of DevfileRegistry %s", snapshot.GetName(), backup.Spec.RegistryName)
+	return snapshot, nil
+}
+
+// listBackupSnapshots returns the volume snapshots taken by the backup, from the newest to the oldest
+func (r *DevfileRegistryBackupReconciler) listBackupSnapshots(ctx context.Context, backup *registryv1beta1.DevfileRegistryBackup) ([]unstructured.Unstructured, error) {
+	list := &unstructured.UnstructuredList{}
+	snapshotGVK := registry.VolumeSnapshotGVK()
+	list.SetGroupVersionKind(snapshotGVK.GroupVersion().WithKind(snapshotGVK.Kind + "List"))
+	// Only prune snapshots this backup took, never ones taken by an earlier backup with the same name
+	labels := client.MatchingLabels{registry.BackupLabel: backup.Name, registry.BackupUIDLabel: string(backup.UID)}
+	if err := r.List(ctx, list, client.InNamespace(backup.Namespace), labels); err != nil {
+		return nil, err
+	}
+	snapshots := list.Items
+	registry.SortSnapshots(snapshots)
+	return snapshots, nil
+}
+
+// containsSnapshot returns true if a volume snapshot with the given name is in the list
+func containsSnapshot(snapshots []unstructured.Unstructured, name string) bool {
+	for _, snapshot := range snapshots {
+		if snapshot.GetName() == name {
+			return true
+		}
+	}
+	return false
+}
+
+// setBackupCondition records the Ready condition of the DevfileRegistryBackup
+func setBackupCondition(backup *registryv1beta1.DevfileRegistryBackup, status metav1.ConditionStatus, reason string, message string) {
+	meta.SetStatusCondition(&backup.Status.Conditions, metav1.Condition{
+		Type:               registryv1beta1.ConditionBackupReady,
+		Status:             status,
+		ObservedGeneration: backup.Generation,
+		Reason:             reason,
+		Message:            message,
+	})
+}
+
+// backupsForRegistry maps a DevfileRegistry to the DevfileRegistryBackups backing it up, so that they're reconciled
+// again once the registry can be backed up
+func (r *DevfileRegistryBackupReconciler) backupsForRegistry(obj client.Object) []reconcile.Request {
+	backups := &registryv1beta1.DevfileRegistryBackupList{}
+	if err := r.List(context.Background(), backups, client.InNamespace(obj.GetNamespace())); err != nil {
+		r.Log.Error(err, "Failed to list DevfileRegistryBackups", "namespace", obj.GetNamespace())
+		return nil
+	}
+	var requests []reconcile.Request
+	for _, backup := range backups.Items {
+		if backup.Spec.RegistryName == obj.GetName() {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: backup.Name, Namespace: backup.Namespace}})
+		}
+	}
+	return requests
+}
+
+// backupForSnapshot maps a volume snapshot to the DevfileRegistryBackup that took it, to follow its progress
+func (r *DevfileRegistryBackupReconciler) backupForSnapshot(obj client.Object) []reconcile.Request {
+	name, ok := obj.GetLabels()[registry.BackupLabel]
+	if !ok {
+		return nil
+	}
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: name, Namespace: obj.GetNamespace()}}}
+}
+
+func (r *DevfileRegistryBackupReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	// Check if volume snapshots can be taken of the registry storage
+	volumeSnapshotAPIVersion, err := cluster.VolumeSnapshotAPIVersion()
+	if err != nil {
+		return err
+	}
+	config.ControllerCfg.SetVolumeSnapshotAPIVersion(volumeSnapshotAPIVersion)
+
+	builder := ctrl.NewControllerManagedBy(mgr).
+		For(&registryv1beta1.DevfileRegistryBackup{}).
+		Watches(&source.Kind{Type: &registryv1beta1.DevfileRegistry{}}, handler.EnqueueRequestsFromMapFunc(r.backupsForRegistry))
+
+	// If volume snapshots are served, watch the ones taken by backups to follow their progress
+	if config.ControllerCfg.HasVolumeSnapshots() {
+		snapshot := &unstructured.Unstructured{}
+		snapshot.SetGroupVersionKind(registry.VolumeSnapshotGVK())
+		builder.Watches(&source.Kind{Type: snapshot}, handler.EnqueueRequestsFromMapFunc(r.backupForSnapshot))
+	}
+
+	return builder.Complete(r)
+}
@@ -0,0 +1,92 @@
+//
+// Copyright (c) 2020 Red Hat, Inc.
+// This program and the accompanying materials are made
+// available under the terms of the Eclipse Public License 2.0
+// which is available at https://www.eclipse.org/legal/epl-2.0/
+//
+// SPDX-License-Identifier: EPL-2.0
+//
+// Contributors:
+//   Red Hat, Inc. - initial API and implementation
+
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	registryv1beta1 "github.com/devfile/registry-operator/api/v1beta1"
+	"github.com/devfile/registry-operator/pkg/config"
+	"github.com/devfile/registry-operator/pkg/registry"
+)
+
+func TestReconcileBackupPrunesByLabel(t *testing.T) {
+	scheme := runtime.NewScheme()
+	clientgoscheme.AddToScheme(scheme)
+	registryv1beta1.AddToScheme(scheme)
+	config.ControllerCfg.SetVolumeSnapshotAPIVersion("v1")
+	defer config.ControllerCfg.SetVolumeSnapshotAPIVersion("")
+
+	now := time.Date(2021, 7, 1, 12, 0, 0, 0, time.UTC)
+	retain := int32(1)
+	cr := &registryv1beta1.DevfileRegistry{}
+	cr.Name = "test-registry"
+	cr.Namespace = "test-namespace"
+
+	// A one-off backup that already took its snapshot, so none is due
+	backup := &registryv1beta1.DevfileRegistryBackup{Spec: registryv1beta1.DevfileRegistryBackupSpec{RegistryName: cr.Name, Retain: &retain}}
+	backup.Name = "test-backup"
+	backup.Namespace = cr.Namespace
+	backup.UID = "backup-uid"
+	backup.Status.LastBackupTime = &metav1.Time{Time: now.Add(-2 * time.Hour)}
+
+	// snapshotOf returns a ready snapshot of the registry due at the given time, taken by the backup with the given UID
+	snapshotOf := func(uid types.UID, due time.Time) *unstructured.Unstructured {
+		taker := backup.DeepCopy()
+		taker.UID = uid
+		snapshot := registry.GenerateBackupSnapshot(taker, due)
+		snapshot.SetCreationTimestamp(metav1.Time{Time: due})
+		unstructured.SetNestedField(snapshot.Object, true, "status", "readyToUse")
+		return snapshot
+	}
+	stale := snapshotOf("old-uid", now.Add(-4*time.Hour))
+	older := snapshotOf(backup.UID, now.Add(-3*time.Hour))
+	latest := snapshotOf(backup.UID, now.Add(-2*time.Hour))
+
+	r := &DevfileRegistryBackupReconciler{
+		Client:   fake.NewClientBuilder().WithScheme(scheme).WithObjects(cr, backup, stale, older, latest).Build(),
+		Log:      ctrl.Log.WithName("test"),
+		Scheme:   scheme,
+		Recorder: record.NewFakeRecorder(10),
+	}
+	if _, err := r.reconcileBackup(context.Background(), backup, now); err != nil {
+		t.Fatalf("TestReconcileBackupPrunesByLabel error: unexpected error: %v", err)
+	}
+
+	wantExists := map[string]bool{
+		stale.GetName():  true,
+		older.GetName():  false,
+		latest.GetName(): true,
+	}
+	for name, want := range wantExists {
+		snapshot := &unstructured.Unstructured{}
+		snapshot.SetGroupVersionKind(registry.VolumeSnapshotGVK())
+		err := r.Get(context.Background(), types.NamespacedName{Name: name, Namespace: backup.Namespace}, snapshot)
+		if exists := err == nil; exists != want {
+			t.Errorf("TestReconcileBackupPrunesByLabel error: VolumeSnapshot %s existence mismatch, expected: %v got: %v", name, want, exists)
+		}
+	}
+	if wantKept := []string{latest.GetName()}; len(backup.Status.Snapshots) != 1 || backup.Status.Snapshots[0] != wantKept[0] {
+		t.Errorf("TestReconcileBackupPrunesByLabel error: retained snapshots mismatch, expected: %v got: %v", wantKept, backup.Status.Snapshots)
+	}
+}
@@ -0,0 +1,189 @@
+//
+// Copyright (c) 2020 Red Hat, Inc.
+// This program and the accompanying materials are made
+// available under the terms of the Eclipse Public License 2.0
+// which is available at https://www.eclipse.org/legal/epl-2.0/
+//
+// SPDX-License-Identifier: EPL-2.0
+//
+// Contributors:
+//   Red Hat, Inc. - initial API and implementation
+
+package controllers
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	registryv1beta1 "github.com/devfile/registry-operator/api/v1beta1"
+	"github.com/devfile/registry-operator/pkg/registry"
+)
+
+// Reasons used for the conditions on the DevfileRegistryExport status
+const (
+	reasonRegistryNotReady = "RegistryNotReady"
+	reasonJobRunning       = "JobRunning"
+	reasonJobSucceeded     = "JobSucceeded"
+	reasonJobFailed        = "JobFailed"
+)
+
+// DevfileRegistryExportReconciler reconciles a DevfileRegistryExport object
+type DevfileRegistryExportReconciler struct {
+	client.Client
+	Log      logr.Logger
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+	// Image is the operator image, which the archive jobs run
+	Image string
+}
+
+// +kubebuilder:rbac:groups=registry.devfile.io,resources=devfileregistryexports,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=registry.devfile.io,resources=devfileregistryexports/status;devfileregistryexports/finalizers,verbs=get;update;patch
+// +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create
+
+func (r *DevfileRegistryExportReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.WithValues("devfileregistryexport", req.NamespacedName)
+
+	export := &registryv1beta1.DevfileRegistryExport{}
+	if err := r.Get(ctx, req.NamespacedName, export); err != nil {
+		if errors.IsNotFound(err) {
+			// The job of a deleted export is garbage collected along with it
+			return ctrl.Result{}, nil
+		}
+		log.Error(err, "Failed to get DevfileRegistryExport")
+		return ctrl.Result{}, err
+	}
+	// Exports run once, the job is left for its logs when it's done
+	if !export.DeletionTimestamp.IsZero() || export.Status.Phase == registryv1beta1.ExportPhaseSucceeded || export.Status.Phase == registryv1beta1.ExportPhaseFailed {
+		return ctrl.Result{}, nil
+	}
+
+	oldStatus := export.Status.DeepCopy()
+	err := r.reconcileExport(ctx, export)
+	if err != nil {
+		log.Error(err, "Failed to run the archive job")
+		setExportCondition(export, metav1.ConditionFalse, reasonReconcileFailed, err.Error())
+	}
+	if !equality.Semantic.DeepEqual(oldStatus, &export.Status) {
+		if statusErr := r.Status().Update(ctx, export); statusErr != nil {
+			log.Error(statusErr, "Failed to update DevfileRegistryExport status")
+			if err == nil {
+				err = statusErr
+			}
+		}
+	}
+	return ctrl.Result{}, err
+}
+
+// reconcileExport starts the archive job once the registry is ready, and follows its progress
+func (r *DevfileRegistryExportReconciler) reconcileExport(ctx context.Context, export *registryv1beta1.DevfileRegistryExport) error {
+	if errs := registry.ValidateDevfileRegistryExport(export); len(errs) > 0 {
+		export.Status.Phase = registryv1beta1.ExportPhaseFailed
+		setExportCondition(export, metav1.ConditionFalse, reasonInvalidSpec, errs.ToAggregate().Error())
+		return nil
+	}
+
+	job := &batchv1.Job{}
+	err := r.Get(ctx, types.NamespacedName{Name: registry.ArchiveJobName(export.Name), Namespace: export.Namespace}, job)
+	if errors.IsNotFound(err) {
+		// The registry is watched, so the export is reconciled again once the registry is ready
+		cr := &registryv1beta1.DevfileRegistry{}
+		err := r.Get(ctx, types.NamespacedName{Name: export.Spec.RegistryName, Namespace: export.Namespace}, cr)
+		if errors.IsNotFound(err) {
+			export.Status.Phase = registryv1beta1.ExportPhasePending
+			setExportCondition(export, metav1.ConditionFalse, reasonRegistryNotFound, "DevfileRegistry "+export.Spec.RegistryName+" doesn't exist")
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if !meta.IsStatusConditionTrue(cr.Status.Conditions, registryv1beta1.ConditionReady) {
+			export.Status.Phase = registryv1beta1.ExportPhasePending
+			setExportCondition(export, metav1.ConditionFalse, reasonRegistryNotReady, "Waiting for DevfileRegistry "+cr.Name+" to be ready")
+			return nil
+		}
+
+		job = registry.GenerateArchiveJob(export, cr, r.Image, r.Scheme)
+		if err := r.Create(ctx, job); err != nil {
+			return err
+		}
+		r.Log.Info("Created archive Job", "devfileregistryexport", types.NamespacedName{Name: export.Name, Namespace: export.Namespace}, "name", job.Name)
+		r.Recorder.Eventf(export, corev1.EventTypeNormal, eventReasonCreated, "Created Job %s to %s the stacks of DevfileRegistry %s", job.Name, registry.GetExportMode(export), cr.Name)
+	} else if err != nil {
+		return err
+	}
+
+	export.Status.JobName = job.Name
+	export.Status.StartTime = job.Status.StartTime
+	for _, condition := range job.Status.Conditions {
+		if condition.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch condition.Type {
+		case batchv1.JobComplete:
+			export.Status.Phase = registryv1beta1.ExportPhaseSucceeded
+			export.Status.CompletionTime = job.Status.CompletionTime
+			setExportCondition(export, metav1.ConditionTrue, reasonJobSucceeded, "Job "+job.Name+" copied the stacks")
+			return nil
+		case batchv1.JobFailed:
+			export.Status.Phase = registryv1beta1.ExportPhaseFailed
+			export.Status.CompletionTime = &condition.LastTransitionTime
+			setExportCondition(export, metav1.ConditionFalse, reasonJobFailed, "Job "+job.Name+" failed: "+condition.Message)
+			r.Recorder.Eventf(export, corev1.EventTypeWarning, reasonJobFailed, "Job %s failed: %s", job.Name, condition.Message)
+			return nil
+		}
+	}
+	export.Status.Phase = registryv1beta1.ExportPhaseRunning
+	setExportCondition(export, metav1.ConditionFalse, reasonJobRunning, "Job "+job.Name+" is copying the stacks")
+	return nil
+}
+
+// setExportCondition records the Complete condition of the DevfileRegistryExport
+func setExportCondition(export *registryv1beta1.DevfileRegistryExport, status metav1.ConditionStatus, reason string, message string) {
+	meta.SetStatusCondition(&export.Status.Conditions, metav1.Condition{
+		Type:               registryv1beta1.ConditionExportComplete,
+		Status:             status,
+		ObservedGeneration: export.Generation,
+		Reason:             reason,
+		Message:            message,
+	})
+}
+
+// exportsForRegistry maps a DevfileRegistry to the DevfileRegistryExports waiting for it, so that their job starts
+// once the registry is ready
+func (r *DevfileRegistryExportReconciler) exportsForRegistry(obj client.Object) []reconcile.Request {
+	exports := &registryv1beta1.DevfileRegistryExportList{}
+	if err := r.List(context.Background(), exports, client.InNamespace(obj.GetNamespace())); err != nil {
+		r.Log.Error(err, "Failed to list DevfileRegistryExports", "namespace", obj.GetNamespace())
+		return nil
+	}
+	var requests []reconcile.Request
+	for _, export := range exports.Items {
+		if export.Spec.RegistryName == obj.GetName() && export.Status.Phase == registryv1beta1.ExportPhasePending {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: export.Name, Namespace: export.Namespace}})
+		}
+	}
+	return requests
+}
+
+func (r *DevfileRegistryExportReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&registryv1beta1.DevfileRegistryExport{}).
+		Owns(&batchv1.Job{}).
+		Watches(&source.Kind{Type: &registryv1beta1.DevfileRegistry{}}, handler.EnqueueRequestsFromMapFunc(r.exportsForRegistry)).
+		Complete(r)
+}
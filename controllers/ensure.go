@@ -13,169 +13,509 @@ package controllers
 
 import (
 	"context"
+	"time"
 
-	registryv1alpha1 "github.com/devfile/registry-operator/api/v1alpha1"
+	registryv1beta1 "github.com/devfile/registry-operator/api/v1beta1"
+	"github.com/devfile/registry-operator/pkg/config"
+	"github.com/devfile/registry-operator/pkg/metrics"
 	"github.com/devfile/registry-operator/pkg/registry"
+	"github.com/devfile/registry-operator/pkg/util"
 	routev1 "github.com/openshift/api/route/v1"
-	"github.com/prometheus/common/log"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
-	"k8s.io/api/extensions/v1beta1"
+	networkingv1 "k8s.io/api/networking/v1"
+	networkingv1beta1 "k8s.io/api/networking/v1beta1"
+	policyv1 "k8s.io/api/policy/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	storagev1 "k8s.io/api/storage/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
-// ensureService ensures that a service for the devfile registry exists on the cluster and is up to date with the custom resource
-func (r *DevfileRegistryReconciler) ensureService(ctx context.Context, cr *registryv1alpha1.DevfileRegistry, labels map[string]string) (*reconcile.Result, error) {
-	// Check if the service already exists, if not create a new one
-	svc := &corev1.Service{}
-	err := r.Get(ctx, types.NamespacedName{Name: registry.ServiceName(cr.Name), Namespace: cr.Namespace}, svc)
-	if err != nil && errors.IsNotFound(err) {
-		// Define a new service
-		pvc := registry.GenerateService(cr, r.Scheme, labels)
-		log.Info("Creating a new Service", "Service.Namespace", pvc.Namespace, "Service.Name", pvc.Name)
-		err = r.Create(ctx, pvc)
-		if err != nil {
-			log.Error(err, "Failed to create new Service", "Service.Namespace", pvc.Namespace, "Service.Name", pvc.Name)
-			return &ctrl.Result{}, err
-		}
-		return nil, nil
-	} else if err != nil {
-		log.Error(err, "Failed to get Service")
-		return &ctrl.Result{}, err
+// serverProbeInterval is how long to wait before probing a devfile registry server that wasn't responding again
+const serverProbeInterval = 10 * time.Second
+
+// serviceResource describes the service load balancing the devfile index and OCI registry containers
+func (r *DevfileRegistryReconciler) serviceResource(cr *registryv1beta1.DevfileRegistry, labels map[string]string) childResource {
+	return childResource{
+		kind:          "Service",
+		name:          registry.ServiceName(cr.Name),
+		step:          metrics.StepService,
+		newObject:     func() client.Object { return &corev1.Service{} },
+		generate:      func() client.Object { return registry.GenerateService(cr, r.Scheme, labels) },
+		conditionType: registryv1beta1.ConditionServiceReady,
 	}
+}
 
-	return nil, nil
+// ociConfigResource describes the config map holding the configuration file of the OCI registry
+func (r *DevfileRegistryReconciler) ociConfigResource(cr *registryv1beta1.DevfileRegistry, labels map[string]string) childResource {
+	return childResource{
+		kind:          "ConfigMap",
+		name:          registry.OCIRegistryConfigName(cr.Name),
+		step:          metrics.StepOCIConfig,
+		newObject:     func() client.Object { return &corev1.ConfigMap{} },
+		generate:      func() client.Object { return registry.GenerateOCIRegistryConfigMap(cr, r.Scheme, labels) },
+		conditionType: registryv1beta1.ConditionDeploymentAvailable,
+	}
 }
 
-// ensureDeployment ensures that a devfile registry deployment exists on the cluster and is up to date with the custom resource
-func (r *DevfileRegistryReconciler) ensureDeployment(ctx context.Context, cr *registryv1alpha1.DevfileRegistry, labels map[string]string) (*reconcile.Result, error) {
-	dep := &appsv1.Deployment{}
-	err := r.Get(ctx, types.NamespacedName{Name: registry.DeploymentName(cr.Name), Namespace: cr.Namespace}, dep)
-	if err != nil && errors.IsNotFound(err) {
-		// Generate a new Deployment template and create it on the cluster
-		dep = registry.GenerateDeployment(cr, r.Scheme, labels)
+// deploymentResource describes the deployment running the devfile registry, serving the certificate of tlsSecret when
+// TLS is served in the pods
+func (r *DevfileRegistryReconciler) deploymentResource(cr *registryv1beta1.DevfileRegistry, tlsSecret *corev1.Secret, labels map[string]string) childResource {
+	return childResource{
+		kind:          "Deployment",
+		name:          registry.DeploymentName(cr.Name),
+		step:          metrics.StepDeployment,
+		newObject:     func() client.Object { return &appsv1.Deployment{} },
+		generate:      func() client.Object { return registry.GenerateDeployment(cr, tlsSecret, r.Scheme, labels) },
+		conditionType: registryv1beta1.ConditionDeploymentAvailable,
+		// Mirror the deployment's own Available condition
+		ready: func(obj client.Object) (metav1.ConditionStatus, string, string) {
+			if isDeploymentAvailable(obj.(*appsv1.Deployment)) {
+				return metav1.ConditionTrue, reasonDeploymentReady, "Deployment " + obj.GetName() + " has minimum availability"
+			}
+			return metav1.ConditionFalse, reasonDeploymentWait, "Deployment " + obj.GetName() + " does not have minimum availability"
+		},
+	}
+}
 
-		log.Info("Creating a new Deployment", "Deployment.Namespace", dep.Namespace, "Deployment.Name", dep.Name)
-		err = r.Create(ctx, dep)
-		if err != nil {
-			log.Error(err, "Failed to create new Deployment", "Deployment.Namespace", dep.Namespace, "Deployment.Name", dep.Name)
-			return &ctrl.Result{}, err
+// httpSecretResource describes the secret holding the HTTP secret shared by the OCI registry replicas. It's deleted
+// when the registry runs a single replica, which has to happen after the deployment stops referencing it.
+func (r *DevfileRegistryReconciler) httpSecretResource(cr *registryv1beta1.DevfileRegistry, labels map[string]string) childResource {
+	return childResource{
+		kind:          "Secret",
+		name:          registry.HTTPSecretName(cr.Name),
+		step:          metrics.StepOCIConfig,
+		newObject:     func() client.Object { return &corev1.Secret{} },
+		generate:      func() client.Object { return registry.GenerateHTTPSecret(cr, r.Scheme, labels) },
+		strategy:      issueStrategy(registry.IssueHTTPSecret),
+		disabled:      !registry.IsReplicated(cr),
+		conditionType: registryv1beta1.ConditionDeploymentAvailable,
+	}
+}
+
+// podDisruptionBudgetResource describes the pod disruption budget keeping all but one of the registry replicas running
+// through node drains. A single replica isn't covered, as the budget would block the drains. It's served through the
+// policy/v1beta1 API on clusters that predate policy/v1.
+func (r *DevfileRegistryReconciler) podDisruptionBudgetResource(cr *registryv1beta1.DevfileRegistry, labels map[string]string) childResource {
+	child := childResource{
+		kind:          "PodDisruptionBudget",
+		name:          registry.PodDisruptionBudgetName(cr.Name),
+		step:          metrics.StepDisruptionBudget,
+		newObject:     func() client.Object { return &policyv1.PodDisruptionBudget{} },
+		generate:      func() client.Object { return registry.GeneratePodDisruptionBudget(cr, r.Scheme, labels) },
+		disabled:      !registry.IsReplicated(cr),
+		conditionType: registryv1beta1.ConditionDeploymentAvailable,
+	}
+	if !config.ControllerCfg.HasPodDisruptionBudgetV1() {
+		child.newObject = func() client.Object { return &policyv1beta1.PodDisruptionBudget{} }
+		child.generate = func() client.Object { return registry.GeneratePodDisruptionBudgetV1beta1(cr, r.Scheme, labels) }
+	}
+	return child
+}
+
+// isDeploymentAvailable returns true if the deployment reports the Available condition
+func isDeploymentAvailable(dep *appsv1.Deployment) bool {
+	for _, condition := range dep.Status.Conditions {
+		if condition.Type == appsv1.DeploymentAvailable {
+			return condition.Status == corev1.ConditionTrue
 		}
-		return nil, nil
-	} else if err != nil {
-		log.Error(err, "Failed to get Deployment")
-		return &ctrl.Result{}, err
 	}
+	return false
+}
 
-	err = r.updateDeployment(ctx, cr, dep)
-	if err != nil {
-		log.Error(err, "Failed to update Deployment")
-		return &ctrl.Result{}, err
+// pvcResource describes the persistent volume claim backing the OCI registry. It's deleted when storage is disabled or
+// moved to S3, which has to happen after the deployment stops mounting it.
+func (r *DevfileRegistryReconciler) pvcResource(cr *registryv1beta1.DevfileRegistry, labels map[string]string) childResource {
+	child := childResource{
+		kind:          "PersistentVolumeClaim",
+		name:          registry.PVCName(cr.Name),
+		step:          metrics.StepPVC,
+		newObject:     func() client.Object { return &corev1.PersistentVolumeClaim{} },
+		generate:      func() client.Object { return registry.GeneratePVC(cr, r.Scheme, labels) },
+		strategy:      pvcStrategy,
+		disabled:      !registry.IsPVCEnabled(cr),
+		conditionType: registryv1beta1.ConditionStorageReady,
+		// Claims using a WaitForFirstConsumer storage class only bind once the deployment is scheduled, so an unbound
+		// claim is reported but doesn't block the rest of the reconcile
+		ready: func(obj client.Object) (metav1.ConditionStatus, string, string) {
+			pvc := obj.(*corev1.PersistentVolumeClaim)
+			if pvc.Status.Phase != corev1.ClaimBound {
+				return metav1.ConditionFalse, reasonPVCPending, "PersistentVolumeClaim " + pvc.Name + " is not bound yet"
+			}
+			if registry.IsPVCSpecImmutableChanged(cr, pvc) {
+				return metav1.ConditionFalse, reasonPVCImmutable, "The storage class and access modes of PersistentVolumeClaim " + pvc.Name + " can't be changed"
+			}
+			requested, err := registry.ParsePVCSize(cr)
+			if err != nil {
+				return metav1.ConditionFalse, reasonReconcileFailed, err.Error()
+			}
+			current := pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+			switch current.Cmp(requested) {
+			case 1:
+				// The claim keeps the size it was automatically expanded to
+				if registry.IsAutoExpandedSize(cr, current) {
+					break
+				}
+				return metav1.ConditionFalse, reasonShrinkRejected, "PersistentVolumeClaim " + pvc.Name + " can't be shrunk from " + current.String() + " to " + requested.String()
+			case -1:
+				return metav1.ConditionFalse, reasonNoExpansion, "The storage class of PersistentVolumeClaim " + pvc.Name + " doesn't allow expanding it to " + requested.String()
+			}
+			if resizing, message := registry.GetPVCResizeStatus(pvc); resizing {
+				return metav1.ConditionFalse, reasonPVCResizing, "PersistentVolumeClaim " + pvc.Name + " is being resized: " + message
+			}
+			return metav1.ConditionTrue, reasonPVCBound, "PersistentVolumeClaim " + pvc.Name + " is bound"
+		},
+		disabledReason:  reasonStorageDisabled,
+		disabledMessage: "Persistent storage is disabled, the registry uses ephemeral storage",
 	}
-	return nil, nil
+	if registry.IsS3Enabled(cr) {
+		child.disabledReason = reasonObjectStorage
+		child.disabledMessage = "The registry stores its content in S3 bucket " + cr.Spec.Storage.S3.Bucket
+	}
+	return child
 }
 
-func (r *DevfileRegistryReconciler) ensurePVC(ctx context.Context, cr *registryv1alpha1.DevfileRegistry, labels map[string]string) (*reconcile.Result, error) {
-	// Check if the persistentvolumeclaim already exists, if not create a new one
-	pvc := &corev1.PersistentVolumeClaim{}
-	err := r.Get(ctx, types.NamespacedName{Name: registry.PVCName(cr.Name), Namespace: cr.Namespace}, pvc)
-	if err != nil && errors.IsNotFound(err) {
-		// Define a new PVC
-		pvc := registry.GeneratePVC(cr, r.Scheme, labels)
-		log.Info("Creating a new PersistentVolumeClaim", "PersistentVolumeClaim.Namespace", pvc.Namespace, "PersistentVolumeClaim.Name", pvc.Name)
-		err = r.Create(ctx, pvc)
+// pvcStrategy applies the persistent volume claim, leaving out the changes the API server would reject: the storage
+// class and access modes are kept as they are, and the claim only grows when its storage class allows expanding it.
+// The ready check of the claim reports the changes that were left out.
+func pvcStrategy(ctx context.Context, r *DevfileRegistryReconciler, existing client.Object, desired client.Object) (client.Object, bool, error) {
+	if existing == nil {
+		return applyStrategy(ctx, r, existing, desired)
+	}
+	existingPVC := existing.(*corev1.PersistentVolumeClaim)
+	pvc := desired.(*corev1.PersistentVolumeClaim)
+	pvc.Spec.StorageClassName = existingPVC.Spec.StorageClassName
+	pvc.Spec.AccessModes = existingPVC.Spec.AccessModes
+	pvc.Spec.DataSource = existingPVC.Spec.DataSource
+
+	current := existingPVC.Spec.Resources.Requests[corev1.ResourceStorage]
+	requested := pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+	if requested.Cmp(current) > 0 {
+		expandable, err := r.isPVCExpandable(ctx, existingPVC)
 		if err != nil {
-			log.Error(err, "Failed to create new PersistentVolumeClaim", "PersistentVolumeClaim.Namespace", pvc.Namespace, "PersistentVolumeClaim.Name", pvc.Name)
-			return &ctrl.Result{}, err
+			return nil, false, err
 		}
-		return nil, nil
-	} else if err != nil {
-		log.Error(err, "Failed to get PersistentVolumeClaim")
-		return &ctrl.Result{}, err
+		if expandable {
+			r.Log.Info("Expanding PersistentVolumeClaim", "name", pvc.Name, "from", current.String(), "to", requested.String())
+		} else {
+			pvc.Spec.Resources.Requests[corev1.ResourceStorage] = current
+		}
+	} else if requested.Cmp(current) < 0 {
+		pvc.Spec.Resources.Requests[corev1.ResourceStorage] = current
 	}
+	return applyStrategy(ctx, r, existing, pvc)
+}
 
-	return nil, nil
+// isPVCExpandable returns true if the storage class the persistent volume claim was provisioned from allows expanding
+// its volumes. Claims without a storage class weren't dynamically provisioned and can't be expanded.
+func (r *DevfileRegistryReconciler) isPVCExpandable(ctx context.Context, pvc *corev1.PersistentVolumeClaim) (bool, error) {
+	if pvc.Spec.StorageClassName == nil || *pvc.Spec.StorageClassName == "" {
+		return false, nil
+	}
+	storageClass := &storagev1.StorageClass{}
+	if err := r.Get(ctx, types.NamespacedName{Name: *pvc.Spec.StorageClassName}, storageClass); err != nil {
+		if errors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return storageClass.AllowVolumeExpansion != nil && *storageClass.AllowVolumeExpansion, nil
 }
 
-func (r *DevfileRegistryReconciler) ensureDevfilesRoute(ctx context.Context, cr *registryv1alpha1.DevfileRegistry, labels map[string]string) (*reconcile.Result, error) {
-	route := &routev1.Route{}
-	err := r.Get(ctx, types.NamespacedName{Name: registry.DevfilesRouteName(cr.Name), Namespace: cr.Namespace}, route)
-	if err != nil && errors.IsNotFound(err) {
-		// Define a new route exposing the devfile registry index
-		route := registry.GenerateDevfilesRoute(cr, r.Scheme, labels)
-		log.Info("Creating a new Route", "Route.Namespace", route.Namespace, "Route.Name", route.Name)
-		err = r.Create(ctx, route)
-		if err != nil {
-			log.Error(err, "Failed to create new Route", "Route.Namespace", route.Namespace, "Route.Name", route.Name)
-			return &ctrl.Result{}, err
+// devfilesRouteResource describes the route exposing the devfile registry index
+func (r *DevfileRegistryReconciler) devfilesRouteResource(cr *registryv1beta1.DevfileRegistry, tlsSecret *corev1.Secret, labels map[string]string) childResource {
+	return childResource{
+		kind:          "Route",
+		name:          registry.DevfilesRouteName(cr.Name),
+		step:          metrics.StepDevfilesRoute,
+		newObject:     func() client.Object { return &routev1.Route{} },
+		generate:      func() client.Object { return registry.GenerateDevfilesRoute(cr, tlsSecret, r.Scheme, labels) },
+		conditionType: registryv1beta1.ConditionExposed,
+	}
+}
+
+// ociRouteResource describes the route exposing the OCI registry, under the same hostname as the devfile registry index.
+// It's deleted when the routes pass TLS through, as they can't be told apart by path anymore.
+func (r *DevfileRegistryReconciler) ociRouteResource(cr *registryv1beta1.DevfileRegistry, hostname string, tlsSecret *corev1.Secret, labels map[string]string) childResource {
+	return childResource{
+		kind:          "Route",
+		name:          registry.OCIRouteName(cr.Name),
+		step:          metrics.StepOCIRoute,
+		newObject:     func() client.Object { return &routev1.Route{} },
+		generate:      func() client.Object { return registry.GenerateOCIRoute(cr, hostname, tlsSecret, r.Scheme, labels) },
+		disabled:      !registry.IsOCIRouteEnabled(cr),
+		conditionType: registryv1beta1.ConditionExposed,
+	}
+}
+
+// httpRouteResource describes the Gateway API HTTPRoute exposing the devfile index and OCI registry through a gateway
+func (r *DevfileRegistryReconciler) httpRouteResource(cr *registryv1beta1.DevfileRegistry, labels map[string]string) childResource {
+	return childResource{
+		kind: "HTTPRoute",
+		name: registry.HTTPRouteName(cr.Name),
+		step: metrics.StepHTTPRoute,
+		newObject: func() client.Object {
+			route := &unstructured.Unstructured{}
+			route.SetGroupVersionKind(registry.HTTPRouteGVK())
+			return route
+		},
+		generate:      func() client.Object { return registry.GenerateHTTPRoute(cr, r.Scheme, labels) },
+		conditionType: registryv1beta1.ConditionExposed,
+	}
+}
+
+// certificateResource describes the cert-manager certificate requested for the hostname of the ingress. It's deleted
+// when TLS is disabled or the user provides the certificate.
+func (r *DevfileRegistryReconciler) certificateResource(cr *registryv1beta1.DevfileRegistry, hostname string, labels map[string]string) childResource {
+	return childResource{
+		kind: "Certificate",
+		name: registry.CertificateName(cr.Name),
+		step: metrics.StepCertificate,
+		newObject: func() client.Object {
+			certificate := &unstructured.Unstructured{}
+			certificate.SetGroupVersionKind(registry.CertificateGVK)
+			return certificate
+		},
+		generate:      func() client.Object { return registry.GenerateCertificate(cr, hostname, r.Scheme, labels) },
+		disabled:      !registry.IsCertificateRequested(cr),
+		conditionType: registryv1beta1.ConditionExposed,
+		ready: func(obj client.Object) (metav1.ConditionStatus, string, string) {
+			ready, message := registry.IsCertificateReady(obj.(*unstructured.Unstructured))
+			if ready {
+				return metav1.ConditionTrue, reasonReconciled, "Certificate " + obj.GetName() + " is ready"
+			}
+			if message != "" {
+				return metav1.ConditionFalse, reasonCertPending, "Waiting for Certificate " + obj.GetName() + " to be issued: " + message
+			}
+			return metav1.ConditionFalse, reasonCertPending, "Waiting for Certificate " + obj.GetName() + " to be issued"
+		},
+	}
+}
+
+// selfSignedResources describes the CA, serving certificate and CA bundle the operator generates for the devfile
+// registry, in the order they have to be reconciled. They're deleted when the registry doesn't use self-signed
+// certificates. Certificates are renewed once less than a third of their validity is left, which the periodic resync
+// of the controller catches in time.
+func (r *DevfileRegistryReconciler) selfSignedResources(cr *registryv1beta1.DevfileRegistry, labels map[string]string) []childResource {
+	disabled := !registry.IsSelfSigned(cr)
+	// The CA issued or read by the first child signs the serving certificate and fills the CA bundle
+	ca := &corev1.Secret{}
+
+	return []childResource{
+		{
+			kind:      "Secret",
+			name:      registry.CASecretName(cr.Name),
+			step:      metrics.StepCertificate,
+			newObject: func() client.Object { return &corev1.Secret{} },
+			generate:  func() client.Object { return registry.GenerateCASecret(cr, r.Scheme, labels) },
+			strategy: issueStrategy(func(existing *corev1.Secret) (map[string][]byte, bool, error) {
+				data, changed, err := registry.IssueCA(cr, existing, time.Now())
+				ca.Data = data
+				return data, changed, err
+			}),
+			disabled:      disabled,
+			conditionType: registryv1beta1.ConditionExposed,
+		},
+		{
+			kind:      "Secret",
+			name:      registry.CertificateSecretName(cr.Name),
+			step:      metrics.StepCertificate,
+			newObject: func() client.Object { return &corev1.Secret{} },
+			generate:  func() client.Object { return registry.GenerateServingSecret(cr, r.Scheme, labels) },
+			strategy: issueStrategy(func(existing *corev1.Secret) (map[string][]byte, bool, error) {
+				return registry.IssueServingCertificate(existing, ca, registry.GetServingHostnames(cr), time.Now())
+			}),
+			disabled:      disabled,
+			conditionType: registryv1beta1.ConditionExposed,
+		},
+		{
+			kind:          "ConfigMap",
+			name:          registry.CABundleName(cr.Name),
+			step:          metrics.StepCertificate,
+			newObject:     func() client.Object { return &corev1.ConfigMap{} },
+			generate:      func() client.Object { return registry.GenerateCABundle(cr, ca, r.Scheme, labels) },
+			disabled:      disabled,
+			conditionType: registryv1beta1.ConditionExposed,
+		},
+	}
+}
+
+// ingressResource describes the ingress exposing the devfile index and OCI registry on Kubernetes. It's served through
+// the networking.k8s.io/v1beta1 API on clusters that predate networking.k8s.io/v1.
+func (r *DevfileRegistryReconciler) ingressResource(cr *registryv1beta1.DevfileRegistry, hostname string, labels map[string]string) childResource {
+	child := childResource{
+		kind:          "Ingress",
+		name:          registry.IngressName(cr.Name),
+		step:          metrics.StepIngress,
+		newObject:     func() client.Object { return &networkingv1.Ingress{} },
+		generate:      func() client.Object { return registry.GenerateIngress(cr, hostname, r.Scheme, labels) },
+		conditionType: registryv1beta1.ConditionExposed,
+	}
+	if !config.ControllerCfg.HasIngressV1() {
+		child.newObject = func() client.Object { return &networkingv1beta1.Ingress{} }
+		child.generate = func() client.Object { return registry.GenerateIngressV1beta1(cr, hostname, r.Scheme, labels) }
+	}
+	return child
+}
+
+// staleExposureResources describes the resources that expose the devfile registry through exposure types other than
+// the one in use, as disabled children so that they are deleted. Kinds the cluster doesn't serve are skipped.
+func (r *DevfileRegistryReconciler) staleExposureResources(cr *registryv1beta1.DevfileRegistry, exposureType registryv1beta1.DevfileRegistryExposureType) []childResource {
+	var stale []childResource
+	if exposureType != registryv1beta1.ExposureTypeRoute && config.ControllerCfg.IsOpenShift() {
+		stale = append(stale, r.devfilesRouteResource(cr, nil, nil), r.ociRouteResource(cr, "", nil, nil))
+	}
+	if exposureType != registryv1beta1.ExposureTypeIngress {
+		stale = append(stale, r.ingressResource(cr, "", nil))
+		if config.ControllerCfg.HasCertManager() {
+			stale = append(stale, r.certificateResource(cr, "", nil))
 		}
+	}
+	if exposureType != registryv1beta1.ExposureTypeGateway && config.ControllerCfg.GatewayAPIVersion() != "" {
+		stale = append(stale, r.httpRouteResource(cr, nil))
+	}
+	for i := range stale {
+		stale[i].disabled = true
+		stale[i].disabledMessage = "The devfile registry is exposed through " + string(exposureType)
+	}
+	return stale
+}
+
+// getRouteTLSSecret returns the secret holding the certificate of the routes exposing the devfile registry, or nil if
+// the routes use the router's default certificate. When only the registry has a certificate, the routes keep the
+// router's certificate and only get the CA to verify the registry with.
+func (r *DevfileRegistryReconciler) getRouteTLSSecret(ctx context.Context, cr *registryv1beta1.DevfileRegistry) (*corev1.Secret, error) {
+	if !registry.IsTLSEnabled(cr) {
+		return nil, nil
+	}
+	secretName := cr.Spec.TLS.SecretName
+	if secretName == "" && registry.IsInPodTLSEnabled(cr) {
+		secretName = registry.GetTLSSecretName(cr)
+	}
+	if secretName == "" {
 		return nil, nil
-	} else if err != nil {
-		log.Error(err, "Failed to get Route")
-		return &ctrl.Result{}, err
 	}
 
-	err = r.updateDevfilesRoute(ctx, cr, route)
-	if err != nil {
-		log.Error(err, "Failed to update Route")
-		return &ctrl.Result{}, err
+	secret := &corev1.Secret{}
+	if err := r.Get(ctx, types.NamespacedName{Name: secretName, Namespace: cr.Namespace}, secret); err != nil {
+		return nil, err
 	}
+	if cr.Spec.TLS.SecretName == "" {
+		secret.Data = map[string][]byte{registry.TLSSecretCAKey: secret.Data[registry.TLSSecretCAKey]}
+	}
+	return secret, nil
+}
 
-	return nil, nil
+// getServingTLSSecret returns the secret holding the certificate served by the registry containers, or nil if they
+// don't serve TLS or it doesn't exist yet
+func (r *DevfileRegistryReconciler) getServingTLSSecret(ctx context.Context, cr *registryv1beta1.DevfileRegistry) (*corev1.Secret, error) {
+	if !registry.IsInPodTLSEnabled(cr) {
+		return nil, nil
+	}
+	secret := &corev1.Secret{}
+	if err := r.Get(ctx, types.NamespacedName{Name: registry.GetTLSSecretName(cr), Namespace: cr.Namespace}, secret); err != nil {
+		return nil, client.IgnoreNotFound(err)
+	}
+	return secret, nil
 }
 
-func (r *DevfileRegistryReconciler) ensureOCIRoute(ctx context.Context, cr *registryv1alpha1.DevfileRegistry, hostname string, labels map[string]string) (*reconcile.Result, error) {
-	route := &routev1.Route{}
-	err := r.Get(ctx, types.NamespacedName{Name: registry.OCIRouteName(cr.Name), Namespace: cr.Namespace}, route)
-	if err != nil && errors.IsNotFound(err) {
-		// Define a new route exposing the devfile registry index
-		route = registry.GenerateOCIRoute(cr, hostname, r.Scheme, labels)
-		log.Info("Creating a new Route", "Route.Namespace", route.Namespace, "Route.Name", route.Name)
-		err = r.Create(ctx, route)
-		if err != nil {
-			log.Error(err, "Failed to create new Route", "Route.Namespace", route.Namespace, "Route.Name", route.Name)
-			return &ctrl.Result{}, err
+// registriesForSecret maps a secret to the DevfileRegistries in its namespace using it as their TLS secret, so that
+// their routes and pods pick up certificate changes
+func (r *DevfileRegistryReconciler) registriesForSecret(obj client.Object) []reconcile.Request {
+	registries := &registryv1beta1.DevfileRegistryList{}
+	if err := r.List(context.Background(), registries, client.InNamespace(obj.GetNamespace())); err != nil {
+		r.Log.Error(err, "Failed to list DevfileRegistries", "namespace", obj.GetNamespace())
+		return nil
+	}
+	var requests []reconcile.Request
+	for _, cr := range registries.Items {
+		if registry.GetTLSSecretName(&cr) == obj.GetName() {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: cr.Name, Namespace: cr.Namespace}})
 		}
-		return nil, nil
-	} else if err != nil {
-		log.Error(err, "Failed to get Route")
-		return &ctrl.Result{}, err
 	}
+	return requests
+}
 
-	err = r.updateOCIRoute(ctx, cr, route)
-	if err != nil {
-		log.Error(err, "Failed to update Route")
-		return &ctrl.Result{}, err
+// getServiceExposureURL returns the URL the devfile registry is served at by its NodePort or LoadBalancer service, or
+// an empty string while the service has no address yet
+func (r *DevfileRegistryReconciler) getServiceExposureURL(ctx context.Context, cr *registryv1beta1.DevfileRegistry, exposureType registryv1beta1.DevfileRegistryExposureType) (string, error) {
+	svc := &corev1.Service{}
+	if err := r.Get(ctx, types.NamespacedName{Name: registry.ServiceName(cr.Name), Namespace: cr.Namespace}, svc); err != nil {
+		return "", err
+	}
+	if exposureType == registryv1beta1.ExposureTypeLoadBalancer {
+		return registry.GetLoadBalancerURL(cr, svc), nil
 	}
 
-	return nil, nil
+	nodes := &corev1.NodeList{}
+	if err := r.List(ctx, nodes); err != nil {
+		return "", err
+	}
+	return registry.GetNodePortURL(cr, svc, nodes.Items), nil
 }
 
-func (r *DevfileRegistryReconciler) ensureIngress(ctx context.Context, cr *registryv1alpha1.DevfileRegistry, hostname string, labels map[string]string) (*reconcile.Result, error) {
-	ingress := &v1beta1.Ingress{}
-	err := r.Get(ctx, types.NamespacedName{Name: registry.IngressName(cr.Name), Namespace: cr.Namespace}, ingress)
-	if err != nil && errors.IsNotFound(err) {
-		// Define a new ingress exposing the devfile index and oci registry
-		ingress = registry.GenerateIngress(cr, hostname, r.Scheme, labels)
-		log.Info("Creating a new Ingress", "Ingress.Namespace", ingress.Namespace, "Ingress.Name", ingress.Name)
-		err = r.Create(ctx, ingress)
+// ensureServerReachable probes the devfile registry server once, and records its URL in the status once it responds.
+// Rather than waiting for the server, the DevfileRegistry is requeued until the probe succeeds so that the reconcile
+// doesn't hold up the other registries.
+func (r *DevfileRegistryReconciler) ensureServerReachable(ctx context.Context, cr *registryv1beta1.DevfileRegistry, url string) (*reconcile.Result, error) {
+	// The server can't respond before the deployment is available, and the deployment becoming available triggers
+	// another reconcile
+	if !meta.IsStatusConditionTrue(cr.Status.Conditions, registryv1beta1.ConditionDeploymentAvailable) {
+		setCondition(cr, registryv1beta1.ConditionServerReachable, metav1.ConditionFalse, reasonDeploymentWait, "Waiting for Deployment "+registry.DeploymentName(cr.Name)+" to be available")
+		return nil, nil
+	}
+
+	log := r.Log.WithValues("devfileregistry", types.NamespacedName{Name: cr.Name, Namespace: cr.Namespace}, "url", url)
+	previous := meta.FindStatusCondition(cr.Status.Conditions, registryv1beta1.ConditionServerReachable)
+
+	// Verify registries serving the operator's self-signed certificate against its CA
+	httpClient := r.HTTPClient
+	probeClientKey := types.NamespacedName{Name: cr.Name, Namespace: cr.Namespace}.String()
+	if registry.IsSelfSignedCertificateServed(cr) {
+		caBundle := &corev1.ConfigMap{}
+		err := r.Get(ctx, types.NamespacedName{Name: registry.CABundleName(cr.Name), Namespace: cr.Namespace}, caBundle)
+		if err == nil {
+			// Registries reached without a Route, Ingress or HTTPRoute present the certificate issued for their service
+			serverName := ""
+			if registry.GetServiceType(cr) != corev1.ServiceTypeClusterIP {
+				serverName = registry.GetServiceHostname(cr)
+			}
+			httpClient, err = r.probeClients.Get(probeClientKey, r.HTTPClient, []byte(caBundle.Data[registry.CABundleKey]), serverName)
+		}
 		if err != nil {
-			log.Error(err, "Failed to create new Ingress", "Ingress.Namespace", ingress.Namespace, "Ingress.Name", ingress.Name)
+			log.Error(err, "Failed to load the CA bundle")
+			setFailedCondition(cr, registryv1beta1.ConditionServerReachable, err)
 			return &ctrl.Result{}, err
 		}
-		return nil, nil
-	} else if err != nil {
-		log.Error(err, "Failed to get Ingress")
-		return &ctrl.Result{}, err
+	} else {
+		r.probeClients.Delete(probeClientKey)
 	}
 
-	err = r.updateIngress(ctx, cr, hostname, ingress)
+	start := time.Now()
+	err := util.ProbeServer(ctx, httpClient, url)
 	if err != nil {
-		log.Error(err, "Failed to update Ingress")
-		return &ctrl.Result{}, err
+		log.Info("Devfile registry server is not responding yet, requeuing", "error", err.Error())
+		// Only report the first failed probe, rather than every one until the server comes up
+		if previous == nil || previous.Reason != reasonServerDown {
+			r.Recorder.Eventf(cr, corev1.EventTypeWarning, eventReasonServerUnreachable, "Devfile registry server at %s is not responding: %v", url, err)
+		}
+		setCondition(cr, registryv1beta1.ConditionServerReachable, metav1.ConditionFalse, reasonServerDown, "Devfile registry server at "+url+" is not responding: "+err.Error())
+		return &ctrl.Result{RequeueAfter: serverProbeInterval}, nil
+	}
+
+	if previous == nil || previous.Status != metav1.ConditionTrue || cr.Status.URL != url {
+		log.Info("Devfile registry server is responding")
+		r.Recorder.Eventf(cr, corev1.EventTypeNormal, eventReasonServerReady, "Devfile registry server is responding at %s", url)
 	}
+	metrics.SetProbeLatency(cr.Namespace, cr.Name, time.Since(start))
+	cr.Status.URL = url
+	setCondition(cr, registryv1beta1.ConditionServerReachable, metav1.ConditionTrue, reasonServerReachable, "Devfile registry server is responding at "+url)
 	return nil, nil
 }
@@ -0,0 +1,177 @@
+//
+// Copyright (c) 2020 Red Hat, Inc.
+// This program and the accompanying materials are made
+// available under the terms of the Eclipse Public License 2.0
+// which is available at https://www.eclipse.org/legal/epl-2.0/
+//
+// SPDX-License-Identifier: EPL-2.0
+//
+// Contributors:
+//   Red Hat, Inc. - initial API and implementation
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	registryv1beta1 "github.com/devfile/registry-operator/api/v1beta1"
+	"github.com/devfile/registry-operator/pkg/config"
+	"github.com/devfile/registry-operator/pkg/registry"
+)
+
+// storageFinalizer holds the deletion of a DevfileRegistry until its storage was reclaimed according to
+// spec.storage.reclaimPolicy
+const storageFinalizer = "registry.devfile.io/storage"
+
+// orphanedFromAnnotation is set on the persistent volume claim of a deleted DevfileRegistry to the UID of the
+// registry, once it stopped owning the claim. It lets the operator recognize the claim while it finishes reclaiming it.
+const orphanedFromAnnotation = "registry.devfile.io/orphaned-from"
+
+// snapshotPollInterval is how long to wait before checking on a volume snapshot that isn't ready yet
+const snapshotPollInterval = 5 * time.Second
+
+// ensureFinalizer adds the storage finalizer to the DevfileRegistry if it's missing
+func (r *DevfileRegistryReconciler) ensureFinalizer(ctx context.Context, cr *registryv1beta1.DevfileRegistry) error {
+	if controllerutil.ContainsFinalizer(cr, storageFinalizer) {
+		return nil
+	}
+	patch := client.MergeFrom(cr.DeepCopy())
+	controllerutil.AddFinalizer(cr, storageFinalizer)
+	return r.Patch(ctx, cr, patch)
+}
+
+// finalizeDevfileRegistry reclaims the storage of a DevfileRegistry that is being deleted, then removes the storage
+// finalizer so that the deletion can go ahead
+func (r *DevfileRegistryReconciler) finalizeDevfileRegistry(ctx context.Context, cr *registryv1beta1.DevfileRegistry) (*reconcile.Result, error) {
+	if !controllerutil.ContainsFinalizer(cr, storageFinalizer) {
+		return &ctrl.Result{}, nil
+	}
+
+	done, err := r.reclaimStorage(ctx, cr)
+	if err != nil {
+		setFailedCondition(cr, registryv1beta1.ConditionStorageReclaimed, err)
+		return &ctrl.Result{}, err
+	}
+	if !done {
+		return &ctrl.Result{RequeueAfter: snapshotPollInterval}, nil
+	}
+
+	patch := client.MergeFrom(cr.DeepCopy())
+	controllerutil.RemoveFinalizer(cr, storageFinalizer)
+	return &ctrl.Result{}, r.Patch(ctx, cr, patch)
+}
+
+// reclaimStorage applies the reclaim policy to the registry's persistent volume claim. It returns true once the claim
+// can be left to the garbage collector.
+func (r *DevfileRegistryReconciler) reclaimStorage(ctx context.Context, cr *registryv1beta1.DevfileRegistry) (bool, error) {
+	log := r.Log.WithValues("devfileregistry", types.NamespacedName{Name: cr.Name, Namespace: cr.Namespace})
+
+	pvc := &corev1.PersistentVolumeClaim{}
+	err := r.Get(ctx, types.NamespacedName{Name: registry.PVCName(cr.Name), Namespace: cr.Namespace}, pvc)
+	if errors.IsNotFound(err) || (err == nil && !metav1.IsControlledBy(pvc, cr) && pvc.Annotations[orphanedFromAnnotation] != string(cr.UID)) {
+		setCondition(cr, registryv1beta1.ConditionStorageReclaimed, metav1.ConditionTrue, reasonNoStorage, "The devfile registry has no persistent storage to reclaim")
+		return true, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	policy := registry.GetReclaimPolicy(cr)
+	if policy == registryv1beta1.ReclaimPolicySnapshot && !config.ControllerCfg.HasVolumeSnapshots() {
+		// Keeping the claim is the only way not to lose the registry's content
+		log.Info("Volume snapshots aren't supported by the cluster, retaining the PersistentVolumeClaim instead")
+		r.Recorder.Eventf(cr, corev1.EventTypeWarning, eventReasonSnapshotUnsupported, "Volume snapshots aren't supported by the cluster, retaining PersistentVolumeClaim %s instead", pvc.Name)
+		policy = registryv1beta1.ReclaimPolicyRetain
+	}
+
+	switch policy {
+	case registryv1beta1.ReclaimPolicyRetain:
+		if err := r.orphanPVC(ctx, cr, pvc); err != nil {
+			return false, err
+		}
+		r.Recorder.Eventf(cr, corev1.EventTypeNormal, eventReasonRetained, "Retained PersistentVolumeClaim %s", pvc.Name)
+		setCondition(cr, registryv1beta1.ConditionStorageReclaimed, metav1.ConditionTrue, reasonPVCRetained, "PersistentVolumeClaim "+pvc.Name+" was retained")
+		return true, nil
+	case registryv1beta1.ReclaimPolicySnapshot:
+		return r.snapshotPVC(ctx, cr, pvc)
+	default:
+		setCondition(cr, registryv1beta1.ConditionStorageReclaimed, metav1.ConditionTrue, reasonPVCDeleted, "PersistentVolumeClaim "+pvc.Name+" is deleted with the devfile registry")
+		return true, nil
+	}
+}
+
+// snapshotPVC takes a volume snapshot of the persistent volume claim and deletes the claim once the snapshot is ready.
+// The claim is orphaned first, so that neither the garbage collector nor a foreground deletion can remove it before
+// the snapshot is taken.
+func (r *DevfileRegistryReconciler) snapshotPVC(ctx context.Context, cr *registryv1beta1.DevfileRegistry, pvc *corev1.PersistentVolumeClaim) (bool, error) {
+	if err := r.orphanPVC(ctx, cr, pvc); err != nil {
+		return false, err
+	}
+
+	snapshot := &unstructured.Unstructured{}
+	snapshot.SetGroupVersionKind(registry.VolumeSnapshotGVK())
+	err := r.Get(ctx, types.NamespacedName{Name: registry.VolumeSnapshotName(cr.Name, cr.UID), Namespace: cr.Namespace}, snapshot)
+	if errors.IsNotFound(err) {
+		snapshot = registry.GenerateVolumeSnapshot(cr, registry.LabelsForDevfileRegistry(cr.Name))
+		if err := r.Create(ctx, snapshot); err != nil {
+			return false, err
+		}
+		r.Recorder.Eventf(cr, corev1.EventTypeNormal, eventReasonCreated, "Created VolumeSnapshot %s of PersistentVolumeClaim %s", snapshot.GetName(), pvc.Name)
+	} else if err != nil {
+		return false, err
+	}
+	// The claim is only deleted once its content is known to be saved
+	if !registry.IsVolumeSnapshotOf(snapshot, cr) {
+		return false, fmt.Errorf("VolumeSnapshot %s wasn't taken of PersistentVolumeClaim %s by this devfile registry, keeping the claim", snapshot.GetName(), pvc.Name)
+	}
+
+	ready, message := registry.IsVolumeSnapshotReady(snapshot)
+	if !ready {
+		if message != "" {
+			setCondition(cr, registryv1beta1.ConditionStorageReclaimed, metav1.ConditionFalse, reasonSnapshotFailed, "VolumeSnapshot "+snapshot.GetName()+" failed: "+message)
+		} else {
+			setCondition(cr, registryv1beta1.ConditionStorageReclaimed, metav1.ConditionFalse, reasonSnapshotPending, "Waiting for VolumeSnapshot "+snapshot.GetName()+" to be ready")
+		}
+		return false, nil
+	}
+
+	if err := r.Delete(ctx, pvc); err != nil && !errors.IsNotFound(err) {
+		return false, err
+	}
+	r.Recorder.Eventf(cr, corev1.EventTypeNormal, eventReasonDeleted, "Deleted PersistentVolumeClaim %s after taking VolumeSnapshot %s", pvc.Name, snapshot.GetName())
+	setCondition(cr, registryv1beta1.ConditionStorageReclaimed, metav1.ConditionTrue, reasonSnapshotReady, "PersistentVolumeClaim "+pvc.Name+" was saved to VolumeSnapshot "+snapshot.GetName())
+	return true, nil
+}
+
+// orphanPVC removes the DevfileRegistry's owner reference from the persistent volume claim, so that it isn't garbage
+// collected with the DevfileRegistry, and records the DevfileRegistry it came from
+func (r *DevfileRegistryReconciler) orphanPVC(ctx context.Context, cr *registryv1beta1.DevfileRegistry, pvc *corev1.PersistentVolumeClaim) error {
+	var ownerReferences []metav1.OwnerReference
+	for _, ref := range pvc.OwnerReferences {
+		if ref.UID != cr.UID {
+			ownerReferences = append(ownerReferences, ref)
+		}
+	}
+	if len(ownerReferences) == len(pvc.OwnerReferences) {
+		return nil
+	}
+
+	patch := client.MergeFrom(pvc.DeepCopy())
+	pvc.OwnerReferences = ownerReferences
+	if pvc.Annotations == nil {
+		pvc.Annotations = map[string]string{}
+	}
+	pvc.Annotations[orphanedFromAnnotation] = string(cr.UID)
+	return r.Patch(ctx, pvc, patch)
+}
@@ -0,0 +1,149 @@
+//
+// Copyright (c) 2020 Red Hat, Inc.
+// This program and the accompanying materials are made
+// available under the terms of the Eclipse Public License 2.0
+// which is available at https://www.eclipse.org/legal/epl-2.0/
+//
+// SPDX-License-Identifier: EPL-2.0
+//
+// Contributors:
+//   Red Hat, Inc. - initial API and implementation
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	registryv1beta1 "github.com/devfile/registry-operator/api/v1beta1"
+	"github.com/devfile/registry-operator/pkg/config"
+	"github.com/devfile/registry-operator/pkg/registry"
+)
+
+func TestReclaimStorage(t *testing.T) {
+	scheme := runtime.NewScheme()
+	clientgoscheme.AddToScheme(scheme)
+	registryv1beta1.AddToScheme(scheme)
+	config.ControllerCfg.SetVolumeSnapshotAPIVersion("v1")
+	defer config.ControllerCfg.SetVolumeSnapshotAPIVersion("")
+
+	cr := &registryv1beta1.DevfileRegistry{}
+	cr.Name = "test-registry"
+	cr.Namespace = "test-namespace"
+	cr.UID = "new-uid"
+
+	// snapshotOf returns a volume snapshot of the registry's claim, as taken by the registry with the given UID
+	snapshotOf := func(uid types.UID, ready bool) *unstructured.Unstructured {
+		source := cr.DeepCopy()
+		source.UID = uid
+		snapshot := registry.GenerateVolumeSnapshot(source, registry.LabelsForDevfileRegistry(cr.Name))
+		// A stale snapshot left behind by a registry with the same name, under the name the new registry uses
+		snapshot.SetName(registry.VolumeSnapshotName(cr.Name, cr.UID))
+		unstructured.SetNestedField(snapshot.Object, ready, "status", "readyToUse")
+		return snapshot
+	}
+
+	tests := []struct {
+		name        string
+		policy      registryv1beta1.DevfileRegistryReclaimPolicy
+		snapshot    *unstructured.Unstructured
+		wantDone    bool
+		wantErr     bool
+		wantPVC     bool
+		wantOwned   bool
+		wantSnapped bool
+	}{
+		{
+			name:      "Case 1: Delete leaves the claim to the garbage collector",
+			policy:    registryv1beta1.ReclaimPolicyDelete,
+			wantDone:  true,
+			wantPVC:   true,
+			wantOwned: true,
+		},
+		{
+			name:      "Case 2: Retain orphans the claim",
+			policy:    registryv1beta1.ReclaimPolicyRetain,
+			wantDone:  true,
+			wantPVC:   true,
+			wantOwned: false,
+		},
+		{
+			name:        "Case 3: Snapshot takes a snapshot and keeps the claim until it's ready",
+			policy:      registryv1beta1.ReclaimPolicySnapshot,
+			wantDone:    false,
+			wantPVC:     true,
+			wantSnapped: true,
+		},
+		{
+			name:        "Case 4: Snapshot deletes the claim once its snapshot is ready",
+			policy:      registryv1beta1.ReclaimPolicySnapshot,
+			snapshot:    snapshotOf(cr.UID, true),
+			wantDone:    true,
+			wantPVC:     false,
+			wantSnapped: true,
+		},
+		{
+			name:     "Case 5: Snapshot keeps the claim when a stale snapshot has the same name",
+			policy:   registryv1beta1.ReclaimPolicySnapshot,
+			snapshot: snapshotOf("old-uid", true),
+			wantErr:  true,
+			wantPVC:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testCR := cr.DeepCopy()
+			testCR.Spec.Storage.ReclaimPolicy = tt.policy
+			pvc := registry.GeneratePVC(testCR, scheme, registry.LabelsForDevfileRegistry(cr.Name))
+			objects := []client.Object{testCR, pvc}
+			if tt.snapshot != nil {
+				objects = append(objects, tt.snapshot)
+			}
+			r := &DevfileRegistryReconciler{
+				Client:   fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build(),
+				Log:      ctrl.Log.WithName("test"),
+				Scheme:   scheme,
+				Recorder: record.NewFakeRecorder(10),
+			}
+
+			done, err := r.reclaimStorage(context.Background(), testCR)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("TestReclaimStorage error: unexpected error, expected: %v got: %v", tt.wantErr, err)
+			}
+			if done != tt.wantDone {
+				t.Errorf("TestReclaimStorage error: done mismatch, expected: %v got: %v", tt.wantDone, done)
+			}
+
+			gotPVC := &corev1.PersistentVolumeClaim{}
+			err = r.Get(context.Background(), types.NamespacedName{Name: pvc.Name, Namespace: pvc.Namespace}, gotPVC)
+			if err != nil && !errors.IsNotFound(err) {
+				t.Fatalf("TestReclaimStorage error: failed to get the PersistentVolumeClaim: %v", err)
+			}
+			if (err == nil) != tt.wantPVC {
+				t.Fatalf("TestReclaimStorage error: PersistentVolumeClaim mismatch, expected: %v got: %v", tt.wantPVC, err == nil)
+			}
+			if tt.wantPVC && tt.wantOwned != (len(gotPVC.OwnerReferences) > 0) {
+				t.Errorf("TestReclaimStorage error: owner mismatch, expected: %v got: %v", tt.wantOwned, gotPVC.OwnerReferences)
+			}
+
+			snapshot := &unstructured.Unstructured{}
+			snapshot.SetGroupVersionKind(registry.VolumeSnapshotGVK())
+			err = r.Get(context.Background(), types.NamespacedName{Name: registry.VolumeSnapshotName(cr.Name, cr.UID), Namespace: cr.Namespace}, snapshot)
+			snapped := err == nil && registry.IsVolumeSnapshotOf(snapshot, testCR)
+			if snapped != tt.wantSnapped {
+				t.Errorf("TestReclaimStorage error: snapshot mismatch, expected: %v got: %v", tt.wantSnapped, snapped)
+			}
+		})
+	}
+}
@@ -0,0 +1,169 @@
+//
+// Copyright (c) 2020 Red Hat, Inc.
+// This program and the accompanying materials are made
+// available under the terms of the Eclipse Public License 2.0
+// which is available at https://www.eclipse.org/legal/epl-2.0/
+//
+// SPDX-License-Identifier: EPL-2.0
+//
+// Contributors:
+//   Red Hat, Inc. - initial API and implementation
+
+package controllers
+
+import (
+	"context"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	registryv1beta1 "github.com/devfile/registry-operator/api/v1beta1"
+	"github.com/devfile/registry-operator/pkg/config"
+	"github.com/devfile/registry-operator/pkg/metrics"
+	"github.com/devfile/registry-operator/pkg/registry"
+)
+
+// Reasons used for the GarbageCollection condition of the DevfileRegistry
+const (
+	reasonGCDisabled  = "GarbageCollectionDisabled"
+	reasonGCScheduled = "GarbageCollectionScheduled"
+	reasonGCRunning   = "GarbageCollectionRunning"
+	reasonGCFailed    = "GarbageCollectionFailed"
+)
+
+// Reasons used for the events emitted when a garbage collection job finishes
+const (
+	eventReasonGCSucceeded = "GarbageCollected"
+	eventReasonGCFailed    = "GarbageCollectionFailed"
+)
+
+// gcCronJobResource describes the cron job collecting the garbage of the OCI registry. It's deleted when garbage
+// collection isn't scheduled. It's served through the batch/v1beta1 API on clusters that predate batch/v1 cron jobs.
+func (r *DevfileRegistryReconciler) gcCronJobResource(cr *registryv1beta1.DevfileRegistry, labels map[string]string) childResource {
+	child := childResource{
+		kind:          "CronJob",
+		name:          registry.GarbageCollectionCronJobName(cr.Name),
+		step:          metrics.StepGarbageCollection,
+		newObject:     func() client.Object { return &batchv1.CronJob{} },
+		generate:      func() client.Object { return registry.GenerateGarbageCollectionCronJob(cr, r.Scheme, labels) },
+		disabled:      !registry.IsGarbageCollectionEnabled(cr),
+		conditionType: registryv1beta1.ConditionGarbageCollection,
+		ready: func(obj client.Object) (metav1.ConditionStatus, string, string) {
+			status := cr.Status.GarbageCollection
+			switch {
+			case registry.IsReadOnly(cr):
+				return metav1.ConditionTrue, reasonGCRunning, "Job " + status.ActiveJob + " is collecting garbage, the OCI registry is read-only until it finishes"
+			case status != nil && status.LastRunFailed:
+				return metav1.ConditionFalse, reasonGCFailed, "Job " + status.LastJob + " failed to collect garbage"
+			}
+			return metav1.ConditionTrue, reasonGCScheduled, "Garbage is collected on schedule " + cr.Spec.OCIRegistry.GarbageCollection.Schedule
+		},
+		disabledReason:  reasonGCDisabled,
+		disabledMessage: "Garbage collection of the OCI registry isn't scheduled",
+	}
+	if !config.ControllerCfg.HasCronJobV1() {
+		child.newObject = func() client.Object { return &batchv1beta1.CronJob{} }
+		child.generate = func() client.Object { return registry.GenerateGarbageCollectionCronJobV1beta1(cr, r.Scheme, labels) }
+	}
+	return child
+}
+
+// trackGarbageCollection records the garbage collection jobs of the DevfileRegistry in its status. While a job is
+// active the OCI registry is configured in read-only mode, and the job is signalled once the deployment rolled out
+// with that configuration. It has to run before the OCI registry configuration is generated.
+func (r *DevfileRegistryReconciler) trackGarbageCollection(ctx context.Context, cr *registryv1beta1.DevfileRegistry) error {
+	if !registry.IsGarbageCollectionEnabled(cr) && cr.Status.GarbageCollection == nil {
+		return nil
+	}
+
+	jobs := &batchv1.JobList{}
+	if err := r.List(ctx, jobs, client.InNamespace(cr.Namespace), client.MatchingLabels(registry.LabelsForGarbageCollection(cr.Name))); err != nil {
+		return err
+	}
+	status := &registryv1beta1.DevfileRegistryGarbageCollectionStatus{}
+	if cr.Status.GarbageCollection != nil {
+		status = cr.Status.GarbageCollection.DeepCopy()
+	}
+
+	// Only the latest job that finished since the last recorded run is reported
+	var activeJob, lastJob *batchv1.Job
+	var lastFailed bool
+	var lastTime metav1.Time
+	for i := range jobs.Items {
+		job := &jobs.Items[i]
+		finished, failed, finishTime := registry.GetJobResult(job)
+		if !finished {
+			if job.DeletionTimestamp.IsZero() {
+				activeJob = job
+			}
+			continue
+		}
+		if status.LastRunTime != nil && !finishTime.After(status.LastRunTime.Time) {
+			continue
+		}
+		if lastJob == nil || finishTime.After(lastTime.Time) {
+			lastJob, lastFailed, lastTime = job, failed, finishTime
+		}
+	}
+
+	if lastJob != nil {
+		status.LastJob = lastJob.Name
+		status.LastRunTime = &lastTime
+		status.LastRunFailed = lastFailed
+		status.LastFreedBytes = nil
+		if lastFailed {
+			r.Recorder.Eventf(cr, corev1.EventTypeWarning, eventReasonGCFailed, "Job %s failed to collect the garbage of the OCI registry", lastJob.Name)
+		} else {
+			// The job controller labels the pods of a job with its name. Pods aren't cached by the manager, so they're
+			// read from the API server.
+			pods := &corev1.PodList{}
+			if err := r.APIReader.List(ctx, pods, client.InNamespace(cr.Namespace), client.MatchingLabels{"job-name": lastJob.Name}); err != nil {
+				return err
+			}
+			status.LastFreedBytes = registry.GetFreedBytes(pods.Items)
+			if status.LastFreedBytes != nil {
+				r.Recorder.Eventf(cr, corev1.EventTypeNormal, eventReasonGCSucceeded, "Job %s collected the garbage of the OCI registry, freeing %d bytes", lastJob.Name, *status.LastFreedBytes)
+			} else {
+				r.Recorder.Eventf(cr, corev1.EventTypeNormal, eventReasonGCSucceeded, "Job %s collected the garbage of the OCI registry", lastJob.Name)
+			}
+		}
+	}
+
+	status.ActiveJob = ""
+	status.ReadOnly = false
+	if activeJob != nil {
+		status.ActiveJob = activeJob.Name
+	}
+	cr.Status.GarbageCollection = status
+	if activeJob == nil {
+		return nil
+	}
+
+	// The deployment is rolled with the read-only configuration further down the reconcile, and its rollout
+	// triggers another one
+	dep := &appsv1.Deployment{}
+	err := r.Get(ctx, types.NamespacedName{Name: registry.DeploymentName(cr.Name), Namespace: cr.Namespace}, dep)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	status.ReadOnly = err == nil && registry.IsReadOnlyRolledOut(cr, dep)
+	return nil
+}
+
+// registriesForGCJob maps a garbage collection job to the DevfileRegistry it collects the garbage of, so that the
+// registry is switched to read-only mode when the job starts and back once it finishes
+func (r *DevfileRegistryReconciler) registriesForGCJob(obj client.Object) []reconcile.Request {
+	labels := obj.GetLabels()
+	name, ok := labels["devfileregistry_cr"]
+	if !ok || labels["app"] != registry.LabelsForGarbageCollection(name)["app"] {
+		return nil
+	}
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: name, Namespace: obj.GetNamespace()}}}
+}
@@ -0,0 +1,193 @@
+//
+// Copyright (c) 2020 Red Hat, Inc.
+// This program and the accompanying materials are made
+// available under the terms of the Eclipse Public License 2.0
+// which is available at https://www.eclipse.org/legal/epl-2.0/
+//
+// SPDX-License-Identifier: EPL-2.0
+//
+// Contributors:
+//   Red Hat, Inc. - initial API and implementation
+
+package controllers
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	registryv1beta1 "github.com/devfile/registry-operator/api/v1beta1"
+	"github.com/devfile/registry-operator/pkg/metrics"
+)
+
+// Reasons used for the events emitted on the DevfileRegistry
+const (
+	eventReasonCreated             = "Created"
+	eventReasonUpdated             = "Updated"
+	eventReasonDeleted             = "Deleted"
+	eventReasonGetFailed           = "GetFailed"
+	eventReasonSyncFailed          = "SyncFailed"
+	eventReasonDeleteFailed        = "DeleteFailed"
+	eventReasonServerReady         = "ServerReady"
+	eventReasonServerUnreachable   = "ServerUnreachable"
+	eventReasonStatusUpdateFailed  = "StatusUpdateFailed"
+	eventReasonRetained            = "Retained"
+	eventReasonSnapshotUnsupported = "SnapshotUnsupported"
+)
+
+// mergeStrategy brings the object on the cluster in line with its desired state. existing is nil when the object
+// doesn't exist yet. It returns the object as it is on the cluster afterwards, and whether it was changed.
+type mergeStrategy func(ctx context.Context, r *DevfileRegistryReconciler, existing client.Object, desired client.Object) (client.Object, bool, error)
+
+// applyStrategy keeps every field the operator sets in sync with the desired state through server-side apply
+func applyStrategy(ctx context.Context, r *DevfileRegistryReconciler, existing client.Object, desired client.Object) (client.Object, bool, error) {
+	if err := r.applyObject(ctx, desired); err != nil {
+		return nil, false, err
+	}
+	// The resource version only moves when the apply changed something
+	changed := existing == nil || existing.GetResourceVersion() != desired.GetResourceVersion()
+	return desired, changed, nil
+}
+
+// createOnlyStrategy creates the object when it's missing, but leaves it untouched once it exists
+func createOnlyStrategy(ctx context.Context, r *DevfileRegistryReconciler, existing client.Object, desired client.Object) (client.Object, bool, error) {
+	if existing != nil {
+		return existing, false, nil
+	}
+	if err := r.Create(ctx, desired); err != nil {
+		return nil, false, err
+	}
+	return desired, true, nil
+}
+
+// issueStrategy returns a strategy for secrets holding generated credentials. issue returns the data of the secret from
+// the existing one, which is nil when the secret doesn't exist yet, and whether it has to be written to the cluster.
+func issueStrategy(issue func(existing *corev1.Secret) (map[string][]byte, bool, error)) mergeStrategy {
+	return func(ctx context.Context, r *DevfileRegistryReconciler, existing client.Object, desired client.Object) (client.Object, bool, error) {
+		var existingSecret *corev1.Secret
+		if existing != nil {
+			existingSecret = existing.(*corev1.Secret)
+		}
+		data, changed, err := issue(existingSecret)
+		if err != nil {
+			return nil, false, err
+		}
+		secret := desired.(*corev1.Secret)
+		secret.Data = data
+		if !changed {
+			// Still apply the rest of the secret, which only moves the resource version when it drifted
+			return applyStrategy(ctx, r, existing, secret)
+		}
+		if err := r.applyObject(ctx, secret); err != nil {
+			return nil, false, err
+		}
+		return secret, true, nil
+	}
+}
+
+// childResource describes an object owned by a DevfileRegistry that the operator keeps in sync with it
+type childResource struct {
+	// kind is the kind of the object, as shown in logs, events and condition messages
+	kind string
+	// name is the name of the object, in the namespace of the DevfileRegistry
+	name string
+	// step is the reconcile step errors on the child are counted under in the metrics
+	step string
+	// newObject returns an empty object of the child's type, to read the object from the cluster into
+	newObject func() client.Object
+	// generate returns the desired state of the object, with the DevfileRegistry set as its controller. It's only
+	// called when the child is enabled.
+	generate func() client.Object
+	// strategy updates the object on the cluster, applyStrategy is used when it's nil
+	strategy mergeStrategy
+	// disabled children are deleted from the cluster if the DevfileRegistry controls them
+	disabled bool
+
+	// conditionType is the condition of the DevfileRegistry reporting on the child
+	conditionType string
+	// ready reports the status, reason and message of the condition from the object on the cluster. When nil, the
+	// condition is true as soon as the object is in sync.
+	ready func(obj client.Object) (metav1.ConditionStatus, string, string)
+	// disabledReason and disabledMessage are reported on the condition while the child is disabled. The condition is
+	// left as is when disabledReason is empty.
+	disabledReason  string
+	disabledMessage string
+}
+
+// reconcileChild creates, updates or deletes a child resource of the DevfileRegistry so that it matches its desired
+// state, emitting an event for every change and reporting the outcome on the child's condition
+func (r *DevfileRegistryReconciler) reconcileChild(ctx context.Context, cr *registryv1beta1.DevfileRegistry, child childResource) (*reconcile.Result, error) {
+	log := r.Log.WithValues("devfileregistry", types.NamespacedName{Name: cr.Name, Namespace: cr.Namespace}, "kind", child.kind, "name", child.name)
+
+	existing := child.newObject()
+	err := r.Get(ctx, types.NamespacedName{Name: child.name, Namespace: cr.Namespace}, existing)
+	if err != nil && !errors.IsNotFound(err) {
+		log.Error(err, "Failed to get child resource")
+		r.Recorder.Eventf(cr, corev1.EventTypeWarning, eventReasonGetFailed, "Failed to get %s %s: %v", child.kind, child.name, err)
+		metrics.IncReconcileErrors(cr.Namespace, cr.Name, child.step)
+		setFailedCondition(cr, child.conditionType, err)
+		return &ctrl.Result{}, err
+	}
+	if errors.IsNotFound(err) {
+		existing = nil
+	}
+
+	if child.disabled {
+		// Only clean up objects this DevfileRegistry created, never ones that happen to have the same name
+		if existing != nil && metav1.IsControlledBy(existing, cr) {
+			log.Info("Deleting disabled child resource")
+			if err := r.Delete(ctx, existing); err != nil && !errors.IsNotFound(err) {
+				log.Error(err, "Failed to delete child resource")
+				r.Recorder.Eventf(cr, corev1.EventTypeWarning, eventReasonDeleteFailed, "Failed to delete %s %s: %v", child.kind, child.name, err)
+				metrics.IncReconcileErrors(cr.Namespace, cr.Name, child.step)
+				setFailedCondition(cr, child.conditionType, err)
+				return &ctrl.Result{}, err
+			}
+			if child.disabledMessage != "" {
+				r.Recorder.Eventf(cr, corev1.EventTypeNormal, eventReasonDeleted, "Deleted %s %s: %s", child.kind, child.name, child.disabledMessage)
+			} else {
+				r.Recorder.Eventf(cr, corev1.EventTypeNormal, eventReasonDeleted, "Deleted %s %s", child.kind, child.name)
+			}
+		}
+		if child.disabledReason != "" {
+			setCondition(cr, child.conditionType, metav1.ConditionTrue, child.disabledReason, child.disabledMessage)
+		}
+		return nil, nil
+	}
+
+	strategy := child.strategy
+	if strategy == nil {
+		strategy = applyStrategy
+	}
+	obj, changed, err := strategy(ctx, r, existing, child.generate())
+	if err != nil {
+		log.Error(err, "Failed to sync child resource")
+		r.Recorder.Eventf(cr, corev1.EventTypeWarning, eventReasonSyncFailed, "Failed to sync %s %s: %v", child.kind, child.name, err)
+		metrics.IncReconcileErrors(cr.Namespace, cr.Name, child.step)
+		setFailedCondition(cr, child.conditionType, err)
+		return &ctrl.Result{}, err
+	}
+	if changed {
+		if existing == nil {
+			log.Info("Created child resource")
+			r.Recorder.Eventf(cr, corev1.EventTypeNormal, eventReasonCreated, "Created %s %s", child.kind, child.name)
+		} else {
+			log.Info("Updated child resource")
+			r.Recorder.Eventf(cr, corev1.EventTypeNormal, eventReasonUpdated, "Updated %s %s", child.kind, child.name)
+		}
+	}
+
+	if child.ready != nil {
+		status, reason, message := child.ready(obj)
+		setCondition(cr, child.conditionType, status, reason, message)
+	} else {
+		setCondition(cr, child.conditionType, metav1.ConditionTrue, reasonReconciled, child.kind+" "+child.name+" is up to date")
+	}
+	return nil, nil
+}
@@ -0,0 +1,156 @@
+//
+// Copyright (c) 2020 Red Hat, Inc.
+// This program and the accompanying materials are made
+// available under the terms of the Eclipse Public License 2.0
+// which is available at https://www.eclipse.org/legal/epl-2.0/
+//
+// SPDX-License-Identifier: EPL-2.0
+//
+// Contributors:
+//   Red Hat, Inc. - initial API and implementation
+
+package controllers
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	registryv1beta1 "github.com/devfile/registry-operator/api/v1beta1"
+	"github.com/devfile/registry-operator/pkg/metrics"
+	"github.com/devfile/registry-operator/pkg/registry"
+)
+
+// Reasons used for the conditions on the DevfileRegistry status
+const (
+	// reasonReconcileFailed is used whenever the operator failed to get, create or update a resource. Any condition
+	// with this reason moves the DevfileRegistry into the Failed phase.
+	reasonReconcileFailed = "ReconcileFailed"
+	reasonCreating        = "Creating"
+	reasonReconciled      = "Reconciled"
+	reasonStorageDisabled = "StorageDisabled"
+	reasonObjectStorage   = "ObjectStorage"
+	reasonPVCPending      = "PVCPending"
+	reasonPVCBound        = "PVCBound"
+	reasonPVCResizing     = "PVCResizing"
+	reasonPVCImmutable    = "PVCImmutable"
+	reasonShrinkRejected  = "ShrinkRejected"
+	reasonNoExpansion     = "ExpansionUnsupported"
+	reasonDeploymentReady = "MinimumReplicasAvailable"
+	reasonDeploymentWait  = "MinimumReplicasUnavailable"
+	reasonHostPending     = "HostPending"
+	reasonInClusterOnly   = "InClusterOnly"
+	reasonCertPending     = "CertificatePending"
+	reasonServerReachable = "ServerReachable"
+	reasonServerDown      = "ServerUnreachable"
+	reasonAllReady        = "AllConditionsReady"
+	reasonDeleting        = "Deleting"
+	reasonNoStorage       = "NoStorage"
+	reasonPVCDeleted      = "PVCDeleted"
+	reasonPVCRetained     = "PVCRetained"
+	reasonSnapshotPending = "SnapshotPending"
+	reasonSnapshotReady   = "SnapshotReady"
+	reasonSnapshotFailed  = "SnapshotFailed"
+	reasonUsageNormal     = "UsageBelowThreshold"
+	reasonUsageHigh       = "UsageAboveThreshold"
+)
+
+// readinessConditions are the conditions that must all be true for the DevfileRegistry to be Ready, in the order
+// they are reconciled
+var readinessConditions = []string{
+	registryv1beta1.ConditionServiceReady,
+	registryv1beta1.ConditionStorageReady,
+	registryv1beta1.ConditionDeploymentAvailable,
+	registryv1beta1.ConditionExposed,
+	registryv1beta1.ConditionServerReachable,
+}
+
+// setCondition records a condition on the DevfileRegistry. It's only written to the cluster by updateStatus.
+func setCondition(cr *registryv1beta1.DevfileRegistry, conditionType string, status metav1.ConditionStatus, reason string, message string) {
+	meta.SetStatusCondition(&cr.Status.Conditions, metav1.Condition{
+		Type:               conditionType,
+		Status:             status,
+		ObservedGeneration: cr.Generation,
+		Reason:             reason,
+		Message:            message,
+	})
+}
+
+// setFailedCondition marks a condition as false because the operator hit an error reconciling the matching resource
+func setFailedCondition(cr *registryv1beta1.DevfileRegistry, conditionType string, err error) {
+	setCondition(cr, conditionType, metav1.ConditionFalse, reasonReconcileFailed, err.Error())
+}
+
+// updateStatus summarizes the conditions into the Ready condition and phase, and writes the status to the cluster if
+// it differs from oldStatus
+func (r *DevfileRegistryReconciler) updateStatus(ctx context.Context, cr *registryv1beta1.DevfileRegistry, oldStatus *registryv1beta1.DevfileRegistryStatus) error {
+	setReadyCondition(cr)
+	cr.Status.Phase = computePhase(cr)
+	cr.Status.ObservedGeneration = cr.Generation
+	recordMetrics(cr)
+
+	if equality.Semantic.DeepEqual(oldStatus, &cr.Status) {
+		return nil
+	}
+	return r.Status().Update(ctx, cr)
+}
+
+// recordMetrics publishes the phase, time to ready and storage capacity of the DevfileRegistry
+func recordMetrics(cr *registryv1beta1.DevfileRegistry) {
+	metrics.SetPhase(cr.Namespace, cr.Name, cr.Status.Phase)
+
+	ready := meta.FindStatusCondition(cr.Status.Conditions, registryv1beta1.ConditionReady)
+	if ready != nil && ready.Status == metav1.ConditionTrue {
+		metrics.SetTimeToReady(cr.Namespace, cr.Name, ready.LastTransitionTime.Sub(cr.CreationTimestamp.Time))
+	}
+
+	if registry.IsPVCEnabled(cr) {
+		if size, err := registry.ParsePVCSize(cr); err == nil {
+			metrics.SetStorageCapacity(cr.Namespace, cr.Name, size.Value())
+		}
+	} else {
+		metrics.DeleteStorageCapacity(cr.Namespace, cr.Name)
+	}
+}
+
+// setReadyCondition sets the Ready condition to true if every other condition is true, otherwise it's set to false
+// with the reason and message of the first condition that isn't
+func setReadyCondition(cr *registryv1beta1.DevfileRegistry) {
+	if !cr.DeletionTimestamp.IsZero() {
+		setCondition(cr, registryv1beta1.ConditionReady, metav1.ConditionFalse, reasonDeleting, "The devfile registry is being deleted")
+		return
+	}
+	for _, conditionType := range readinessConditions {
+		condition := meta.FindStatusCondition(cr.Status.Conditions, conditionType)
+		if condition == nil {
+			setCondition(cr, registryv1beta1.ConditionReady, metav1.ConditionFalse, reasonCreating, "Waiting for "+conditionType)
+			return
+		}
+		if condition.Status != metav1.ConditionTrue {
+			setCondition(cr, registryv1beta1.ConditionReady, metav1.ConditionFalse, condition.Reason, condition.Message)
+			return
+		}
+	}
+	setCondition(cr, registryv1beta1.ConditionReady, metav1.ConditionTrue, reasonAllReady, "The devfile registry is ready")
+}
+
+// computePhase derives the phase of the DevfileRegistry from its conditions
+func computePhase(cr *registryv1beta1.DevfileRegistry) registryv1beta1.DevfileRegistryPhase {
+	if !cr.DeletionTimestamp.IsZero() {
+		return registryv1beta1.DevfileRegistryPhaseDeleting
+	}
+	if meta.IsStatusConditionTrue(cr.Status.Conditions, registryv1beta1.ConditionReady) {
+		return registryv1beta1.DevfileRegistryPhaseReady
+	}
+	for _, condition := range cr.Status.Conditions {
+		if condition.Reason == reasonReconcileFailed {
+			return registryv1beta1.DevfileRegistryPhaseFailed
+		}
+	}
+	if meta.FindStatusCondition(cr.Status.Conditions, registryv1beta1.ConditionServiceReady) == nil {
+		return registryv1beta1.DevfileRegistryPhasePending
+	}
+	return registryv1beta1.DevfileRegistryPhaseDeploying
+}
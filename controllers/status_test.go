@@ -0,0 +1,178 @@
+//
+// Copyright (c) 2020 Red Hat, Inc.
+// This program and the accompanying materials are made
+// available under the terms of the Eclipse Public License 2.0
+// which is available at https://www.eclipse.org/legal/epl-2.0/
+//
+// SPDX-License-Identifier: EPL-2.0
+//
+// Contributors:
+//   Red Hat, Inc. - initial API and implementation
+
+package controllers
+
+import (
+	"fmt"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	registryv1beta1 "github.com/devfile/registry-operator/api/v1beta1"
+)
+
+// allReady returns conditions with every readiness condition true
+func allReady() []metav1.Condition {
+	var conditions []metav1.Condition
+	for _, conditionType := range readinessConditions {
+		conditions = append(conditions, metav1.Condition{Type: conditionType, Status: metav1.ConditionTrue, Reason: reasonReconciled})
+	}
+	return conditions
+}
+
+func TestSetCondition(t *testing.T) {
+	cr := &registryv1beta1.DevfileRegistry{}
+	cr.Generation = 1
+	setCondition(cr, registryv1beta1.ConditionServiceReady, metav1.ConditionFalse, reasonCreating, "Creating")
+	created := meta.FindStatusCondition(cr.Status.Conditions, registryv1beta1.ConditionServiceReady)
+	if created == nil || created.ObservedGeneration != 1 {
+		t.Fatalf("TestSetCondition error: expected a condition observing generation 1, got: %+v", created)
+	}
+	transitioned := created.LastTransitionTime
+
+	// A new generation is observed without the condition transitioning
+	cr.Generation = 2
+	setCondition(cr, registryv1beta1.ConditionServiceReady, metav1.ConditionFalse, reasonCreating, "Still creating")
+	updated := meta.FindStatusCondition(cr.Status.Conditions, registryv1beta1.ConditionServiceReady)
+	if updated.ObservedGeneration != 2 {
+		t.Errorf("TestSetCondition error: observed generation mismatch, expected: %v got: %v", 2, updated.ObservedGeneration)
+	}
+	if updated.Message != "Still creating" {
+		t.Errorf("TestSetCondition error: message mismatch, expected: %v got: %v", "Still creating", updated.Message)
+	}
+	if !updated.LastTransitionTime.Equal(&transitioned) {
+		t.Errorf("TestSetCondition error: transition time moved without a transition, expected: %v got: %v", transitioned, updated.LastTransitionTime)
+	}
+
+	err := fmt.Errorf("failed to create the Service")
+	setFailedCondition(cr, registryv1beta1.ConditionServiceReady, err)
+	failed := meta.FindStatusCondition(cr.Status.Conditions, registryv1beta1.ConditionServiceReady)
+	if failed.Status != metav1.ConditionFalse || failed.Reason != reasonReconcileFailed || failed.Message != err.Error() {
+		t.Errorf("TestSetCondition error: expected a failed condition, got: %+v", failed)
+	}
+	if len(cr.Status.Conditions) != 1 {
+		t.Errorf("TestSetCondition error: expected a single condition, got: %+v", cr.Status.Conditions)
+	}
+}
+
+func TestSetReadyCondition(t *testing.T) {
+	now := metav1.Now()
+	notBound := allReady()
+	meta.SetStatusCondition(&notBound, metav1.Condition{Type: registryv1beta1.ConditionStorageReady, Status: metav1.ConditionFalse, Reason: reasonPVCPending, Message: "Not bound"})
+
+	tests := []struct {
+		name              string
+		conditions        []metav1.Condition
+		deletionTimestamp *metav1.Time
+		wantStatus        metav1.ConditionStatus
+		wantReason        string
+	}{
+		{
+			name:       "Case 1: No conditions yet",
+			wantStatus: metav1.ConditionFalse,
+			wantReason: reasonCreating,
+		},
+		{
+			name:       "Case 2: Reason of the first condition that isn't true",
+			conditions: notBound,
+			wantStatus: metav1.ConditionFalse,
+			wantReason: reasonPVCPending,
+		},
+		{
+			name:       "Case 3: Every condition true",
+			conditions: allReady(),
+			wantStatus: metav1.ConditionTrue,
+			wantReason: reasonAllReady,
+		},
+		{
+			name:              "Case 4: Registry being deleted",
+			conditions:        allReady(),
+			deletionTimestamp: &now,
+			wantStatus:        metav1.ConditionFalse,
+			wantReason:        reasonDeleting,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cr := &registryv1beta1.DevfileRegistry{}
+			cr.Generation = 3
+			cr.DeletionTimestamp = tt.deletionTimestamp
+			cr.Status.Conditions = tt.conditions
+			setReadyCondition(cr)
+			ready := meta.FindStatusCondition(cr.Status.Conditions, registryv1beta1.ConditionReady)
+			if ready == nil {
+				t.Fatalf("TestSetReadyCondition error: Ready condition wasn't set")
+			}
+			if ready.Status != tt.wantStatus || ready.Reason != tt.wantReason {
+				t.Errorf("TestSetReadyCondition error: Ready condition mismatch, expected: %v %v got: %v %v", tt.wantStatus, tt.wantReason, ready.Status, ready.Reason)
+			}
+			if ready.ObservedGeneration != cr.Generation {
+				t.Errorf("TestSetReadyCondition error: observed generation mismatch, expected: %v got: %v", cr.Generation, ready.ObservedGeneration)
+			}
+		})
+	}
+}
+
+func TestComputePhase(t *testing.T) {
+	now := metav1.Now()
+	ready := append(allReady(), metav1.Condition{Type: registryv1beta1.ConditionReady, Status: metav1.ConditionTrue, Reason: reasonAllReady})
+
+	tests := []struct {
+		name              string
+		conditions        []metav1.Condition
+		deletionTimestamp *metav1.Time
+		want              registryv1beta1.DevfileRegistryPhase
+	}{
+		{
+			name: "Case 1: Nothing reconciled yet",
+			want: registryv1beta1.DevfileRegistryPhasePending,
+		},
+		{
+			name: "Case 2: Service created, waiting for the deployment",
+			conditions: []metav1.Condition{
+				{Type: registryv1beta1.ConditionServiceReady, Status: metav1.ConditionTrue, Reason: reasonReconciled},
+				{Type: registryv1beta1.ConditionDeploymentAvailable, Status: metav1.ConditionFalse, Reason: reasonDeploymentWait},
+			},
+			want: registryv1beta1.DevfileRegistryPhaseDeploying,
+		},
+		{
+			name: "Case 3: Resource failed to reconcile",
+			conditions: []metav1.Condition{
+				{Type: registryv1beta1.ConditionServiceReady, Status: metav1.ConditionTrue, Reason: reasonReconciled},
+				{Type: registryv1beta1.ConditionDeploymentAvailable, Status: metav1.ConditionFalse, Reason: reasonReconcileFailed},
+			},
+			want: registryv1beta1.DevfileRegistryPhaseFailed,
+		},
+		{
+			name:       "Case 4: Ready",
+			conditions: ready,
+			want:       registryv1beta1.DevfileRegistryPhaseReady,
+		},
+		{
+			name:              "Case 5: Being deleted",
+			conditions:        ready,
+			deletionTimestamp: &now,
+			want:              registryv1beta1.DevfileRegistryPhaseDeleting,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cr := &registryv1beta1.DevfileRegistry{}
+			cr.DeletionTimestamp = tt.deletionTimestamp
+			cr.Status.Conditions = tt.conditions
+			if phase := computePhase(cr); phase != tt.want {
+				t.Errorf("TestComputePhase error: phase mismatch, expected: %v got: %v", tt.want, phase)
+			}
+		})
+	}
+}
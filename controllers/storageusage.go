@@ -0,0 +1,160 @@
+//
+// Copyright (c) 2020 Red Hat, Inc.
+// This program and the accompanying materials are made
+// available under the terms of the Eclipse Public License 2.0
+// which is available at https://www.eclipse.org/legal/epl-2.0/
+//
+// SPDX-License-Identifier: EPL-2.0
+//
+// Contributors:
+//   Red Hat, Inc. - initial API and implementation
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	registryv1beta1 "github.com/devfile/registry-operator/api/v1beta1"
+	"github.com/devfile/registry-operator/pkg/metrics"
+	"github.com/devfile/registry-operator/pkg/registry"
+	"github.com/devfile/registry-operator/pkg/util"
+)
+
+// Reasons used for the events emitted when the storage of the DevfileRegistry is nearly full
+const (
+	eventReasonStorageNearlyFull = "StorageNearlyFull"
+	eventReasonStorageExpanded   = "StorageExpanded"
+)
+
+// ensureStorageUsage measures how much of the registry's persistent volume is used, at most once every
+// StorageUsageInterval, and sets the StorageNearlyFull condition. A nearly full claim is expanded when
+// spec.storage.autoExpansion is set. Failing to measure the usage doesn't fail the reconcile.
+func (r *DevfileRegistryReconciler) ensureStorageUsage(ctx context.Context, cr *registryv1beta1.DevfileRegistry) (*reconcile.Result, error) {
+	log := r.Log.WithValues("devfileregistry", types.NamespacedName{Name: cr.Name, Namespace: cr.Namespace})
+
+	if !registry.IsPVCEnabled(cr) {
+		cr.Status.Storage = nil
+		meta.RemoveStatusCondition(&cr.Status.Conditions, registryv1beta1.ConditionStorageNearlyFull)
+		metrics.DeleteStorageUsed(cr.Namespace, cr.Name)
+		return nil, nil
+	}
+	if r.StorageUsageInterval <= 0 {
+		return nil, nil
+	}
+
+	status := &registryv1beta1.DevfileRegistryStorageStatus{}
+	if cr.Status.Storage != nil {
+		status = cr.Status.Storage.DeepCopy()
+	}
+	if status.LastMeasuredTime != nil {
+		if wait := time.Until(status.LastMeasuredTime.Add(r.StorageUsageInterval)); wait > 0 {
+			return &ctrl.Result{RequeueAfter: wait}, nil
+		}
+	}
+
+	used, capacity, err := r.measureStorage(ctx, cr)
+	if err != nil {
+		log.Info("Failed to measure the storage usage", "error", err.Error())
+		return &ctrl.Result{RequeueAfter: r.StorageUsageInterval}, nil
+	}
+	now := metav1.Now()
+	status.UsedBytes = used
+	status.CapacityBytes = capacity
+	status.LastMeasuredTime = &now
+	cr.Status.Storage = status
+	metrics.SetStorageUsed(cr.Namespace, cr.Name, used)
+
+	threshold := registry.GetUsageAlertThreshold(cr)
+	usage := fmt.Sprintf("%d of %d bytes (%d%%) of the persistent volume are used", used, capacity, used*100/capacity)
+	if used*100 < capacity*int64(threshold) {
+		setCondition(cr, registryv1beta1.ConditionStorageNearlyFull, metav1.ConditionFalse, reasonUsageNormal, usage)
+		return &ctrl.Result{RequeueAfter: r.StorageUsageInterval}, nil
+	}
+
+	// Only warn when the storage becomes nearly full, not on every measurement
+	wasFull := meta.IsStatusConditionTrue(cr.Status.Conditions, registryv1beta1.ConditionStorageNearlyFull)
+	message := fmt.Sprintf("%s, above the %d%% threshold", usage, threshold)
+	if cr.Spec.Storage.AutoExpansion != nil {
+		expansion, err := r.autoExpandPVC(ctx, cr)
+		if err != nil {
+			return &ctrl.Result{}, err
+		}
+		message += ". " + expansion
+	}
+	setCondition(cr, registryv1beta1.ConditionStorageNearlyFull, metav1.ConditionTrue, reasonUsageHigh, message)
+	if !wasFull {
+		r.Recorder.Event(cr, corev1.EventTypeWarning, eventReasonStorageNearlyFull, message)
+	}
+	return &ctrl.Result{RequeueAfter: r.StorageUsageInterval}, nil
+}
+
+// measureStorage returns the used bytes and capacity of the registry's persistent volume, as reported by the kubelet
+// of a running registry pod. Pods aren't cached by the manager, so they're read from the API server.
+func (r *DevfileRegistryReconciler) measureStorage(ctx context.Context, cr *registryv1beta1.DevfileRegistry) (int64, int64, error) {
+	pods := &corev1.PodList{}
+	if err := r.APIReader.List(ctx, pods, client.InNamespace(cr.Namespace), client.MatchingLabels(registry.LabelsForDevfileRegistry(cr.Name))); err != nil {
+		return 0, 0, err
+	}
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.Status.Phase != corev1.PodRunning || pod.Spec.NodeName == "" || !pod.DeletionTimestamp.IsZero() {
+			continue
+		}
+		used, capacity, err := util.GetVolumeStats(ctx, r.RESTClient, pod, registry.DevfileRegistryVolumeName)
+		if err != nil {
+			return 0, 0, err
+		}
+		if capacity <= 0 {
+			return 0, 0, fmt.Errorf("the kubelet reported no capacity for volume %s of pod %s", registry.DevfileRegistryVolumeName, pod.Name)
+		}
+		return used, capacity, nil
+	}
+	return 0, 0, fmt.Errorf("no running pod of the devfile registry")
+}
+
+// autoExpandPVC expands the nearly full persistent volume claim from the size it requests. The claim is never shrunk
+// back to spec.storage.size, so it keeps the expanded size. It returns what became of the expansion, to be added to the
+// condition message.
+func (r *DevfileRegistryReconciler) autoExpandPVC(ctx context.Context, cr *registryv1beta1.DevfileRegistry) (string, error) {
+	pvc := &corev1.PersistentVolumeClaim{}
+	if err := r.Get(ctx, types.NamespacedName{Name: registry.PVCName(cr.Name), Namespace: cr.Namespace}, pvc); err != nil {
+		return "", err
+	}
+
+	// Wait for the last expansion to complete before measuring whether another one is needed
+	requested := pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+	capacity := pvc.Status.Capacity[corev1.ResourceStorage]
+	if resizing, _ := registry.GetPVCResizeStatus(pvc); resizing || capacity.Cmp(requested) < 0 {
+		return "PersistentVolumeClaim " + pvc.Name + " is being expanded", nil
+	}
+
+	expandable, err := r.isPVCExpandable(ctx, pvc)
+	if err != nil {
+		return "", err
+	}
+	if !expandable {
+		return "The storage class of PersistentVolumeClaim " + pvc.Name + " doesn't allow expanding it", nil
+	}
+	expanded, ok := registry.GetAutoExpansionSize(cr, requested)
+	if !ok {
+		return "PersistentVolumeClaim " + pvc.Name + " already reached its maximum size of " + cr.Spec.Storage.AutoExpansion.MaxSize, nil
+	}
+
+	desired := registry.GeneratePVC(cr, r.Scheme, registry.LabelsForDevfileRegistry(cr.Name))
+	desired.Spec.Resources.Requests = corev1.ResourceList{corev1.ResourceStorage: expanded}
+	if _, _, err := pvcStrategy(ctx, r, pvc, desired); err != nil {
+		return "", err
+	}
+	r.Recorder.Eventf(cr, corev1.EventTypeNormal, eventReasonStorageExpanded, "Expanding PersistentVolumeClaim %s from %s to %s", pvc.Name, requested.String(), expanded.String())
+	return "PersistentVolumeClaim " + pvc.Name + " is expanded to " + expanded.String(), nil
+}
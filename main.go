@@ -14,16 +14,22 @@ package main
 import (
 	"flag"
 	"os"
+	"time"
 
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/kubernetes"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 
 	registryv1alpha1 "github.com/devfile/registry-operator/api/v1alpha1"
+	registryv1beta1 "github.com/devfile/registry-operator/api/v1beta1"
 	"github.com/devfile/registry-operator/controllers"
+	"github.com/devfile/registry-operator/pkg/archive"
+	"github.com/devfile/registry-operator/pkg/util"
+	"github.com/devfile/registry-operator/pkg/webhooks"
 
 	routev1 "github.com/openshift/api/route/v1"
 	// +kubebuilder:scaffold:imports
@@ -38,16 +44,43 @@ func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 	utilruntime.Must(routev1.AddToScheme(scheme))
 	utilruntime.Must(registryv1alpha1.AddToScheme(scheme))
+	utilruntime.Must(registryv1beta1.AddToScheme(scheme))
 	// +kubebuilder:scaffold:scheme
 }
 
 func main() {
+	// The archive jobs of DevfileRegistryExports run the operator image
+	if len(os.Args) > 1 && os.Args[1] == archive.Command {
+		ctrl.SetLogger(zap.New(zap.UseDevMode(true)))
+		if err := archive.Run(ctrl.SetupSignalHandler(), os.Args[2:]); err != nil {
+			setupLog.Error(err, "archive failed")
+			os.Exit(1)
+		}
+		return
+	}
+
 	var metricsAddr string
 	var enableLeaderElection bool
+	var registryCAFile string
+	var registryProbeTimeout time.Duration
+	var maxConcurrentReconciles int
+	var archiveImage string
+	var storageUsageInterval time.Duration
 	flag.StringVar(&metricsAddr, "metrics-addr", ":8080", "The address the metric endpoint binds to.")
 	flag.BoolVar(&enableLeaderElection, "enable-leader-election", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
+	flag.StringVar(&registryCAFile, "registry-ca-file", "",
+		"PEM file with the CA certificates used to verify the devfile registry servers when probing them. "+
+			"If not set, their certificates aren't verified.")
+	flag.DurationVar(&registryProbeTimeout, "registry-probe-timeout", 5*time.Second,
+		"How long to wait for a devfile registry server to respond when probing it.")
+	flag.IntVar(&maxConcurrentReconciles, "max-concurrent-reconciles", 1,
+		"The number of devfile registries that can be reconciled at the same time.")
+	flag.StringVar(&archiveImage, "archive-image", "quay.io/devfile/registry-operator:next",
+		"The operator image run by the jobs exporting and importing the stacks of devfile registries.")
+	flag.DurationVar(&storageUsageInterval, "storage-usage-interval", 0,
+		"How often to measure the storage usage of the devfile registries, 0 disables measuring it. Requires get access to nodes/proxy.")
 	flag.Parse()
 
 	ctrl.SetLogger(zap.New(zap.UseDevMode(true)))
@@ -64,14 +97,56 @@ func main() {
 		os.Exit(1)
 	}
 
+	probeClient, err := util.NewProbeClient(registryCAFile, registryProbeTimeout)
+	if err != nil {
+		setupLog.Error(err, "unable to create the devfile registry probe client")
+		os.Exit(1)
+	}
+
+	clientset, err := kubernetes.NewForConfig(mgr.GetConfig())
+	if err != nil {
+		setupLog.Error(err, "unable to create the Kubernetes clientset")
+		os.Exit(1)
+	}
+
 	if err = (&controllers.DevfileRegistryReconciler{
-		Client: mgr.GetClient(),
-		Log:    ctrl.Log.WithName("controllers").WithName("DevfileRegistry"),
-		Scheme: mgr.GetScheme(),
-	}).SetupWithManager(mgr); err != nil {
+		Client:               mgr.GetClient(),
+		Log:                  ctrl.Log.WithName("controllers").WithName("DevfileRegistry"),
+		Scheme:               mgr.GetScheme(),
+		Recorder:             mgr.GetEventRecorderFor("devfileregistry-controller"),
+		HTTPClient:           probeClient,
+		APIReader:            mgr.GetAPIReader(),
+		RESTClient:           clientset.CoreV1().RESTClient(),
+		StorageUsageInterval: storageUsageInterval,
+	}).SetupWithManager(mgr, maxConcurrentReconciles); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "DevfileRegistry")
 		os.Exit(1)
 	}
+	if err = (&controllers.DevfileRegistryBackupReconciler{
+		Client:   mgr.GetClient(),
+		Log:      ctrl.Log.WithName("controllers").WithName("DevfileRegistryBackup"),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("devfileregistrybackup-controller"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "DevfileRegistryBackup")
+		os.Exit(1)
+	}
+	if err = (&controllers.DevfileRegistryExportReconciler{
+		Client:   mgr.GetClient(),
+		Log:      ctrl.Log.WithName("controllers").WithName("DevfileRegistryExport"),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("devfileregistryexport-controller"),
+		Image:    archiveImage,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "DevfileRegistryExport")
+		os.Exit(1)
+	}
+	if os.Getenv("ENABLE_WEBHOOKS") != "false" {
+		if err = webhooks.SetupWebhooks(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhooks", "webhook", "DevfileRegistry")
+			os.Exit(1)
+		}
+	}
 	// +kubebuilder:scaffold:builder
 
 	setupLog.Info("starting manager")
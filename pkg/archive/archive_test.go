@@ -0,0 +1,231 @@
+//
+// Copyright (c) 2020 Red Hat, Inc.
+// This program and the accompanying materials are made
+// available under the terms of the Eclipse Public License 2.0
+// which is available at https://www.eclipse.org/legal/epl-2.0/
+//
+// SPDX-License-Identifier: EPL-2.0
+//
+// Contributors:
+//   Red Hat, Inc. - initial API and implementation
+
+package archive
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeRegistry serves the parts of the OCI distribution API the archive uses from memory
+type fakeRegistry struct {
+	mu        sync.Mutex
+	blobs     map[string][]byte
+	manifests map[string]map[string][]byte
+	mediaType map[string]string
+	uploads   int
+}
+
+func newFakeRegistry() *fakeRegistry {
+	return &fakeRegistry{blobs: map[string][]byte{}, manifests: map[string]map[string][]byte{}, mediaType: map[string]string{}}
+}
+
+// push stores a manifest under a tag and its digest, along with its blobs
+func (f *fakeRegistry) push(repository string, tag string, mediaType string, body []byte, blobs ...[]byte) string {
+	for _, blob := range blobs {
+		f.blobs[digestOf(blob)] = blob
+	}
+	digest := digestOf(body)
+	if f.manifests[repository] == nil {
+		f.manifests[repository] = map[string][]byte{}
+	}
+	f.manifests[repository][digest] = body
+	if tag != "" {
+		f.manifests[repository][tag] = body
+	}
+	f.mediaType[digest] = mediaType
+	return digest
+}
+
+func (f *fakeRegistry) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	path := strings.TrimPrefix(r.URL.Path, "/v2/")
+	switch {
+	case path == "_catalog":
+		var repositories []string
+		for repository := range f.manifests {
+			repositories = append(repositories, repository)
+		}
+		sort.Strings(repositories)
+		// Serve one repository per page to exercise the pagination
+		page := repositories
+		if last := r.URL.Query().Get("last"); last != "" {
+			page = repositories[sort.SearchStrings(repositories, last)+1:]
+		}
+		if len(page) > 1 {
+			w.Header().Set("Link", fmt.Sprintf(`</v2/_catalog?last=%s&n=1>; rel="next"`, page[0]))
+			page = page[:1]
+		}
+		json.NewEncoder(w).Encode(map[string][]string{"repositories": page})
+	case strings.HasSuffix(path, "/tags/list"):
+		repository := strings.TrimSuffix(path, "/tags/list")
+		var tags []string
+		for ref := range f.manifests[repository] {
+			if !strings.HasPrefix(ref, "sha256:") {
+				tags = append(tags, ref)
+			}
+		}
+		sort.Strings(tags)
+		json.NewEncoder(w).Encode(map[string]interface{}{"name": repository, "tags": tags})
+	case strings.Contains(path, "/manifests/"):
+		parts := strings.SplitN(path, "/manifests/", 2)
+		if r.Method == http.MethodPut {
+			body, _ := ioutil.ReadAll(r.Body)
+			if digest := parts[1]; strings.HasPrefix(digest, "sha256:") && digest != digestOf(body) {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			tag := parts[1]
+			if strings.HasPrefix(tag, "sha256:") {
+				tag = ""
+			}
+			f.push(parts[0], tag, r.Header.Get("Content-Type"), body)
+			w.WriteHeader(http.StatusCreated)
+			return
+		}
+		body, ok := f.manifests[parts[0]][parts[1]]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", f.mediaType[digestOf(body)])
+		w.Header().Set("Docker-Content-Digest", digestOf(body))
+		w.Write(body)
+	case strings.HasSuffix(path, "/blobs/uploads/"):
+		f.uploads++
+		w.Header().Set("Location", fmt.Sprintf("/v2/%supload-%d?state=abc", path, f.uploads))
+		w.WriteHeader(http.StatusAccepted)
+	case strings.Contains(path, "/blobs/uploads/"):
+		body, _ := ioutil.ReadAll(r.Body)
+		if r.URL.Query().Get("state") != "abc" || r.URL.Query().Get("digest") != digestOf(body) {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		f.blobs[digestOf(body)] = body
+		w.WriteHeader(http.StatusCreated)
+	case strings.Contains(path, "/blobs/"):
+		blob, ok := f.blobs[path[strings.LastIndex(path, "/")+1:]]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if r.Method == http.MethodGet {
+			w.Write(blob)
+		}
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+// imageManifest returns an OCI image manifest referencing the config and layer blobs
+func imageManifest(config []byte, layers ...[]byte) []byte {
+	m := map[string]interface{}{
+		"schemaVersion": 2,
+		"mediaType":     MediaTypeOCIManifest,
+		"config":        Descriptor{MediaType: "application/vnd.devfileio.devfile.config.v2+json", Digest: digestOf(config), Size: int64(len(config))},
+	}
+	var descs []Descriptor
+	for _, layer := range layers {
+		descs = append(descs, Descriptor{MediaType: "application/vnd.devfileio.devfile.layer.v1", Digest: digestOf(layer), Size: int64(len(layer))})
+	}
+	m["layers"] = descs
+	body, _ := json.Marshal(m)
+	return body
+}
+
+func TestExportImport(t *testing.T) {
+	source := newFakeRegistry()
+	config := []byte("{}")
+	devfile := []byte("schemaVersion: 2.2.0\nmetadata:\n  name: go\n")
+	logo := []byte("<svg/>")
+	goManifest := imageManifest(config, devfile, logo)
+	source.push("devfile-catalog/go", "1.0.0", MediaTypeOCIManifest, goManifest, config, devfile, logo)
+	source.push("devfile-catalog/go", "latest", MediaTypeOCIManifest, goManifest)
+	nodeDevfile := []byte("schemaVersion: 2.2.0\nmetadata:\n  name: nodejs\n")
+	nodeManifest := imageManifest(config, nodeDevfile)
+	nodeDigest := source.push("devfile-catalog/nodejs", "", MediaTypeOCIManifest, nodeManifest, config, nodeDevfile)
+	index, _ := json.Marshal(map[string]interface{}{
+		"schemaVersion": 2,
+		"mediaType":     MediaTypeOCIIndex,
+		"manifests":     []Descriptor{{MediaType: MediaTypeOCIManifest, Digest: nodeDigest, Size: int64(len(nodeManifest))}},
+	})
+	source.push("devfile-catalog/nodejs", "2.1.1", MediaTypeOCIIndex, index)
+
+	sourceServer := httptest.NewServer(source)
+	defer sourceServer.Close()
+	ctx := context.Background()
+
+	archive := &bytes.Buffer{}
+	summary, err := Export(ctx, &RegistryClient{URL: sourceServer.URL, HTTPClient: sourceServer.Client()}, archive)
+	if err != nil {
+		t.Fatalf("TestExportImport error: unexpected error exporting: %v", err)
+	}
+	if want := (Summary{Repositories: 2, Tags: 3, Blobs: 4}); summary != want {
+		t.Errorf("TestExportImport error: export summary mismatch, expected: %v got: %v", want, summary)
+	}
+
+	dir, err := ioutil.TempDir("", "archive")
+	if err != nil {
+		t.Fatalf("TestExportImport error: unexpected error: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	if err := ExtractLayout(archive, dir); err != nil {
+		t.Fatalf("TestExportImport error: unexpected error extracting the archive: %v", err)
+	}
+
+	target := newFakeRegistry()
+	targetServer := httptest.NewServer(target)
+	defer targetServer.Close()
+	summary, err = Import(ctx, &RegistryClient{URL: targetServer.URL, HTTPClient: targetServer.Client()}, dir)
+	if err != nil {
+		t.Fatalf("TestExportImport error: unexpected error importing: %v", err)
+	}
+	if want := (Summary{Repositories: 2, Tags: 3, Blobs: 4}); summary != want {
+		t.Errorf("TestExportImport error: import summary mismatch, expected: %v got: %v", want, summary)
+	}
+	if !reflect.DeepEqual(target.manifests, source.manifests) {
+		t.Errorf("TestExportImport error: manifests mismatch, expected: %v got: %v", source.manifests, target.manifests)
+	}
+	if !reflect.DeepEqual(target.blobs, source.blobs) {
+		t.Errorf("TestExportImport error: blobs mismatch, expected: %d blobs got: %d", len(source.blobs), len(target.blobs))
+	}
+}
+
+func TestExtractLayoutRejectsEscapingEntries(t *testing.T) {
+	archive := &bytes.Buffer{}
+	e := &exporter{tw: tar.NewWriter(archive)}
+	writeTarFile(e.tw, "../escape", 1, strings.NewReader("x"))
+	e.tw.Close()
+
+	dir, err := ioutil.TempDir("", "archive")
+	if err != nil {
+		t.Fatalf("TestExtractLayoutRejectsEscapingEntries error: unexpected error: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	if err := ExtractLayout(archive, dir); err == nil {
+		t.Errorf("TestExtractLayoutRejectsEscapingEntries error: expected an error extracting an entry outside of the layout")
+	}
+}
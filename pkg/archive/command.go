@@ -0,0 +1,169 @@
+//
+// Copyright (c) 2020 Red Hat, Inc.
+// This program and the accompanying materials are made
+// available under the terms of the Eclipse Public License 2.0
+// which is available at https://www.eclipse.org/legal/epl-2.0/
+//
+// SPDX-License-Identifier: EPL-2.0
+//
+// Contributors:
+//   Red Hat, Inc. - initial API and implementation
+
+package archive
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/devfile/registry-operator/pkg/util"
+)
+
+const (
+	// Command is the subcommand of the manager binary running the archive jobs
+	Command = "archive"
+	// ExportCommand and ImportCommand select the direction the stacks are copied in
+	ExportCommand = "export"
+	ImportCommand = "import"
+
+	// AccessKeyIDEnv and SecretAccessKeyEnv hold the credentials of the S3 bucket the archive is stored in
+	AccessKeyIDEnv     = "AWS_ACCESS_KEY_ID"
+	SecretAccessKeyEnv = "AWS_SECRET_ACCESS_KEY"
+)
+
+// Run runs the archive subcommand with its arguments, the first of which is export or import
+func Run(ctx context.Context, args []string) error {
+	if len(args) == 0 || (args[0] != ExportCommand && args[0] != ImportCommand) {
+		return fmt.Errorf("usage: %s %s|%s [flags]", Command, ExportCommand, ImportCommand)
+	}
+	mode := args[0]
+
+	flags := flag.NewFlagSet(Command+" "+mode, flag.ContinueOnError)
+	registryURL := flags.String("registry-url", "", "The URL of the OCI registry to copy the stacks from or into.")
+	archivePath := flags.String("archive", "", "The path of the archive file.")
+	caFile := flags.String("ca-file", "", "PEM file with the CA certificates used to verify the OCI registry, in addition to the system ones.")
+	s3Endpoint := flags.String("s3-endpoint", "", "The URL of the S3-compatible service storing the archive. Defaults to the AWS endpoint of the region.")
+	s3Region := flags.String("s3-region", "us-east-1", "The region of the S3 bucket.")
+	s3Bucket := flags.String("s3-bucket", "", "The S3 bucket storing the archive. The archive is only kept in the archive file when it's not set.")
+	s3Key := flags.String("s3-key", "", "The key of the archive object in the S3 bucket.")
+	if err := flags.Parse(args[1:]); err != nil {
+		return err
+	}
+	if *registryURL == "" || *archivePath == "" {
+		return fmt.Errorf("--registry-url and --archive must be set")
+	}
+
+	httpClient := &http.Client{}
+	if *caFile != "" {
+		caBundle, err := ioutil.ReadFile(*caFile)
+		if err != nil {
+			return err
+		}
+		if httpClient, err = util.WithRootCAs(httpClient, caBundle, ""); err != nil {
+			return err
+		}
+	}
+	registry := &RegistryClient{URL: *registryURL, HTTPClient: httpClient}
+
+	var bucket *S3Client
+	if *s3Bucket != "" {
+		bucket = &S3Client{
+			Endpoint:        *s3Endpoint,
+			Region:          *s3Region,
+			Bucket:          *s3Bucket,
+			AccessKeyID:     os.Getenv(AccessKeyIDEnv),
+			SecretAccessKey: os.Getenv(SecretAccessKeyEnv),
+			HTTPClient:      &http.Client{},
+		}
+	}
+
+	if mode == ExportCommand {
+		return runExport(ctx, registry, bucket, *archivePath, *s3Key)
+	}
+	return runImport(ctx, registry, bucket, *archivePath, *s3Key)
+}
+
+// runExport exports the registry into the archive file, and uploads it to the bucket if there's one
+func runExport(ctx context.Context, registry *RegistryClient, bucket *S3Client, archivePath string, key string) error {
+	f, err := os.Create(archivePath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	summary, err := Export(ctx, registry, f)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Exported %s from %s to %s\n", summary, registry.URL, archivePath)
+
+	if bucket == nil {
+		return nil
+	}
+	size, err := f.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	if err := bucket.PutObject(ctx, key, f, size); err != nil {
+		return err
+	}
+	fmt.Printf("Uploaded %s to bucket %s\n", key, bucket.Bucket)
+	return nil
+}
+
+// runImport downloads the archive file from the bucket if there's one, and imports it into the registry
+func runImport(ctx context.Context, registry *RegistryClient, bucket *S3Client, archivePath string, key string) error {
+	if bucket != nil {
+		if err := download(ctx, bucket, key, archivePath); err != nil {
+			return err
+		}
+		fmt.Printf("Downloaded %s from bucket %s\n", key, bucket.Bucket)
+	}
+
+	f, err := os.Open(archivePath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	// Extract the layout next to the archive, as the volume holding it has room for its content
+	dir, err := ioutil.TempDir(filepath.Dir(archivePath), "layout")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+	if err := ExtractLayout(f, dir); err != nil {
+		return err
+	}
+
+	summary, err := Import(ctx, registry, dir)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Imported %s from %s to %s\n", summary, archivePath, registry.URL)
+	return nil
+}
+
+// download writes the object at key into the file at path
+func download(ctx context.Context, bucket *S3Client, key string, path string) error {
+	content, err := bucket.GetObject(ctx, key)
+	if err != nil {
+		return err
+	}
+	defer content.Close()
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, content); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,148 @@
+//
+// Copyright (c) 2020 Red Hat, Inc.
+// This program and the accompanying materials are made
+// available under the terms of the Eclipse Public License 2.0
+// which is available at https://www.eclipse.org/legal/epl-2.0/
+//
+// SPDX-License-Identifier: EPL-2.0
+//
+// Contributors:
+//   Red Hat, Inc. - initial API and implementation
+
+package archive
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// Summary counts what was copied between a registry and an archive
+type Summary struct {
+	Repositories int
+	Tags         int
+	Blobs        int
+}
+
+func (s Summary) String() string {
+	return fmt.Sprintf("%d repositories, %d tags and %d blobs", s.Repositories, s.Tags, s.Blobs)
+}
+
+// exporter writes the content of a registry into an OCI image layout tar archive
+type exporter struct {
+	client  *RegistryClient
+	tw      *tar.Writer
+	written map[string]bool
+	summary Summary
+}
+
+// Export writes every tagged manifest of the registry, along with the manifests and blobs it references, into w as an
+// OCI image layout tar archive. The manifests are listed in the layout index with the repository and tag they were
+// pushed under.
+func Export(ctx context.Context, client *RegistryClient, w io.Writer) (Summary, error) {
+	e := &exporter{client: client, tw: tar.NewWriter(w), written: map[string]bool{}}
+
+	layout := []byte(`{"imageLayoutVersion":"1.0.0"}`)
+	if err := writeTarFile(e.tw, layoutFile, int64(len(layout)), bytes.NewReader(layout)); err != nil {
+		return e.summary, err
+	}
+
+	repositories, err := client.Catalog(ctx)
+	if err != nil {
+		return e.summary, err
+	}
+	index := Index{SchemaVersion: 2, MediaType: MediaTypeOCIIndex, Manifests: []Descriptor{}}
+	for _, repository := range repositories {
+		tags, err := client.Tags(ctx, repository)
+		if err != nil {
+			return e.summary, err
+		}
+		for _, tag := range tags {
+			desc, body, err := client.GetManifest(ctx, repository, tag)
+			if err != nil {
+				return e.summary, err
+			}
+			if err := e.exportManifest(ctx, repository, desc, body); err != nil {
+				return e.summary, fmt.Errorf("failed to export %s:%s: %v", repository, tag, err)
+			}
+			desc.Annotations = map[string]string{RefNameAnnotation: repository + ":" + tag}
+			index.Manifests = append(index.Manifests, desc)
+			e.summary.Tags++
+		}
+		e.summary.Repositories++
+	}
+
+	indexJSON, err := json.Marshal(index)
+	if err != nil {
+		return e.summary, err
+	}
+	if err := writeTarFile(e.tw, indexFile, int64(len(indexJSON)), bytes.NewReader(indexJSON)); err != nil {
+		return e.summary, err
+	}
+	return e.summary, e.tw.Close()
+}
+
+// exportManifest writes a manifest into the archive, after the manifests and blobs it references
+func (e *exporter) exportManifest(ctx context.Context, repository string, desc Descriptor, body []byte) error {
+	if e.written[desc.Digest] {
+		return nil
+	}
+	m, err := parseManifest(body)
+	if err != nil {
+		return err
+	}
+
+	for _, child := range m.Manifests {
+		childDesc, childBody, err := e.client.GetManifest(ctx, repository, child.Digest)
+		if err != nil {
+			return err
+		}
+		if err := e.exportManifest(ctx, repository, childDesc, childBody); err != nil {
+			return err
+		}
+	}
+	blobs := m.Layers
+	if m.Config != nil {
+		blobs = append([]Descriptor{*m.Config}, blobs...)
+	}
+	for _, blob := range blobs {
+		if err := e.exportBlob(ctx, repository, blob); err != nil {
+			return err
+		}
+	}
+
+	return e.writeBlob(desc, bytes.NewReader(body))
+}
+
+// exportBlob copies a blob from the registry into the archive
+func (e *exporter) exportBlob(ctx context.Context, repository string, desc Descriptor) error {
+	if e.written[desc.Digest] {
+		return nil
+	}
+	content, err := e.client.GetBlob(ctx, repository, desc.Digest)
+	if err != nil {
+		return err
+	}
+	defer content.Close()
+	if err := e.writeBlob(desc, newVerifier(content, desc)); err != nil {
+		return err
+	}
+	e.summary.Blobs++
+	return nil
+}
+
+// writeBlob writes content under the path of its digest in the archive
+func (e *exporter) writeBlob(desc Descriptor, content io.Reader) error {
+	path, err := blobPath(desc.Digest)
+	if err != nil {
+		return err
+	}
+	if err := writeTarFile(e.tw, path, desc.Size, content); err != nil {
+		return err
+	}
+	e.written[desc.Digest] = true
+	return nil
+}
@@ -0,0 +1,127 @@
+//
+// Copyright (c) 2020 Red Hat, Inc.
+// This program and the accompanying materials are made
+// available under the terms of the Eclipse Public License 2.0
+// which is available at https://www.eclipse.org/legal/epl-2.0/
+//
+// SPDX-License-Identifier: EPL-2.0
+//
+// Contributors:
+//   Red Hat, Inc. - initial API and implementation
+
+package archive
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// importer pushes the content of an extracted OCI image layout into a registry
+type importer struct {
+	client  *RegistryClient
+	dir     string
+	summary Summary
+}
+
+// Import pushes every manifest listed in the index of the OCI image layout extracted in dir into the registry, under
+// the repository and tag it was exported from, along with the manifests and blobs it references. Blobs the registry
+// already holds aren't pushed again.
+func Import(ctx context.Context, client *RegistryClient, dir string) (Summary, error) {
+	i := &importer{client: client, dir: dir}
+
+	indexJSON, err := ioutil.ReadFile(filepath.Join(dir, indexFile))
+	if err != nil {
+		return i.summary, err
+	}
+	index := Index{}
+	if err := json.Unmarshal(indexJSON, &index); err != nil {
+		return i.summary, err
+	}
+
+	repositories := map[string]bool{}
+	for _, desc := range index.Manifests {
+		ref := desc.Annotations[RefNameAnnotation]
+		sep := strings.LastIndex(ref, ":")
+		if sep <= 0 || sep == len(ref)-1 {
+			return i.summary, fmt.Errorf("manifest %s has no repository and tag in its %s annotation", desc.Digest, RefNameAnnotation)
+		}
+		repository, tag := ref[:sep], ref[sep+1:]
+		if err := i.importManifest(ctx, repository, tag, desc); err != nil {
+			return i.summary, fmt.Errorf("failed to import %s: %v", ref, err)
+		}
+		repositories[repository] = true
+		i.summary.Tags++
+	}
+	i.summary.Repositories = len(repositories)
+	return i.summary, nil
+}
+
+// importManifest pushes a manifest to the registry under reference, after the manifests and blobs it references
+func (i *importer) importManifest(ctx context.Context, repository string, reference string, desc Descriptor) error {
+	path, err := blobPath(desc.Digest)
+	if err != nil {
+		return err
+	}
+	body, err := ioutil.ReadFile(filepath.Join(i.dir, path))
+	if err != nil {
+		return err
+	}
+	m, err := parseManifest(body)
+	if err != nil {
+		return err
+	}
+
+	for _, child := range m.Manifests {
+		if err := i.importManifest(ctx, repository, child.Digest, child); err != nil {
+			return err
+		}
+	}
+	blobs := m.Layers
+	if m.Config != nil {
+		blobs = append([]Descriptor{*m.Config}, blobs...)
+	}
+	for _, blob := range blobs {
+		if err := i.importBlob(ctx, repository, blob); err != nil {
+			return err
+		}
+	}
+
+	mediaType := desc.MediaType
+	if m.MediaType != "" {
+		mediaType = m.MediaType
+	}
+	if mediaType == "" {
+		mediaType = MediaTypeOCIManifest
+		if len(m.Manifests) > 0 {
+			mediaType = MediaTypeOCIIndex
+		}
+	}
+	return i.client.PutManifest(ctx, repository, reference, mediaType, body)
+}
+
+// importBlob pushes a blob to the registry unless it already holds it
+func (i *importer) importBlob(ctx context.Context, repository string, desc Descriptor) error {
+	exists, err := i.client.HasBlob(ctx, repository, desc.Digest)
+	if err != nil || exists {
+		return err
+	}
+	path, err := blobPath(desc.Digest)
+	if err != nil {
+		return err
+	}
+	f, err := os.Open(filepath.Join(i.dir, path))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := i.client.PutBlob(ctx, repository, desc, newVerifier(f, desc)); err != nil {
+		return err
+	}
+	i.summary.Blobs++
+	return nil
+}
@@ -0,0 +1,148 @@
+//
+// Copyright (c) 2020 Red Hat, Inc.
+// This program and the accompanying materials are made
+// available under the terms of the Eclipse Public License 2.0
+// which is available at https://www.eclipse.org/legal/epl-2.0/
+//
+// SPDX-License-Identifier: EPL-2.0
+//
+// Contributors:
+//   Red Hat, Inc. - initial API and implementation
+
+package archive
+
+import (
+	"archive/tar"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// layoutFile and indexFile are the files at the root of an OCI image layout
+	layoutFile = "oci-layout"
+	indexFile  = "index.json"
+	// blobsDir holds the manifests and blobs of an OCI image layout, by digest
+	blobsDir = "blobs"
+
+	// RefNameAnnotation is set on the manifests of the layout index to the repository and tag they're pushed under
+	RefNameAnnotation = "org.opencontainers.image.ref.name"
+)
+
+// Index is the index.json of an OCI image layout
+type Index struct {
+	SchemaVersion int          `json:"schemaVersion"`
+	MediaType     string       `json:"mediaType,omitempty"`
+	Manifests     []Descriptor `json:"manifests"`
+}
+
+// blobPath returns the path of a blob within an OCI image layout
+func blobPath(digest string) (string, error) {
+	parts := strings.SplitN(digest, ":", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" || strings.ContainsAny(digest, `/\`) {
+		return "", fmt.Errorf("invalid digest %q", digest)
+	}
+	return filepath.Join(blobsDir, parts[0], parts[1]), nil
+}
+
+// writeTarFile writes a regular file into a tar archive
+func writeTarFile(tw *tar.Writer, name string, size int64, content io.Reader) error {
+	if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: size, Typeflag: tar.TypeReg}); err != nil {
+		return err
+	}
+	_, err := io.Copy(tw, content)
+	return err
+}
+
+// ExtractLayout extracts an OCI image layout tar archive into dir. Entries escaping dir are rejected.
+func ExtractLayout(r io.Reader, dir string) error {
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		name := filepath.Clean(filepath.FromSlash(header.Name))
+		if filepath.IsAbs(name) || name == ".." || strings.HasPrefix(name, ".."+string(filepath.Separator)) {
+			return fmt.Errorf("archive entry %q is outside of the image layout", header.Name)
+		}
+		path := filepath.Join(dir, name)
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(path, 0755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+				return err
+			}
+			f, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+			if err != nil {
+				return err
+			}
+			_, err = io.Copy(f, tr)
+			if closeErr := f.Close(); err == nil {
+				err = closeErr
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// verifier checks that the content read through it matches a descriptor
+type verifier struct {
+	r      io.Reader
+	desc   Descriptor
+	hash   hash.Hash
+	hashed string
+	read   int64
+}
+
+// newVerifier returns a reader checking the content of r against desc once it's read to the end. Only sha256 digests
+// are verified, the size is always checked.
+func newVerifier(r io.Reader, desc Descriptor) *verifier {
+	v := &verifier{r: r, desc: desc}
+	if strings.HasPrefix(desc.Digest, "sha256:") {
+		v.hash = sha256.New()
+		v.hashed = strings.TrimPrefix(desc.Digest, "sha256:")
+	}
+	return v
+}
+
+func (v *verifier) Read(p []byte) (int, error) {
+	n, err := v.r.Read(p)
+	v.read += int64(n)
+	if v.hash != nil {
+		v.hash.Write(p[:n])
+	}
+	if err == io.EOF {
+		if v.read != v.desc.Size {
+			return n, fmt.Errorf("blob %s has %d bytes, expected %d", v.desc.Digest, v.read, v.desc.Size)
+		}
+		if v.hash != nil && hex.EncodeToString(v.hash.Sum(nil)) != v.hashed {
+			return n, fmt.Errorf("blob %s doesn't match its digest", v.desc.Digest)
+		}
+	}
+	return n, err
+}
+
+// parseManifest returns the references of a manifest or index
+func parseManifest(body []byte) (*manifest, error) {
+	m := &manifest{}
+	if err := json.Unmarshal(body, m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
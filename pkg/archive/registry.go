@@ -0,0 +1,256 @@
+//
+// Copyright (c) 2020 Red Hat, Inc.
+// This program and the accompanying materials are made
+// available under the terms of the Eclipse Public License 2.0
+// which is available at https://www.eclipse.org/legal/epl-2.0/
+//
+// SPDX-License-Identifier: EPL-2.0
+//
+// Contributors:
+//   Red Hat, Inc. - initial API and implementation
+
+// Package archive copies the stacks of a devfile registry's OCI registry into an archive in the OCI image layout
+// format, and back. It runs in the jobs created for DevfileRegistryExport resources.
+package archive
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// Media types of the manifests the registry client accepts
+const (
+	MediaTypeOCIManifest    = "application/vnd.oci.image.manifest.v1+json"
+	MediaTypeOCIIndex       = "application/vnd.oci.image.index.v1+json"
+	MediaTypeDockerManifest = "application/vnd.docker.distribution.manifest.v2+json"
+	MediaTypeDockerList     = "application/vnd.docker.distribution.manifest.list.v2+json"
+)
+
+// catalogPageSize is the number of repositories or tags requested per page
+const catalogPageSize = 100
+
+// linkNextRegexp extracts the URL of the next page from the Link header of paginated responses
+var linkNextRegexp = regexp.MustCompile(`<([^>]+)>;\s*rel="next"`)
+
+// Descriptor references a manifest or blob by its digest
+type Descriptor struct {
+	MediaType   string            `json:"mediaType,omitempty"`
+	Digest      string            `json:"digest"`
+	Size        int64             `json:"size"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// manifest holds the references of image manifests and indexes, which are all the content needs to be copied
+type manifest struct {
+	MediaType string       `json:"mediaType,omitempty"`
+	Config    *Descriptor  `json:"config,omitempty"`
+	Layers    []Descriptor `json:"layers,omitempty"`
+	Manifests []Descriptor `json:"manifests,omitempty"`
+}
+
+// RegistryClient talks to an OCI registry through the distribution API
+type RegistryClient struct {
+	// URL is the base URL of the registry, e.g. http://registry:5000
+	URL        string
+	HTTPClient *http.Client
+}
+
+// Catalog returns the repositories of the registry
+func (c *RegistryClient) Catalog(ctx context.Context) ([]string, error) {
+	var repositories []string
+	err := c.getPages(ctx, fmt.Sprintf("/v2/_catalog?n=%d", catalogPageSize), func(body []byte) error {
+		var page struct {
+			Repositories []string `json:"repositories"`
+		}
+		if err := json.Unmarshal(body, &page); err != nil {
+			return err
+		}
+		repositories = append(repositories, page.Repositories...)
+		return nil
+	})
+	return repositories, err
+}
+
+// Tags returns the tags of a repository
+func (c *RegistryClient) Tags(ctx context.Context, repository string) ([]string, error) {
+	var tags []string
+	err := c.getPages(ctx, fmt.Sprintf("/v2/%s/tags/list?n=%d", repository, catalogPageSize), func(body []byte) error {
+		var page struct {
+			Tags []string `json:"tags"`
+		}
+		if err := json.Unmarshal(body, &page); err != nil {
+			return err
+		}
+		tags = append(tags, page.Tags...)
+		return nil
+	})
+	return tags, err
+}
+
+// GetManifest returns the manifest of a repository with the given tag or digest, along with its descriptor
+func (c *RegistryClient) GetManifest(ctx context.Context, repository string, reference string) (Descriptor, []byte, error) {
+	resp, err := c.do(ctx, http.MethodGet, "/v2/"+repository+"/manifests/"+reference, nil, map[string]string{
+		"Accept": strings.Join([]string{MediaTypeOCIManifest, MediaTypeOCIIndex, MediaTypeDockerManifest, MediaTypeDockerList}, ", "),
+	}, http.StatusOK)
+	if err != nil {
+		return Descriptor{}, nil, err
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return Descriptor{}, nil, err
+	}
+
+	desc := Descriptor{
+		MediaType: resp.Header.Get("Content-Type"),
+		Digest:    resp.Header.Get("Docker-Content-Digest"),
+		Size:      int64(len(body)),
+	}
+	if desc.Digest == "" {
+		desc.Digest = digestOf(body)
+	}
+	// Manifests written before the media type was part of the specification only declare it in their content
+	var m manifest
+	if err := json.Unmarshal(body, &m); err == nil && m.MediaType != "" {
+		desc.MediaType = m.MediaType
+	}
+	return desc, body, nil
+}
+
+// PutManifest pushes a manifest to a repository under the given tag or digest
+func (c *RegistryClient) PutManifest(ctx context.Context, repository string, reference string, mediaType string, body []byte) error {
+	resp, err := c.do(ctx, http.MethodPut, "/v2/"+repository+"/manifests/"+reference, bytes.NewReader(body), map[string]string{
+		"Content-Type": mediaType,
+	}, http.StatusCreated)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// GetBlob returns the content of a blob. The caller must close it.
+func (c *RegistryClient) GetBlob(ctx context.Context, repository string, digest string) (io.ReadCloser, error) {
+	resp, err := c.do(ctx, http.MethodGet, "/v2/"+repository+"/blobs/"+digest, nil, nil, http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// HasBlob returns true if the repository already holds a blob
+func (c *RegistryClient) HasBlob(ctx context.Context, repository string, digest string) (bool, error) {
+	resp, err := c.do(ctx, http.MethodHead, "/v2/"+repository+"/blobs/"+digest, nil, nil, http.StatusOK, http.StatusNotFound)
+	if err != nil {
+		return false, err
+	}
+	resp.Body.Close()
+	return resp.StatusCode == http.StatusOK, nil
+}
+
+// PutBlob pushes a blob to a repository in a single upload
+func (c *RegistryClient) PutBlob(ctx context.Context, repository string, desc Descriptor, content io.Reader) error {
+	resp, err := c.do(ctx, http.MethodPost, "/v2/"+repository+"/blobs/uploads/", nil, nil, http.StatusAccepted)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	location, err := resp.Request.URL.Parse(resp.Header.Get("Location"))
+	if err != nil {
+		return err
+	}
+	query := location.Query()
+	query.Set("digest", desc.Digest)
+	location.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, location.String(), content)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = desc.Size
+	req.Header.Set("Content-Type", "application/octet-stream")
+	resp, err = c.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return checkStatus(resp, http.StatusCreated)
+}
+
+// getPages gets a paginated list, calling handle with the body of every page
+func (c *RegistryClient) getPages(ctx context.Context, path string, handle func(body []byte) error) error {
+	for path != "" {
+		resp, err := c.do(ctx, http.MethodGet, path, nil, nil, http.StatusOK)
+		if err != nil {
+			return err
+		}
+		body, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return err
+		}
+		if err := handle(body); err != nil {
+			return err
+		}
+
+		path = ""
+		if match := linkNextRegexp.FindStringSubmatch(resp.Header.Get("Link")); match != nil {
+			path = match[1]
+		}
+	}
+	return nil
+}
+
+// do sends a request to the registry, and returns an error unless it responds with one of the expected statuses
+func (c *RegistryClient) do(ctx context.Context, method string, path string, body io.Reader, headers map[string]string, expected ...int) (*http.Response, error) {
+	base, err := url.Parse(c.URL)
+	if err != nil {
+		return nil, err
+	}
+	target, err := base.Parse(path)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, method, target.String(), body)
+	if err != nil {
+		return nil, err
+	}
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkStatus(resp, expected...); err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+	return resp, nil
+}
+
+// checkStatus returns an error unless the response has one of the expected statuses
+func checkStatus(resp *http.Response, expected ...int) error {
+	for _, status := range expected {
+		if resp.StatusCode == status {
+			return nil
+		}
+	}
+	body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+	return fmt.Errorf("%s %s: unexpected status %s: %s", resp.Request.Method, resp.Request.URL.Path, resp.Status, strings.TrimSpace(string(body)))
+}
+
+// digestOf returns the sha256 digest of content
+func digestOf(content []byte) string {
+	sum := sha256.Sum256(content)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,192 @@
+//
+// Copyright (c) 2020 Red Hat, Inc.
+// This program and the accompanying materials are made
+// available under the terms of the Eclipse Public License 2.0
+// which is available at https://www.eclipse.org/legal/epl-2.0/
+//
+// SPDX-License-Identifier: EPL-2.0
+//
+// Contributors:
+//   Red Hat, Inc. - initial API and implementation
+
+package archive
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// unsignedPayload is sent as the payload hash of signed requests, so that archives can be streamed without hashing
+// them first
+const unsignedPayload = "UNSIGNED-PAYLOAD"
+
+// MaxPutObjectSize is the largest object S3 accepts in a single PUT request
+const MaxPutObjectSize = 5 << 30
+
+// S3Client reads and writes objects of an S3-compatible bucket. Requests are signed with AWS Signature Version 4, and
+// buckets are addressed by path, which every S3-compatible service supports.
+type S3Client struct {
+	// Endpoint is the URL of the service, e.g. http://minio.minio.svc:9000. The AWS endpoint of the region is used
+	// when it's empty.
+	Endpoint        string
+	Region          string
+	Bucket          string
+	AccessKeyID     string
+	SecretAccessKey string
+	HTTPClient      *http.Client
+
+	// now returns the time requests are signed at, it's only replaced in tests
+	now func() time.Time
+}
+
+// PutObject uploads size bytes of content to the object at key, in a single request. Objects larger than
+// MaxPutObjectSize are rejected before anything is sent.
+func (c *S3Client) PutObject(ctx context.Context, key string, content io.Reader, size int64) error {
+	if size > MaxPutObjectSize {
+		return fmt.Errorf("object %s is %d bytes, larger than the %d bytes S3 accepts in a single upload", key, size, int64(MaxPutObjectSize))
+	}
+	req, err := c.newRequest(ctx, http.MethodPut, key, content)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+	resp, err := c.do(req)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// GetObject returns the content of the object at key. The caller must close it.
+func (c *S3Client) GetObject(ctx context.Context, key string) (io.ReadCloser, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, key, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+func (c *S3Client) newRequest(ctx context.Context, method string, key string, body io.Reader) (*http.Request, error) {
+	endpoint := c.Endpoint
+	if endpoint == "" {
+		endpoint = "https://s3." + c.Region + ".amazonaws.com"
+	}
+	u, err := url.Parse(strings.TrimSuffix(endpoint, "/") + "/" + c.Bucket + "/" + strings.TrimPrefix(key, "/"))
+	if err != nil {
+		return nil, err
+	}
+	return http.NewRequestWithContext(ctx, method, u.String(), body)
+}
+
+func (c *S3Client) do(req *http.Request) (*http.Response, error) {
+	now := time.Now
+	if c.now != nil {
+		now = c.now
+	}
+	req.Header.Set("X-Amz-Content-Sha256", unsignedPayload)
+	c.sign(req, now().UTC())
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkStatus(resp, http.StatusOK); err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+	return resp, nil
+}
+
+// sign adds the AWS Signature Version 4 authorization header to the request, covering its host, range and x-amz-*
+// headers
+func (c *S3Client) sign(req *http.Request, t time.Time) {
+	signV4(req, t, req.Header.Get("X-Amz-Content-Sha256"), c.AccessKeyID, c.SecretAccessKey, c.Region, "s3")
+}
+
+// signV4 signs a request to an AWS service with Signature Version 4. payloadHash is the hex encoded SHA-256 of the
+// body, or unsignedPayload. The service is a parameter so that the signing can be checked against the generic test
+// suite of Signature Version 4, besides the S3 examples.
+func signV4(req *http.Request, t time.Time, payloadHash string, accessKeyID string, secretAccessKey string, region string, service string) {
+	amzDate := t.Format("20060102T150405Z")
+	date := t.Format("20060102")
+	req.Header.Set("X-Amz-Date", amzDate)
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		name = strings.ToLower(name)
+		if strings.HasPrefix(name, "x-amz-") || name == "range" {
+			headers[name] = strings.TrimSpace(strings.Join(values, ","))
+		}
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	scope := date + "/" + region + "/" + service + "/aws4_request"
+	stringToSign := strings.Join([]string{"AWS4-HMAC-SHA256", amzDate, scope, hashHex([]byte(canonicalRequest))}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+secretAccessKey), date)
+	for _, part := range []string{region, service, "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		accessKeyID, scope, signedHeaders, signature))
+}
+
+// canonicalQuery returns the query parameters sorted by name, encoded the way Signature Version 4 expects
+func canonicalQuery(query url.Values) string {
+	var params []string
+	for name, values := range query {
+		for _, value := range values {
+			params = append(params, awsEscape(name)+"="+awsEscape(value))
+		}
+	}
+	sort.Strings(params)
+	return strings.Join(params, "&")
+}
+
+// awsEscape percent-encodes everything but the unreserved characters of RFC 3986
+func awsEscape(s string) string {
+	return strings.ReplaceAll(url.QueryEscape(s), "+", "%20")
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+func hashHex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
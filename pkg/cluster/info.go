@@ -13,29 +13,140 @@
 package cluster
 
 import (
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/discovery"
 	"sigs.k8s.io/controller-runtime/pkg/client/config"
 )
 
+const (
+	// VolumeSnapshotGroup is the API group of the CSI volume snapshot resources
+	VolumeSnapshotGroup = "snapshot.storage.k8s.io"
+	// GatewayGroup is the API group of the Gateway API resources
+	GatewayGroup = "gateway.networking.k8s.io"
+	// CertManagerGroup is the API group of the cert-manager resources
+	CertManagerGroup = "cert-manager.io"
+)
+
+// IsOpenShift returns true if the cluster serves the OpenShift route API
 func IsOpenShift() (bool, error) {
-	kubeCfg, err := config.GetConfig()
+	return HasAPIGroup("route.openshift.io")
+}
+
+// VolumeSnapshotAPIVersion returns the version of the CSI VolumeSnapshot API served by the cluster, or an empty string
+// if it isn't
+func VolumeSnapshotAPIVersion() (string, error) {
+	return ServedAPIVersion(VolumeSnapshotGroup, "v1", "v1beta1")
+}
+
+// HasCertManager returns true if cert-manager is installed on the cluster
+func HasCertManager() (bool, error) {
+	return HasAPIVersion(CertManagerGroup, "v1")
+}
+
+// HasIngressV1 returns true if the cluster serves ingresses through the networking.k8s.io/v1 API
+func HasIngressV1() (bool, error) {
+	return HasAPIVersion("networking.k8s.io", "v1")
+}
+
+// HasPodDisruptionBudgetV1 returns true if the cluster serves pod disruption budgets through the policy/v1 API
+func HasPodDisruptionBudgetV1() (bool, error) {
+	return HasAPIVersion("policy", "v1")
+}
+
+// HasCronJobV1 returns true if the cluster serves cron jobs through the batch/v1 API. Jobs have always been served
+// through it, so the cron job resource itself is looked up.
+func HasCronJobV1() (bool, error) {
+	return HasAPIResource("batch/v1", "cronjobs")
+}
+
+// GatewayAPIVersion returns the version of the Gateway API served by the cluster, or an empty string if it isn't
+func GatewayAPIVersion() (string, error) {
+	return ServedAPIVersion(GatewayGroup, "v1", "v1beta1")
+}
+
+// HasAPIGroup returns true if the cluster serves the given API group
+func HasAPIGroup(group string) (bool, error) {
+	apiList, err := serverGroups()
 	if err != nil {
 		return false, err
 	}
-	discoveryClient, err := discovery.NewDiscoveryClientForConfig(kubeCfg)
+	return findAPIGroup(apiList.Groups, group) != nil, nil
+}
+
+// HasAPIVersion returns true if the cluster serves the given version of an API group
+func HasAPIVersion(group string, version string) (bool, error) {
+	apiList, err := serverGroups()
 	if err != nil {
 		return false, err
 	}
-	apiList, err := discoveryClient.ServerGroups()
+	return servesAPIVersion(apiList.Groups, group, version), nil
+}
+
+// ServedAPIVersion returns the first of the given versions of an API group served by the cluster, or an empty string if
+// none of them is
+func ServedAPIVersion(group string, versions ...string) (string, error) {
+	apiList, err := serverGroups()
+	if err != nil {
+		return "", err
+	}
+	return servedAPIVersion(apiList.Groups, group, versions), nil
+}
+
+// servedAPIVersion returns the first of the given versions of an API group that is among the served groups
+func servedAPIVersion(groups []metav1.APIGroup, group string, versions []string) string {
+	for _, version := range versions {
+		if servesAPIVersion(groups, group, version) {
+			return version
+		}
+	}
+	return ""
+}
+
+// servesAPIVersion returns true if the given version of an API group is among the served groups
+func servesAPIVersion(groups []metav1.APIGroup, group string, version string) bool {
+	apiGroup := findAPIGroup(groups, group)
+	if apiGroup == nil {
+		return false
+	}
+	for _, groupVersion := range apiGroup.Versions {
+		if groupVersion.Version == version {
+			return true
+		}
+	}
+	return false
+}
+
+// HasAPIResource returns true if the cluster serves the given resource through an API group version
+func HasAPIResource(groupVersion string, resource string) (bool, error) {
+	discoveryClient, err := newDiscoveryClient()
 	if err != nil {
 		return false, err
 	}
-	if findAPIGroup(apiList.Groups, "route.openshift.io") == nil {
+	resourceList, err := discoveryClient.ServerResourcesForGroupVersion(groupVersion)
+	if errors.IsNotFound(err) {
 		return false, nil
-	} else {
-		return true, nil
 	}
+	if err != nil {
+		return false, err
+	}
+	return findAPIResource(resourceList.APIResources, resource) != nil, nil
+}
+
+func serverGroups() (*metav1.APIGroupList, error) {
+	discoveryClient, err := newDiscoveryClient()
+	if err != nil {
+		return nil, err
+	}
+	return discoveryClient.ServerGroups()
+}
+
+func newDiscoveryClient() (*discovery.DiscoveryClient, error) {
+	kubeCfg, err := config.GetConfig()
+	if err != nil {
+		return nil, err
+	}
+	return discovery.NewDiscoveryClientForConfig(kubeCfg)
 }
 
 func findAPIGroup(source []metav1.APIGroup, apiName string) *metav1.APIGroup {
@@ -46,3 +157,12 @@ func findAPIGroup(source []metav1.APIGroup, apiName string) *metav1.APIGroup {
 	}
 	return nil
 }
+
+func findAPIResource(source []metav1.APIResource, resourceName string) *metav1.APIResource {
+	for i := 0; i < len(source); i++ {
+		if source[i].Name == resourceName {
+			return &source[i]
+		}
+	}
+	return nil
+}
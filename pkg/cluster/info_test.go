@@ -0,0 +1,119 @@
+//
+// Copyright (c) 2020 Red Hat, Inc.
+// This program and the accompanying materials are made
+// available under the terms of the Eclipse Public License 2.0
+// which is available at https://www.eclipse.org/legal/epl-2.0/
+//
+// SPDX-License-Identifier: EPL-2.0
+//
+// Contributors:
+//   Red Hat, Inc. - initial API and implementation
+
+package cluster
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestServesAPIVersion(t *testing.T) {
+	tests := []struct {
+		name   string
+		groups []metav1.APIGroup
+		want   bool
+	}{
+		{
+			name:   "Case 1: v1 volume snapshots served",
+			groups: []metav1.APIGroup{snapshotGroup("v1", "v1beta1")},
+			want:   true,
+		},
+		{
+			name:   "Case 2: Volume snapshot group served without v1",
+			groups: []metav1.APIGroup{snapshotGroup("v1beta1")},
+			want:   false,
+		},
+		{
+			name:   "Case 3: Volume snapshot group not served",
+			groups: []metav1.APIGroup{{Name: CertManagerGroup}},
+			want:   false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := servesAPIVersion(tt.groups, VolumeSnapshotGroup, "v1"); got != tt.want {
+				t.Errorf("TestServesAPIVersion error: expected: %v got: %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestServedAPIVersion(t *testing.T) {
+	tests := []struct {
+		name   string
+		groups []metav1.APIGroup
+		want   string
+	}{
+		{
+			name:   "Case 1: v1 and v1beta1 volume snapshots served",
+			groups: []metav1.APIGroup{snapshotGroup("v1", "v1beta1")},
+			want:   "v1",
+		},
+		{
+			name:   "Case 2: Only v1beta1 volume snapshots served",
+			groups: []metav1.APIGroup{snapshotGroup("v1beta1")},
+			want:   "v1beta1",
+		},
+		{
+			name:   "Case 3: Only v1alpha1 volume snapshots served",
+			groups: []metav1.APIGroup{snapshotGroup("v1alpha1")},
+			want:   "",
+		},
+		{
+			name:   "Case 4: Volume snapshot group not served",
+			groups: []metav1.APIGroup{{Name: CertManagerGroup}},
+			want:   "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := servedAPIVersion(tt.groups, VolumeSnapshotGroup, []string{"v1", "v1beta1"}); got != tt.want {
+				t.Errorf("TestServedAPIVersion error: expected: %v got: %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestFindAPIResource(t *testing.T) {
+	tests := []struct {
+		name      string
+		resources []metav1.APIResource
+		want      bool
+	}{
+		{
+			name:      "Case 1: batch/v1 serves cron jobs",
+			resources: []metav1.APIResource{{Name: "cronjobs", Kind: "CronJob"}, {Name: "cronjobs/status", Kind: "CronJob"}, {Name: "jobs", Kind: "Job"}},
+			want:      true,
+		},
+		{
+			name:      "Case 2: batch/v1 only serves jobs",
+			resources: []metav1.APIResource{{Name: "jobs", Kind: "Job"}, {Name: "jobs/status", Kind: "Job"}},
+			want:      false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := findAPIResource(tt.resources, "cronjobs") != nil; got != tt.want {
+				t.Errorf("TestFindAPIResource error: expected: %v got: %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func snapshotGroup(versions ...string) metav1.APIGroup {
+	group := metav1.APIGroup{Name: VolumeSnapshotGroup}
+	for _, version := range versions {
+		group.Versions = append(group.Versions, metav1.GroupVersionForDiscovery{GroupVersion: VolumeSnapshotGroup + "/" + version, Version: version})
+	}
+	return group
+}
@@ -15,7 +15,13 @@ package config
 var ControllerCfg ControllerConfig
 
 type ControllerConfig struct {
-	isOpenShift bool
+	isOpenShift              bool
+	volumeSnapshotAPIVersion string
+	hasIngressV1             bool
+	gatewayAPIVersion        string
+	hasCertManager           bool
+	hasPDBV1                 bool
+	hasCronJobV1             bool
 }
 
 func (c *ControllerConfig) IsOpenShift() bool {
@@ -25,3 +31,59 @@ func (c *ControllerConfig) IsOpenShift() bool {
 func (c *ControllerConfig) SetIsOpenShift(isOpenShift bool) {
 	c.isOpenShift = isOpenShift
 }
+
+// HasVolumeSnapshots returns true if the cluster serves a version of the CSI VolumeSnapshot API
+func (c *ControllerConfig) HasVolumeSnapshots() bool {
+	return c.volumeSnapshotAPIVersion != ""
+}
+
+// VolumeSnapshotAPIVersion returns the version of the CSI VolumeSnapshot API served by the cluster, or an empty string
+// if it isn't
+func (c *ControllerConfig) VolumeSnapshotAPIVersion() string {
+	return c.volumeSnapshotAPIVersion
+}
+
+func (c *ControllerConfig) SetVolumeSnapshotAPIVersion(volumeSnapshotAPIVersion string) {
+	c.volumeSnapshotAPIVersion = volumeSnapshotAPIVersion
+}
+
+func (c *ControllerConfig) HasIngressV1() bool {
+	return c.hasIngressV1
+}
+
+func (c *ControllerConfig) SetHasIngressV1(hasIngressV1 bool) {
+	c.hasIngressV1 = hasIngressV1
+}
+
+// GatewayAPIVersion returns the version of the Gateway API served by the cluster, or an empty string if it isn't
+func (c *ControllerConfig) GatewayAPIVersion() string {
+	return c.gatewayAPIVersion
+}
+
+func (c *ControllerConfig) SetGatewayAPIVersion(gatewayAPIVersion string) {
+	c.gatewayAPIVersion = gatewayAPIVersion
+}
+
+func (c *ControllerConfig) HasCertManager() bool {
+	return c.hasCertManager
+}
+
+func (c *ControllerConfig) SetHasCertManager(hasCertManager bool) {
+	c.hasCertManager = hasCertManager
+}
+
+func (c *ControllerConfig) HasPodDisruptionBudgetV1() bool {
+	return c.hasPDBV1
+}
+
+func (c *ControllerConfig) SetHasPodDisruptionBudgetV1(hasPDBV1 bool) {
+	c.hasPDBV1 = hasPDBV1
+}
+
+func (c *ControllerConfig) HasCronJobV1() bool {
+	return c.hasCronJobV1
+}
+
+func (c *ControllerConfig) SetHasCronJobV1(hasCronJobV1 bool) {
+	c.hasCronJobV1 = hasCronJobV1
+}
@@ -0,0 +1,167 @@
+//
+// Copyright (c) 2020 Red Hat, Inc.
+// This program and the accompanying materials are made
+// available under the terms of the Eclipse Public License 2.0
+// which is available at https://www.eclipse.org/legal/epl-2.0/
+//
+// SPDX-License-Identifier: EPL-2.0
+//
+// Contributors:
+//   Red Hat, Inc. - initial API and implementation
+
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	registryv1beta1 "github.com/devfile/registry-operator/api/v1beta1"
+)
+
+const metricsPrefix = "devfile_registry_"
+
+// Steps of the DevfileRegistry reconcile that reconcile errors are counted for
+const (
+	StepFetch             = "fetch"
+	StepService           = "ensureService"
+	StepPVC               = "ensurePVC"
+	StepDeployment        = "ensureDeployment"
+	StepDisruptionBudget  = "ensurePodDisruptionBudget"
+	StepDevfilesRoute     = "ensureDevfilesRoute"
+	StepOCIRoute          = "ensureOCIRoute"
+	StepIngress           = "ensureIngress"
+	StepHTTPRoute         = "ensureHTTPRoute"
+	StepCertificate       = "ensureCertificate"
+	StepOCIConfig         = "ensureOCIConfig"
+	StepGarbageCollection = "ensureGarbageCollection"
+	StepStatus            = "updateStatus"
+	StepFinalize          = "finalize"
+)
+
+// steps lists every reconcile step, so that their series can be deleted along with the DevfileRegistry
+var steps = []string{
+	StepFetch,
+	StepService,
+	StepPVC,
+	StepDeployment,
+	StepDisruptionBudget,
+	StepDevfilesRoute,
+	StepOCIRoute,
+	StepIngress,
+	StepHTTPRoute,
+	StepCertificate,
+	StepOCIConfig,
+	StepGarbageCollection,
+	StepStatus,
+	StepFinalize,
+}
+
+// phases lists every phase a DevfileRegistry can be in, so that the series of the other phases can be reset
+var phases = []registryv1beta1.DevfileRegistryPhase{
+	registryv1beta1.DevfileRegistryPhasePending,
+	registryv1beta1.DevfileRegistryPhaseDeploying,
+	registryv1beta1.DevfileRegistryPhaseReady,
+	registryv1beta1.DevfileRegistryPhaseFailed,
+	registryv1beta1.DevfileRegistryPhaseDeleting,
+}
+
+var (
+	// registryPhase is 1 for the current phase of each DevfileRegistry and 0 for the others. Summing it by phase
+	// gives the number of registries in each phase.
+	registryPhase = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: metricsPrefix + "phase",
+		Help: "The phase of the devfile registry, 1 for the current phase and 0 for the others.",
+	}, []string{"namespace", "name", "phase"})
+
+	timeToReady = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: metricsPrefix + "time_to_ready_seconds",
+		Help: "Time between the creation of the devfile registry and the last time it became ready.",
+	}, []string{"namespace", "name"})
+
+	reconcileErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: metricsPrefix + "reconcile_errors_total",
+		Help: "Number of errors hit while reconciling the devfile registry, by reconcile step.",
+	}, []string{"namespace", "name", "step"})
+
+	probeLatency = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: metricsPrefix + "probe_latency_seconds",
+		Help: "Latency of the last successful readiness probe of the devfile registry server.",
+	}, []string{"namespace", "name"})
+
+	storageCapacity = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: metricsPrefix + "storage_capacity_bytes",
+		Help: "Capacity requested for the persistent storage of the devfile registry.",
+	}, []string{"namespace", "name"})
+
+	storageUsed = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: metricsPrefix + "storage_used_bytes",
+		Help: "Bytes used on the persistent storage of the devfile registry, as last measured.",
+	}, []string{"namespace", "name"})
+)
+
+func init() {
+	// Collectors registered with controller-runtime are served on the manager's metrics endpoint
+	metrics.Registry.MustRegister(registryPhase, timeToReady, reconcileErrors, probeLatency, storageCapacity, storageUsed)
+}
+
+// SetPhase records the current phase of a DevfileRegistry
+func SetPhase(namespace, name string, phase registryv1beta1.DevfileRegistryPhase) {
+	for _, p := range phases {
+		value := 0.0
+		if p == phase {
+			value = 1
+		}
+		registryPhase.WithLabelValues(namespace, name, string(p)).Set(value)
+	}
+}
+
+// SetTimeToReady records how long a DevfileRegistry took to become ready after it was created
+func SetTimeToReady(namespace, name string, duration time.Duration) {
+	timeToReady.WithLabelValues(namespace, name).Set(duration.Seconds())
+}
+
+// IncReconcileErrors counts an error hit by a DevfileRegistry reconcile at the given step
+func IncReconcileErrors(namespace, name, step string) {
+	reconcileErrors.WithLabelValues(namespace, name, step).Inc()
+}
+
+// SetProbeLatency records the latency of the last successful probe of a DevfileRegistry server
+func SetProbeLatency(namespace, name string, latency time.Duration) {
+	probeLatency.WithLabelValues(namespace, name).Set(latency.Seconds())
+}
+
+// SetStorageCapacity records the capacity of the persistent storage requested for a DevfileRegistry
+func SetStorageCapacity(namespace, name string, bytes int64) {
+	storageCapacity.WithLabelValues(namespace, name).Set(float64(bytes))
+}
+
+// DeleteStorageCapacity removes the storage capacity of a DevfileRegistry that doesn't use persistent storage
+func DeleteStorageCapacity(namespace, name string) {
+	storageCapacity.DeleteLabelValues(namespace, name)
+}
+
+// SetStorageUsed records the bytes used on the persistent storage of a DevfileRegistry
+func SetStorageUsed(namespace, name string, bytes int64) {
+	storageUsed.WithLabelValues(namespace, name).Set(float64(bytes))
+}
+
+// DeleteStorageUsed removes the storage usage of a DevfileRegistry that doesn't use persistent storage
+func DeleteStorageUsed(namespace, name string) {
+	storageUsed.DeleteLabelValues(namespace, name)
+}
+
+// DeleteRegistry removes every series of a DevfileRegistry that was deleted
+func DeleteRegistry(namespace, name string) {
+	for _, p := range phases {
+		registryPhase.DeleteLabelValues(namespace, name, string(p))
+	}
+	timeToReady.DeleteLabelValues(namespace, name)
+	probeLatency.DeleteLabelValues(namespace, name)
+	storageCapacity.DeleteLabelValues(namespace, name)
+	storageUsed.DeleteLabelValues(namespace, name)
+	for _, step := range steps {
+		reconcileErrors.DeleteLabelValues(namespace, name, step)
+	}
+}
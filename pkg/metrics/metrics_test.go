@@ -0,0 +1,91 @@
+//
+// Copyright (c) 2020 Red Hat, Inc.
+// This program and the accompanying materials are made
+// available under the terms of the Eclipse Public License 2.0
+// which is available at https://www.eclipse.org/legal/epl-2.0/
+//
+// SPDX-License-Identifier: EPL-2.0
+//
+// Contributors:
+//   Red Hat, Inc. - initial API and implementation
+
+package metrics
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	registryv1beta1 "github.com/devfile/registry-operator/api/v1beta1"
+)
+
+func TestSetPhase(t *testing.T) {
+	tests := []struct {
+		name   string
+		phases []registryv1beta1.DevfileRegistryPhase
+		want   map[registryv1beta1.DevfileRegistryPhase]float64
+	}{
+		{
+			name:   "Case 1: New registry",
+			phases: []registryv1beta1.DevfileRegistryPhase{registryv1beta1.DevfileRegistryPhasePending},
+			want: map[registryv1beta1.DevfileRegistryPhase]float64{
+				registryv1beta1.DevfileRegistryPhasePending:   1,
+				registryv1beta1.DevfileRegistryPhaseDeploying: 0,
+				registryv1beta1.DevfileRegistryPhaseReady:     0,
+				registryv1beta1.DevfileRegistryPhaseFailed:    0,
+			},
+		},
+		{
+			name: "Case 2: Registry moving to another phase",
+			phases: []registryv1beta1.DevfileRegistryPhase{
+				registryv1beta1.DevfileRegistryPhaseDeploying,
+				registryv1beta1.DevfileRegistryPhaseReady,
+			},
+			want: map[registryv1beta1.DevfileRegistryPhase]float64{
+				registryv1beta1.DevfileRegistryPhasePending:   0,
+				registryv1beta1.DevfileRegistryPhaseDeploying: 0,
+				registryv1beta1.DevfileRegistryPhaseReady:     1,
+				registryv1beta1.DevfileRegistryPhaseFailed:    0,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer DeleteRegistry("test-namespace", "test-registry")
+			for _, phase := range tt.phases {
+				SetPhase("test-namespace", "test-registry", phase)
+			}
+			for phase, want := range tt.want {
+				got := testutil.ToFloat64(registryPhase.WithLabelValues("test-namespace", "test-registry", string(phase)))
+				if got != want {
+					t.Errorf("TestSetPhase error: value mismatch for phase %s, expected: %v got: %v", phase, want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestDeleteRegistry(t *testing.T) {
+	SetPhase("test-namespace", "test-registry", registryv1beta1.DevfileRegistryPhaseReady)
+	SetStorageCapacity("test-namespace", "test-registry", 1024)
+	SetStorageUsed("test-namespace", "test-registry", 512)
+	IncReconcileErrors("test-namespace", "test-registry", StepService)
+	IncReconcileErrors("test-namespace", "other-registry", StepService)
+	defer DeleteRegistry("test-namespace", "other-registry")
+
+	DeleteRegistry("test-namespace", "test-registry")
+
+	if got := testutil.CollectAndCount(registryPhase); got != 0 {
+		t.Errorf("TestDeleteRegistry error: phase series mismatch, expected: %v got: %v", 0, got)
+	}
+	if got := testutil.CollectAndCount(storageCapacity); got != 0 {
+		t.Errorf("TestDeleteRegistry error: storage capacity series mismatch, expected: %v got: %v", 0, got)
+	}
+	if got := testutil.CollectAndCount(storageUsed); got != 0 {
+		t.Errorf("TestDeleteRegistry error: storage used series mismatch, expected: %v got: %v", 0, got)
+	}
+	// Only the series of the deleted registry are removed
+	if got := testutil.CollectAndCount(reconcileErrors); got != 1 {
+		t.Errorf("TestDeleteRegistry error: reconcile error series mismatch, expected: %v got: %v", 1, got)
+	}
+}
@@ -0,0 +1,114 @@
+//
+// Copyright (c) 2020 Red Hat, Inc.
+// This program and the accompanying materials are made
+// available under the terms of the Eclipse Public License 2.0
+// which is available at https://www.eclipse.org/legal/epl-2.0/
+//
+// SPDX-License-Identifier: EPL-2.0
+//
+// Contributors:
+//   Red Hat, Inc. - initial API and implementation
+
+package registry
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	registryv1beta1 "github.com/devfile/registry-operator/api/v1beta1"
+)
+
+// HTTPSecretKey is the key of the HTTP secret shared by the OCI registry replicas in its secret
+const HTTPSecretKey = "http-secret"
+
+// addHighAvailabilityToDeployment spreads the replicas of the registry across nodes and zones where the cluster allows
+// it, so that losing one of them doesn't take the whole registry down
+func addHighAvailabilityToDeployment(dep *appsv1.Deployment, labels map[string]string) {
+	podSpec := &dep.Spec.Template.Spec
+	podSpec.Affinity = &corev1.Affinity{
+		PodAntiAffinity: &corev1.PodAntiAffinity{
+			PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{{
+				Weight: 100,
+				PodAffinityTerm: corev1.PodAffinityTerm{
+					LabelSelector: &metav1.LabelSelector{MatchLabels: labels},
+					TopologyKey:   corev1.LabelHostname,
+				},
+			}},
+		},
+	}
+	podSpec.TopologySpreadConstraints = []corev1.TopologySpreadConstraint{{
+		MaxSkew:           1,
+		TopologyKey:       corev1.LabelTopologyZone,
+		WhenUnsatisfiable: corev1.ScheduleAnyway,
+		LabelSelector:     &metav1.LabelSelector{MatchLabels: labels},
+	}}
+}
+
+// GeneratePodDisruptionBudget returns a policy/v1 pod disruption budget letting voluntary disruptions, such as node
+// drains, evict one registry pod at a time
+func GeneratePodDisruptionBudget(cr *registryv1beta1.DevfileRegistry, scheme *runtime.Scheme, labels map[string]string) *policyv1.PodDisruptionBudget {
+	maxUnavailable := intstr.FromInt(1)
+	pdb := &policyv1.PodDisruptionBudget{
+		ObjectMeta: generateObjectMeta(PodDisruptionBudgetName(cr.Name), cr.Namespace, labels),
+		Spec: policyv1.PodDisruptionBudgetSpec{
+			MaxUnavailable: &maxUnavailable,
+			Selector:       &metav1.LabelSelector{MatchLabels: labels},
+		},
+	}
+
+	// Set DevfileRegistry instance as the owner and controller
+	ctrl.SetControllerReference(cr, pdb, scheme)
+	return pdb
+}
+
+// GeneratePodDisruptionBudgetV1beta1 returns the same pod disruption budget as GeneratePodDisruptionBudget, through
+// the policy/v1beta1 API served by clusters that predate policy/v1
+func GeneratePodDisruptionBudgetV1beta1(cr *registryv1beta1.DevfileRegistry, scheme *runtime.Scheme, labels map[string]string) *policyv1beta1.PodDisruptionBudget {
+	maxUnavailable := intstr.FromInt(1)
+	pdb := &policyv1beta1.PodDisruptionBudget{
+		ObjectMeta: generateObjectMeta(PodDisruptionBudgetName(cr.Name), cr.Namespace, labels),
+		Spec: policyv1beta1.PodDisruptionBudgetSpec{
+			MaxUnavailable: &maxUnavailable,
+			Selector:       &metav1.LabelSelector{MatchLabels: labels},
+		},
+	}
+
+	// Set DevfileRegistry instance as the owner and controller
+	ctrl.SetControllerReference(cr, pdb, scheme)
+	return pdb
+}
+
+// GenerateHTTPSecret returns the secret holding the HTTP secret shared by the OCI registry replicas. Its data is
+// filled in by IssueHTTPSecret.
+func GenerateHTTPSecret(cr *registryv1beta1.DevfileRegistry, scheme *runtime.Scheme, labels map[string]string) *corev1.Secret {
+	secret := &corev1.Secret{
+		ObjectMeta: generateObjectMeta(HTTPSecretName(cr.Name), cr.Namespace, labels),
+		Type:       corev1.SecretTypeOpaque,
+	}
+
+	// Set DevfileRegistry instance as the owner and controller
+	ctrl.SetControllerReference(cr, secret, scheme)
+	return secret
+}
+
+// IssueHTTPSecret returns the data of the HTTP secret, and whether it changed. The existing HTTP secret is kept, as
+// changing it breaks the uploads in progress.
+func IssueHTTPSecret(existing *corev1.Secret) (map[string][]byte, bool, error) {
+	if existing != nil && len(existing.Data[HTTPSecretKey]) > 0 {
+		return existing.Data, false, nil
+	}
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, false, err
+	}
+	return map[string][]byte{HTTPSecretKey: []byte(hex.EncodeToString(buf))}, true, nil
+}
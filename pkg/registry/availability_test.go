@@ -0,0 +1,87 @@
+//
+// Copyright (c) 2020 Red Hat, Inc.
+// This program and the accompanying materials are made
+// available under the terms of the Eclipse Public License 2.0
+// which is available at https://www.eclipse.org/legal/epl-2.0/
+//
+// SPDX-License-Identifier: EPL-2.0
+//
+// Contributors:
+//   Red Hat, Inc. - initial API and implementation
+
+package registry
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	registryv1beta1 "github.com/devfile/registry-operator/api/v1beta1"
+)
+
+func TestGenerateDeploymentReplicas(t *testing.T) {
+	replicas := int32(3)
+	tests := []struct {
+		name         string
+		replicas     *int32
+		wantReplicas int32
+		wantSpread   bool
+	}{
+		{
+			name:         "Case 1: Single replica by default",
+			wantReplicas: 1,
+			wantSpread:   false,
+		},
+		{
+			name:         "Case 2: Replicas spread across nodes and zones",
+			replicas:     &replicas,
+			wantReplicas: 3,
+			wantSpread:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cr := &registryv1beta1.DevfileRegistry{Spec: registryv1beta1.DevfileRegistrySpec{Replicas: tt.replicas}}
+			cr.Name = "test-registry"
+			dep := GenerateDeployment(cr, nil, runtime.NewScheme(), LabelsForDevfileRegistry(cr.Name))
+			if *dep.Spec.Replicas != tt.wantReplicas {
+				t.Errorf("TestGenerateDeploymentReplicas error: replicas mismatch, expected: %v got: %v", tt.wantReplicas, *dep.Spec.Replicas)
+			}
+			podSpec := dep.Spec.Template.Spec
+			spread := podSpec.Affinity != nil && podSpec.Affinity.PodAntiAffinity != nil && len(podSpec.TopologySpreadConstraints) > 0
+			if spread != tt.wantSpread {
+				t.Errorf("TestGenerateDeploymentReplicas error: spread mismatch, expected: %v got: %v", tt.wantSpread, spread)
+			}
+			var httpSecret bool
+			for _, env := range podSpec.Containers[1].Env {
+				if env.Name == "REGISTRY_HTTP_SECRET" {
+					httpSecret = env.ValueFrom.SecretKeyRef.Name == HTTPSecretName(cr.Name)
+				}
+			}
+			if httpSecret != tt.wantSpread {
+				t.Errorf("TestGenerateDeploymentReplicas error: shared HTTP secret mismatch, expected: %v got: %v", tt.wantSpread, httpSecret)
+			}
+		})
+	}
+}
+
+func TestIssueHTTPSecret(t *testing.T) {
+	data, changed, err := IssueHTTPSecret(nil)
+	if err != nil {
+		t.Fatalf("TestIssueHTTPSecret error: unexpected error: %v", err)
+	}
+	if !changed || len(data[HTTPSecretKey]) == 0 {
+		t.Fatalf("TestIssueHTTPSecret error: expected a new HTTP secret, got: %v", data)
+	}
+
+	// The HTTP secret must stay the same, or the uploads in progress break
+	existing := &corev1.Secret{Data: data}
+	kept, changed, err := IssueHTTPSecret(existing)
+	if err != nil {
+		t.Fatalf("TestIssueHTTPSecret error: unexpected error: %v", err)
+	}
+	if changed || string(kept[HTTPSecretKey]) != string(data[HTTPSecretKey]) {
+		t.Errorf("TestIssueHTTPSecret error: HTTP secret mismatch, expected: %s got: %s", data[HTTPSecretKey], kept[HTTPSecretKey])
+	}
+}
@@ -0,0 +1,105 @@
+//
+// Copyright (c) 2020 Red Hat, Inc.
+// This program and the accompanying materials are made
+// available under the terms of the Eclipse Public License 2.0
+// which is available at https://www.eclipse.org/legal/epl-2.0/
+//
+// SPDX-License-Identifier: EPL-2.0
+//
+// Contributors:
+//   Red Hat, Inc. - initial API and implementation
+
+package registry
+
+import (
+	"sort"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	registryv1beta1 "github.com/devfile/registry-operator/api/v1beta1"
+)
+
+const (
+	// BackupLabel is set on the volume snapshots taken by a DevfileRegistryBackup to its name
+	BackupLabel = "registry.devfile.io/backup"
+	// BackupUIDLabel is set on the volume snapshots taken by a DevfileRegistryBackup to its UID, so that a backup
+	// recreated under the same name doesn't prune the snapshots of the old one
+	BackupUIDLabel = "registry.devfile.io/backup-uid"
+	// DefaultBackupRetain is the number of snapshots a DevfileRegistryBackup keeps when spec.retain isn't set
+	DefaultBackupRetain = 3
+)
+
+// GetBackupRetain returns the number of snapshots the DevfileRegistryBackup keeps
+func GetBackupRetain(backup *registryv1beta1.DevfileRegistryBackup) int {
+	if backup.Spec.Retain != nil {
+		return int(*backup.Spec.Retain)
+	}
+	return DefaultBackupRetain
+}
+
+// GetNextBackupTime returns when the next snapshot of the DevfileRegistryBackup is due. One-off backups are due as
+// soon as they're created, and scheduled ones at the first time of their schedule after the latest snapshot. It
+// returns false when no more snapshots are due.
+func GetNextBackupTime(backup *registryv1beta1.DevfileRegistryBackup) (time.Time, bool, error) {
+	if backup.Spec.Schedule == "" {
+		return backup.CreationTimestamp.Time, backup.Status.LastBackupTime == nil, nil
+	}
+	schedule, err := cron.ParseStandard(backup.Spec.Schedule)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	last := backup.CreationTimestamp.Time
+	if backup.Status.LastBackupTime != nil {
+		last = backup.Status.LastBackupTime.Time
+	}
+	return schedule.Next(last), true, nil
+}
+
+// GenerateBackupSnapshot returns a volume snapshot of the persistent volume claim of the DevfileRegistry, for the
+// backup due at the given time. The snapshot isn't owned by the DevfileRegistryBackup, the registry can still be
+// restored from the snapshots it retained once it's deleted.
+func GenerateBackupSnapshot(backup *registryv1beta1.DevfileRegistryBackup, due time.Time) *unstructured.Unstructured {
+	labels := LabelsForDevfileRegistry(backup.Spec.RegistryName)
+	labels[BackupLabel] = backup.Name
+	labels[BackupUIDLabel] = string(backup.UID)
+
+	snapshot := &unstructured.Unstructured{}
+	snapshot.SetGroupVersionKind(VolumeSnapshotGVK())
+	snapshot.SetName(BackupSnapshotName(backup.Name, due))
+	snapshot.SetNamespace(backup.Namespace)
+	snapshot.SetLabels(labels)
+	unstructured.SetNestedField(snapshot.Object, PVCName(backup.Spec.RegistryName), "spec", "source", "persistentVolumeClaimName")
+	if className := backup.Spec.VolumeSnapshotClassName; className != nil {
+		unstructured.SetNestedField(snapshot.Object, *className, "spec", "volumeSnapshotClassName")
+	}
+	return snapshot
+}
+
+// SortSnapshots sorts volume snapshots from the newest to the oldest
+func SortSnapshots(snapshots []unstructured.Unstructured) {
+	sort.SliceStable(snapshots, func(i, j int) bool {
+		ti, tj := snapshots[i].GetCreationTimestamp(), snapshots[j].GetCreationTimestamp()
+		if ti.Equal(&tj) {
+			return snapshots[i].GetName() > snapshots[j].GetName()
+		}
+		return tj.Before(&ti)
+	})
+}
+
+// GetSnapshotsToPrune splits volume snapshots sorted from the newest to the oldest into the ones to keep and the ones
+// to delete. Every snapshot newer than the retain-th newest ready snapshot is kept, so that snapshots that are still
+// being taken, or failed, never push out the ones the registry can be restored from.
+func GetSnapshotsToPrune(snapshots []unstructured.Unstructured, retain int) ([]unstructured.Unstructured, []unstructured.Unstructured) {
+	ready := 0
+	for i := range snapshots {
+		if isReady, _ := IsVolumeSnapshotReady(&snapshots[i]); isReady {
+			ready++
+		}
+		if ready == retain {
+			return snapshots[:i+1], snapshots[i+1:]
+		}
+	}
+	return snapshots, nil
+}
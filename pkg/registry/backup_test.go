@@ -0,0 +1,164 @@
+//
+// Copyright (c) 2020 Red Hat, Inc.
+// This program and the accompanying materials are made
+// available under the terms of the Eclipse Public License 2.0
+// which is available at https://www.eclipse.org/legal/epl-2.0/
+//
+// SPDX-License-Identifier: EPL-2.0
+//
+// Contributors:
+//   Red Hat, Inc. - initial API and implementation
+
+package registry
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	registryv1beta1 "github.com/devfile/registry-operator/api/v1beta1"
+)
+
+func TestGetNextBackupTime(t *testing.T) {
+	created := time.Date(2021, 6, 1, 10, 30, 0, 0, time.UTC)
+	lastBackup := time.Date(2021, 6, 3, 2, 0, 5, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		schedule   string
+		lastBackup *time.Time
+		want       time.Time
+		wantDue    bool
+	}{
+		{
+			name:    "Case 1: One-off backup due when created",
+			want:    created,
+			wantDue: true,
+		},
+		{
+			name:       "Case 2: One-off backup already taken",
+			lastBackup: &lastBackup,
+			want:       created,
+			wantDue:    false,
+		},
+		{
+			name:     "Case 3: First scheduled backup",
+			schedule: "0 2 * * *",
+			want:     time.Date(2021, 6, 2, 2, 0, 0, 0, time.UTC),
+			wantDue:  true,
+		},
+		{
+			name:       "Case 4: Scheduled backup after the latest one",
+			schedule:   "0 2 * * *",
+			lastBackup: &lastBackup,
+			want:       time.Date(2021, 6, 4, 2, 0, 0, 0, time.UTC),
+			wantDue:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			backup := &registryv1beta1.DevfileRegistryBackup{
+				ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(created)},
+				Spec:       registryv1beta1.DevfileRegistryBackupSpec{Schedule: tt.schedule},
+			}
+			if tt.lastBackup != nil {
+				backup.Status.LastBackupTime = &metav1.Time{Time: *tt.lastBackup}
+			}
+			next, due, err := GetNextBackupTime(backup)
+			if err != nil {
+				t.Fatalf("TestGetNextBackupTime error: unexpected error: %v", err)
+			}
+			if due != tt.wantDue {
+				t.Errorf("TestGetNextBackupTime error: due mismatch, expected: %v got: %v", tt.wantDue, due)
+			}
+			if !next.Equal(tt.want) {
+				t.Errorf("TestGetNextBackupTime error: next backup time mismatch, expected: %v got: %v", tt.want, next)
+			}
+		})
+	}
+}
+
+func TestGetSnapshotsToPrune(t *testing.T) {
+	snapshot := func(name string, ready bool) unstructured.Unstructured {
+		s := unstructured.Unstructured{}
+		s.SetName(name)
+		unstructured.SetNestedField(s.Object, ready, "status", "readyToUse")
+		return s
+	}
+
+	tests := []struct {
+		name       string
+		snapshots  []unstructured.Unstructured
+		retain     int
+		wantKept   []string
+		wantPruned []string
+	}{
+		{
+			name:      "Case 1: Fewer snapshots than retained",
+			snapshots: []unstructured.Unstructured{snapshot("b", true), snapshot("a", true)},
+			retain:    3,
+			wantKept:  []string{"b", "a"},
+		},
+		{
+			name:       "Case 2: Oldest snapshots pruned",
+			snapshots:  []unstructured.Unstructured{snapshot("c", true), snapshot("b", true), snapshot("a", true)},
+			retain:     2,
+			wantKept:   []string{"c", "b"},
+			wantPruned: []string{"a"},
+		},
+		{
+			name:       "Case 3: Snapshots that aren't ready don't count",
+			snapshots:  []unstructured.Unstructured{snapshot("d", false), snapshot("c", true), snapshot("b", false), snapshot("a", true)},
+			retain:     1,
+			wantKept:   []string{"d", "c"},
+			wantPruned: []string{"b", "a"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kept, pruned := GetSnapshotsToPrune(tt.snapshots, tt.retain)
+			if names := snapshotNames(kept); !reflect.DeepEqual(names, tt.wantKept) {
+				t.Errorf("TestGetSnapshotsToPrune error: kept snapshots mismatch, expected: %v got: %v", tt.wantKept, names)
+			}
+			if names := snapshotNames(pruned); !reflect.DeepEqual(names, tt.wantPruned) {
+				t.Errorf("TestGetSnapshotsToPrune error: pruned snapshots mismatch, expected: %v got: %v", tt.wantPruned, names)
+			}
+		})
+	}
+}
+
+func snapshotNames(snapshots []unstructured.Unstructured) []string {
+	var names []string
+	for _, snapshot := range snapshots {
+		names = append(names, snapshot.GetName())
+	}
+	return names
+}
+
+func TestGenerateBackupSnapshot(t *testing.T) {
+	backup := &registryv1beta1.DevfileRegistryBackup{Spec: registryv1beta1.DevfileRegistryBackupSpec{RegistryName: "test-registry"}}
+	backup.Name = "test-backup"
+	backup.Namespace = "test-namespace"
+	backup.UID = "backup-uid"
+	due := time.Date(2021, 7, 1, 12, 0, 0, 0, time.UTC)
+
+	snapshot := GenerateBackupSnapshot(backup, due)
+	if snapshot.GetName() != BackupSnapshotName(backup.Name, due) {
+		t.Errorf("TestGenerateBackupSnapshot error: name mismatch, expected: %v got: %v", BackupSnapshotName(backup.Name, due), snapshot.GetName())
+	}
+	// Deleting the backup mustn't garbage collect the snapshots it retained
+	if len(snapshot.GetOwnerReferences()) > 0 {
+		t.Errorf("TestGenerateBackupSnapshot error: expected no owner, got: %v", snapshot.GetOwnerReferences())
+	}
+	labels := snapshot.GetLabels()
+	if labels[BackupLabel] != backup.Name || labels[BackupUIDLabel] != string(backup.UID) {
+		t.Errorf("TestGenerateBackupSnapshot error: labels mismatch, expected the name and UID of the backup, got: %v", labels)
+	}
+	source, _, _ := unstructured.NestedString(snapshot.Object, "spec", "source", "persistentVolumeClaimName")
+	if source != PVCName(backup.Spec.RegistryName) {
+		t.Errorf("TestGenerateBackupSnapshot error: source mismatch, expected: %v got: %v", PVCName(backup.Spec.RegistryName), source)
+	}
+}
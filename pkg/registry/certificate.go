@@ -0,0 +1,88 @@
+//
+// Copyright (c) 2020 Red Hat, Inc.
+// This program and the accompanying materials are made
+// available under the terms of the Eclipse Public License 2.0
+// which is available at https://www.eclipse.org/legal/epl-2.0/
+//
+// SPDX-License-Identifier: EPL-2.0
+//
+// Contributors:
+//   Red Hat, Inc. - initial API and implementation
+
+package registry
+
+import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	registryv1beta1 "github.com/devfile/registry-operator/api/v1beta1"
+)
+
+// CertificateGVK is the kind of the cert-manager certificates requested for the registry. cert-manager isn't part of
+// the Kubernetes client libraries, so certificates are handled as unstructured objects.
+var CertificateGVK = schema.GroupVersionKind{Group: "cert-manager.io", Version: "v1", Kind: "Certificate"}
+
+// DefaultIssuerKind is the kind of the cert-manager issuer used when the DevfileRegistry doesn't set one
+const DefaultIssuerKind = "Issuer"
+
+// IsCertificateRequested returns true if the operator has to request a certificate from cert-manager for the ingress
+// exposing the DevfileRegistry, rather than using a secret provided by the user
+func IsCertificateRequested(cr *registryv1beta1.DevfileRegistry) bool {
+	return IsTLSEnabled(cr) && cr.Spec.TLS.SecretName == "" && cr.Spec.TLS.Issuer.Name != "" &&
+		GetExposureType(cr) == registryv1beta1.ExposureTypeIngress
+}
+
+// GetTLSSecretName returns the name of the secret holding the certificate of the ingress exposing the DevfileRegistry,
+// or an empty string if it has none
+func GetTLSSecretName(cr *registryv1beta1.DevfileRegistry) string {
+	if IsCertificateRequested(cr) || IsSelfSigned(cr) {
+		return CertificateSecretName(cr.Name)
+	}
+	return cr.Spec.TLS.SecretName
+}
+
+// GenerateCertificate returns a cert-manager certificate for host, signed by the issuer referenced by the
+// DevfileRegistry
+func GenerateCertificate(cr *registryv1beta1.DevfileRegistry, host string, scheme *runtime.Scheme, labels map[string]string) *unstructured.Unstructured {
+	issuerKind := cr.Spec.TLS.Issuer.Kind
+	if issuerKind == "" {
+		issuerKind = DefaultIssuerKind
+	}
+
+	certificate := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"secretName": CertificateSecretName(cr.Name),
+			"dnsNames":   []interface{}{host},
+			"issuerRef": map[string]interface{}{
+				"group": CertificateGVK.Group,
+				"kind":  issuerKind,
+				"name":  cr.Spec.TLS.Issuer.Name,
+			},
+		},
+	}}
+	certificate.SetGroupVersionKind(CertificateGVK)
+	certificate.SetName(CertificateName(cr.Name))
+	certificate.SetNamespace(cr.Namespace)
+	certificate.SetLabels(labels)
+
+	// Set DevfileRegistry instance as the owner and controller
+	ctrl.SetControllerReference(cr, certificate, scheme)
+	return certificate
+}
+
+// IsCertificateReady returns true once cert-manager stored a valid certificate in the certificate's secret, along with
+// the message of the certificate's Ready condition
+func IsCertificateReady(certificate *unstructured.Unstructured) (bool, string) {
+	conditions, _, _ := unstructured.NestedSlice(certificate.Object, "status", "conditions")
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if !ok || condition["type"] != "Ready" {
+			continue
+		}
+		message, _ := condition["message"].(string)
+		return condition["status"] == "True", message
+	}
+	return false, ""
+}
@@ -0,0 +1,174 @@
+//
+// Copyright (c) 2020 Red Hat, Inc.
+// This program and the accompanying materials are made
+// available under the terms of the Eclipse Public License 2.0
+// which is available at https://www.eclipse.org/legal/epl-2.0/
+//
+// SPDX-License-Identifier: EPL-2.0
+//
+// Contributors:
+//   Red Hat, Inc. - initial API and implementation
+
+package registry
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	registryv1beta1 "github.com/devfile/registry-operator/api/v1beta1"
+)
+
+func TestGetTLSSecretName(t *testing.T) {
+	tlsDisabled := false
+
+	tests := []struct {
+		name string
+		spec registryv1beta1.DevfileRegistrySpec
+		want string
+	}{
+		{
+			name: "Case 1: Secret provided by the user",
+			spec: registryv1beta1.DevfileRegistrySpec{
+				TLS: registryv1beta1.DevfileRegistryTLS{SecretName: "registry-cert"},
+			},
+			want: "registry-cert",
+		},
+		{
+			name: "Case 2: Certificate requested from cert-manager",
+			spec: registryv1beta1.DevfileRegistrySpec{
+				TLS: registryv1beta1.DevfileRegistryTLS{Issuer: registryv1beta1.DevfileRegistryTLSIssuer{Name: "letsencrypt"}},
+			},
+			want: "test-registry-tls",
+		},
+		{
+			name: "Case 3: Certificate requested with TLS disabled",
+			spec: registryv1beta1.DevfileRegistrySpec{
+				TLS: registryv1beta1.DevfileRegistryTLS{Enabled: &tlsDisabled, Issuer: registryv1beta1.DevfileRegistryTLSIssuer{Name: "letsencrypt"}},
+			},
+			want: "",
+		},
+		{
+			name: "Case 4: Certificate requested for a LoadBalancer service",
+			spec: registryv1beta1.DevfileRegistrySpec{
+				TLS:      registryv1beta1.DevfileRegistryTLS{Issuer: registryv1beta1.DevfileRegistryTLSIssuer{Name: "letsencrypt"}},
+				Exposure: registryv1beta1.DevfileRegistryExposure{Type: registryv1beta1.ExposureTypeLoadBalancer},
+			},
+			want: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cr := &registryv1beta1.DevfileRegistry{Spec: tt.spec}
+			cr.Name = "test-registry"
+			secretName := GetTLSSecretName(cr)
+			if secretName != tt.want {
+				t.Errorf("TestGetTLSSecretName error: secret name mismatch, expected: %v got: %v", tt.want, secretName)
+			}
+		})
+	}
+}
+
+func TestGenerateCertificate(t *testing.T) {
+	host := "test-registry.example.com"
+
+	tests := []struct {
+		name          string
+		issuer        registryv1beta1.DevfileRegistryTLSIssuer
+		wantIssuerRef map[string]interface{}
+	}{
+		{
+			name:   "Case 1: Issuer kind defaulted",
+			issuer: registryv1beta1.DevfileRegistryTLSIssuer{Name: "letsencrypt"},
+			wantIssuerRef: map[string]interface{}{
+				"group": CertificateGVK.Group,
+				"kind":  DefaultIssuerKind,
+				"name":  "letsencrypt",
+			},
+		},
+		{
+			name:   "Case 2: ClusterIssuer",
+			issuer: registryv1beta1.DevfileRegistryTLSIssuer{Name: "letsencrypt", Kind: "ClusterIssuer"},
+			wantIssuerRef: map[string]interface{}{
+				"group": CertificateGVK.Group,
+				"kind":  "ClusterIssuer",
+				"name":  "letsencrypt",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cr := &registryv1beta1.DevfileRegistry{Spec: registryv1beta1.DevfileRegistrySpec{
+				TLS: registryv1beta1.DevfileRegistryTLS{Issuer: tt.issuer},
+			}}
+			cr.Name = "test-registry"
+			cr.Namespace = "test-namespace"
+
+			certificate := GenerateCertificate(cr, host, runtime.NewScheme(), LabelsForDevfileRegistry(cr.Name))
+			if certificate.GroupVersionKind() != CertificateGVK {
+				t.Errorf("TestGenerateCertificate error: kind mismatch, expected: %v got: %v", CertificateGVK, certificate.GroupVersionKind())
+			}
+			if certificate.GetName() != CertificateName(cr.Name) || certificate.GetNamespace() != cr.Namespace {
+				t.Errorf("TestGenerateCertificate error: name mismatch, expected: %v/%v got: %v/%v", cr.Namespace, CertificateName(cr.Name), certificate.GetNamespace(), certificate.GetName())
+			}
+			issuerRef, _, _ := unstructured.NestedMap(certificate.Object, "spec", "issuerRef")
+			if !reflect.DeepEqual(issuerRef, tt.wantIssuerRef) {
+				t.Errorf("TestGenerateCertificate error: issuerRef mismatch, expected: %v got: %v", tt.wantIssuerRef, issuerRef)
+			}
+			dnsNames, _, _ := unstructured.NestedStringSlice(certificate.Object, "spec", "dnsNames")
+			if !reflect.DeepEqual(dnsNames, []string{host}) {
+				t.Errorf("TestGenerateCertificate error: dnsNames mismatch, expected: %v got: %v", []string{host}, dnsNames)
+			}
+			// The ingress presents the certificate from the secret GetTLSSecretName points it to
+			secretName, _, _ := unstructured.NestedString(certificate.Object, "spec", "secretName")
+			if secretName != GetTLSSecretName(cr) {
+				t.Errorf("TestGenerateCertificate error: secretName mismatch, expected: %v got: %v", GetTLSSecretName(cr), secretName)
+			}
+		})
+	}
+}
+
+func TestIsCertificateReady(t *testing.T) {
+	tests := []struct {
+		name        string
+		conditions  []interface{}
+		wantReady   bool
+		wantMessage string
+	}{
+		{
+			name: "Case 1: Certificate issued",
+			conditions: []interface{}{
+				map[string]interface{}{"type": "Ready", "status": "True", "message": "Certificate is up to date and has not expired"},
+			},
+			wantReady:   true,
+			wantMessage: "Certificate is up to date and has not expired",
+		},
+		{
+			name: "Case 2: Certificate being issued",
+			conditions: []interface{}{
+				map[string]interface{}{"type": "Issuing", "status": "True"},
+				map[string]interface{}{"type": "Ready", "status": "False", "message": "Issuing certificate as Secret does not exist"},
+			},
+			wantReady:   false,
+			wantMessage: "Issuing certificate as Secret does not exist",
+		},
+		{
+			name:      "Case 3: Certificate without status",
+			wantReady: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			certificate := &unstructured.Unstructured{Object: map[string]interface{}{}}
+			if tt.conditions != nil {
+				unstructured.SetNestedSlice(certificate.Object, tt.conditions, "status", "conditions")
+			}
+			ready, message := IsCertificateReady(certificate)
+			if ready != tt.wantReady || message != tt.wantMessage {
+				t.Errorf("TestIsCertificateReady error: expected: %v %q got: %v %q", tt.wantReady, tt.wantMessage, ready, message)
+			}
+		})
+	}
+}
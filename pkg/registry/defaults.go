@@ -12,21 +12,57 @@
 package registry
 
 import (
-	registryv1alpha1 "github.com/devfile/registry-operator/api/v1alpha1"
+	"fmt"
+
+	registryv1beta1 "github.com/devfile/registry-operator/api/v1beta1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 )
 
 const (
+	// DefaultReplicas is the number of pods running the registry
+	DefaultReplicas = 1
+
 	// Default image:tags
 	DefaultDevfileIndexImage = "quay.io/devfile/metadata-server:next"
 	DefaultOCIRegistryImage  = "registry:2.7.1"
 
+	// Default compute resources for the devfile registry containers
+	DefaultDevfileIndexCPURequest    = "100m"
+	DefaultDevfileIndexMemoryRequest = "64Mi"
+	DefaultDevfileIndexCPULimit      = "250m"
+	DefaultDevfileIndexMemoryLimit   = "128Mi"
+	DefaultOCIRegistryCPURequest     = "100m"
+	DefaultOCIRegistryMemoryRequest  = "64Mi"
+	DefaultOCIRegistryCPULimit       = "500m"
+	DefaultOCIRegistryMemoryLimit    = "256Mi"
+
 	// Defaults/constants for devfile registry storages
 	DefaultDevfileRegistryVolumeSize = "1Gi"
 	DevfileRegistryVolumeEnabled     = true
 	DevfileRegistryVolumeName        = "devfile-registry-storage"
+	DefaultReclaimPolicy             = registryv1beta1.ReclaimPolicyDelete
+	DefaultStorageType               = registryv1beta1.StorageTypePVC
+	DefaultS3Region                  = "us-east-1"
+	DefaultUsageAlertThreshold       = 80
+	// S3AccessKeyIDKey and S3SecretAccessKeyKey are the keys of the S3 credentials in the credentials secret
+	S3AccessKeyIDKey     = "AWS_ACCESS_KEY_ID"
+	S3SecretAccessKeyKey = "AWS_SECRET_ACCESS_KEY"
 
 	DevfileRegistryTLSEnabled = true
+	DefaultTLSTermination     = registryv1beta1.TLSTerminationEdge
+
+	// TLSSecretCAKey is the key of the CA certificate in TLS secrets
+	TLSSecretCAKey = "ca.crt"
+	// Defaults/constants for TLS served by the registry containers
+	DevfileRegistryTLSVolumeName = "devfile-registry-tls"
+	DevfileRegistryTLSMountPath  = "/etc/devfile-registry/tls"
+	// TLSSecretChecksumAnnotation is set on the pod template to the checksum of the serving certificate, so that the
+	// pods are rolled when it's renewed
+	TLSSecretChecksumAnnotation = "registry.devfile.io/tls-secret-checksum"
+	// DevfileIndexTLSCertEnv and DevfileIndexTLSKeyEnv point the devfile index server to its certificate and key
+	DevfileIndexTLSCertEnv = "TLS_CERT_FILE"
+	DevfileIndexTLSKeyEnv  = "TLS_KEY_FILE"
 
 	// Defaults/constants for devfile registry services
 	DevfileIndexPortName = "devfile-registry-metadata"
@@ -35,22 +71,135 @@ const (
 	OCIRegistryPort      = 5000
 )
 
-func GetOCIRegistryImage(cr *registryv1alpha1.DevfileRegistry) string {
-	if cr.Spec.OciRegistryImage != "" {
-		return cr.Spec.OciRegistryImage
+// SetDevfileRegistryDefaults fills in every unset field of the DevfileRegistry spec with the operator's defaults, so
+// the stored resource shows what is actually deployed
+func SetDevfileRegistryDefaults(cr *registryv1beta1.DevfileRegistry) {
+	cr.Spec.DevfileIndex.Image = GetDevfileIndexImage(cr)
+	cr.Spec.OCIRegistry.Image = GetOCIRegistryImage(cr)
+
+	storageEnabled := IsStorageEnabled(cr)
+	cr.Spec.Storage.Enabled = &storageEnabled
+	// The remaining storage settings only apply to persistent volume claims
+	if IsPVCEnabled(cr) {
+		cr.Spec.Storage.Size = GetDevfileRegistryVolumeSize(cr)
+		cr.Spec.Storage.ReclaimPolicy = GetReclaimPolicy(cr)
+		cr.Spec.Storage.AccessModes = GetAccessModes(cr)
+		threshold := GetUsageAlertThreshold(cr)
+		cr.Spec.Storage.UsageAlertThreshold = &threshold
+	}
+
+	tlsEnabled := IsTLSEnabled(cr)
+	cr.Spec.TLS.Enabled = &tlsEnabled
+
+	replicas := GetReplicas(cr)
+	cr.Spec.Replicas = &replicas
+}
+
+// GetReplicas returns the number of pods running the registry set in the DevfileRegistry CR
+// If it's not set, it returns the default number of replicas.
+func GetReplicas(cr *registryv1beta1.DevfileRegistry) int32 {
+	if cr.Spec.Replicas != nil {
+		return *cr.Spec.Replicas
+	}
+	return DefaultReplicas
+}
+
+// IsReplicated returns true if the registry runs more than one pod
+func IsReplicated(cr *registryv1beta1.DevfileRegistry) bool {
+	return GetReplicas(cr) > 1
+}
+
+// GetDevfileIndexImage returns the devfile index image set in the DevfileRegistry CR
+// If it's not set, it returns the default devfile index image.
+func GetDevfileIndexImage(cr *registryv1beta1.DevfileRegistry) string {
+	if cr.Spec.DevfileIndex.Image != "" {
+		return cr.Spec.DevfileIndex.Image
+	}
+	return DefaultDevfileIndexImage
+}
+
+func GetOCIRegistryImage(cr *registryv1beta1.DevfileRegistry) string {
+	if cr.Spec.OCIRegistry.Image != "" {
+		return cr.Spec.OCIRegistry.Image
 	}
 	return DefaultOCIRegistryImage
 }
 
-func getDevfileRegistryVolumeSize(cr *registryv1alpha1.DevfileRegistry) string {
-	if cr.Spec.Storage.RegistryVolumeSize != "" {
-		return cr.Spec.Storage.RegistryVolumeSize
+// GetDevfileIndexResources returns the compute resources of the devfile index container set in the DevfileRegistry CR
+// If they're not set, it returns the default resources.
+func GetDevfileIndexResources(cr *registryv1beta1.DevfileRegistry) corev1.ResourceRequirements {
+	if cr.Spec.DevfileIndex.Resources != nil {
+		return *cr.Spec.DevfileIndex.Resources
+	}
+	return corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse(DefaultDevfileIndexCPURequest),
+			corev1.ResourceMemory: resource.MustParse(DefaultDevfileIndexMemoryRequest),
+		},
+		Limits: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse(DefaultDevfileIndexCPULimit),
+			corev1.ResourceMemory: resource.MustParse(DefaultDevfileIndexMemoryLimit),
+		},
+	}
+}
+
+// GetOCIRegistryResources returns the compute resources of the OCI registry container set in the DevfileRegistry CR
+// If they're not set, it returns the default resources.
+func GetOCIRegistryResources(cr *registryv1beta1.DevfileRegistry) corev1.ResourceRequirements {
+	if cr.Spec.OCIRegistry.Resources != nil {
+		return *cr.Spec.OCIRegistry.Resources
+	}
+	return corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse(DefaultOCIRegistryCPURequest),
+			corev1.ResourceMemory: resource.MustParse(DefaultOCIRegistryMemoryRequest),
+		},
+		Limits: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse(DefaultOCIRegistryCPULimit),
+			corev1.ResourceMemory: resource.MustParse(DefaultOCIRegistryMemoryLimit),
+		},
+	}
+}
+
+// GetReclaimPolicy returns the reclaim policy of the registry storage set in the DevfileRegistry CR
+// If it's not set, it returns the default policy.
+func GetReclaimPolicy(cr *registryv1beta1.DevfileRegistry) registryv1beta1.DevfileRegistryReclaimPolicy {
+	if cr.Spec.Storage.ReclaimPolicy != "" {
+		return cr.Spec.Storage.ReclaimPolicy
+	}
+	return DefaultReclaimPolicy
+}
+
+// GetDevfileRegistryVolumeSize returns the size of the registry volume set in the DevfileRegistry CR
+// If it's not set, it returns the default size.
+func GetDevfileRegistryVolumeSize(cr *registryv1beta1.DevfileRegistry) string {
+	if cr.Spec.Storage.Size != "" {
+		return cr.Spec.Storage.Size
 	}
 	return DefaultDevfileRegistryVolumeSize
 }
 
-func GetDevfileRegistryVolumeSource(cr *registryv1alpha1.DevfileRegistry) corev1.VolumeSource {
-	if IsStorageEnabled(cr) {
+// ParsePVCSize returns the size of the registry volume set in the DevfileRegistry CR. It's only checked by the
+// validating webhook, which may be disabled, so it can fail to parse.
+func ParsePVCSize(cr *registryv1beta1.DevfileRegistry) (resource.Quantity, error) {
+	size, err := resource.ParseQuantity(GetDevfileRegistryVolumeSize(cr))
+	if err != nil {
+		return resource.Quantity{}, fmt.Errorf("invalid storage size %q: %v", GetDevfileRegistryVolumeSize(cr), err)
+	}
+	return size, nil
+}
+
+// GetUsageAlertThreshold returns the percentage of the persistent volume in use above which the DevfileRegistry
+// reports its storage as nearly full. If it's not set, it returns the default threshold.
+func GetUsageAlertThreshold(cr *registryv1beta1.DevfileRegistry) int32 {
+	if cr.Spec.Storage.UsageAlertThreshold != nil {
+		return *cr.Spec.Storage.UsageAlertThreshold
+	}
+	return DefaultUsageAlertThreshold
+}
+
+func GetDevfileRegistryVolumeSource(cr *registryv1beta1.DevfileRegistry) corev1.VolumeSource {
+	if IsPVCEnabled(cr) {
 		return corev1.VolumeSource{
 			PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
 				ClaimName: PVCName(cr.Name),
@@ -63,16 +212,48 @@ func GetDevfileRegistryVolumeSource(cr *registryv1alpha1.DevfileRegistry) corev1
 
 // IsStorageEnabled returns true if storage.enabled is set in the DevfileRegistry CR
 // If it's not set, it returns true by default.
-func IsStorageEnabled(cr *registryv1alpha1.DevfileRegistry) bool {
+func IsStorageEnabled(cr *registryv1beta1.DevfileRegistry) bool {
 	if cr.Spec.Storage.Enabled != nil {
 		return *cr.Spec.Storage.Enabled
 	}
 	return DevfileRegistryVolumeEnabled
 }
 
+// GetAccessModes returns the access modes of the registry volume set in the DevfileRegistry CR
+// If they're not set, it returns ReadWriteOnce.
+func GetAccessModes(cr *registryv1beta1.DevfileRegistry) []corev1.PersistentVolumeAccessMode {
+	if len(cr.Spec.Storage.AccessModes) > 0 {
+		return cr.Spec.Storage.AccessModes
+	}
+	return []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce}
+}
+
+// GetStorageType returns where the DevfileRegistry persists its content. If it's not set, it returns the default type.
+func GetStorageType(cr *registryv1beta1.DevfileRegistry) registryv1beta1.DevfileRegistryStorageType {
+	if cr.Spec.Storage.Type != "" {
+		return cr.Spec.Storage.Type
+	}
+	return DefaultStorageType
+}
+
+// IsPVCEnabled returns true if the DevfileRegistry persists its content in a persistent volume claim
+func IsPVCEnabled(cr *registryv1beta1.DevfileRegistry) bool {
+	return IsStorageEnabled(cr) && GetStorageType(cr) == registryv1beta1.StorageTypePVC
+}
+
+// IsS3Enabled returns true if the DevfileRegistry persists its content in an S3-compatible bucket
+func IsS3Enabled(cr *registryv1beta1.DevfileRegistry) bool {
+	return IsStorageEnabled(cr) && GetStorageType(cr) == registryv1beta1.StorageTypeS3
+}
+
+// IsInPodTLSEnabled returns true if the registry containers serve TLS themselves
+func IsInPodTLSEnabled(cr *registryv1beta1.DevfileRegistry) bool {
+	return IsTLSEnabled(cr) && cr.Spec.TLS.InPod
+}
+
 // IsTLSEnabled returns true if tls.enabled is set in the DevfileRegistry CR
 // If it's not set, it returns true by default.
-func IsTLSEnabled(cr *registryv1alpha1.DevfileRegistry) bool {
+func IsTLSEnabled(cr *registryv1beta1.DevfileRegistry) bool {
 	if cr.Spec.TLS.Enabled != nil {
 		return *cr.Spec.TLS.Enabled
 	}
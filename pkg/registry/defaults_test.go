@@ -15,7 +15,7 @@ import (
 	"reflect"
 	"testing"
 
-	registryv1alpha1 "github.com/devfile/registry-operator/api/v1alpha1"
+	registryv1beta1 "github.com/devfile/registry-operator/api/v1beta1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -26,14 +26,14 @@ func TestIsTLSEnabled(t *testing.T) {
 
 	tests := []struct {
 		name string
-		cr   registryv1alpha1.DevfileRegistry
+		cr   registryv1beta1.DevfileRegistry
 		want bool
 	}{
 		{
 			name: "Case 1: TLS enabled in DevfileRegistry CR",
-			cr: registryv1alpha1.DevfileRegistry{
-				Spec: registryv1alpha1.DevfileRegistrySpec{
-					TLS: registryv1alpha1.DevfileRegistrySpecTLS{
+			cr: registryv1beta1.DevfileRegistry{
+				Spec: registryv1beta1.DevfileRegistrySpec{
+					TLS: registryv1beta1.DevfileRegistryTLS{
 						Enabled: &tlsEnabled,
 					},
 				},
@@ -42,9 +42,9 @@ func TestIsTLSEnabled(t *testing.T) {
 		},
 		{
 			name: "Case 2: TLS disabled in DevfileRegistry CR",
-			cr: registryv1alpha1.DevfileRegistry{
-				Spec: registryv1alpha1.DevfileRegistrySpec{
-					TLS: registryv1alpha1.DevfileRegistrySpecTLS{
+			cr: registryv1beta1.DevfileRegistry{
+				Spec: registryv1beta1.DevfileRegistrySpec{
+					TLS: registryv1beta1.DevfileRegistryTLS{
 						Enabled: &tlsDisabled,
 					},
 				},
@@ -53,8 +53,8 @@ func TestIsTLSEnabled(t *testing.T) {
 		},
 		{
 			name: "Case 3: TLS not set, default set to true",
-			cr: registryv1alpha1.DevfileRegistry{
-				Spec: registryv1alpha1.DevfileRegistrySpec{},
+			cr: registryv1beta1.DevfileRegistry{
+				Spec: registryv1beta1.DevfileRegistrySpec{},
 			},
 			want: true,
 		},
@@ -76,14 +76,14 @@ func TestIsStorageEnabled(t *testing.T) {
 
 	tests := []struct {
 		name string
-		cr   registryv1alpha1.DevfileRegistry
+		cr   registryv1beta1.DevfileRegistry
 		want bool
 	}{
 		{
 			name: "Case 1: Storage enabled in DevfileRegistry CR",
-			cr: registryv1alpha1.DevfileRegistry{
-				Spec: registryv1alpha1.DevfileRegistrySpec{
-					Storage: registryv1alpha1.DevfileRegistrySpecStorage{
+			cr: registryv1beta1.DevfileRegistry{
+				Spec: registryv1beta1.DevfileRegistrySpec{
+					Storage: registryv1beta1.DevfileRegistryStorage{
 						Enabled: &storageEnabled,
 					},
 				},
@@ -92,9 +92,9 @@ func TestIsStorageEnabled(t *testing.T) {
 		},
 		{
 			name: "Case 2: Storage disabled in DevfileRegistry CR",
-			cr: registryv1alpha1.DevfileRegistry{
-				Spec: registryv1alpha1.DevfileRegistrySpec{
-					Storage: registryv1alpha1.DevfileRegistrySpecStorage{
+			cr: registryv1beta1.DevfileRegistry{
+				Spec: registryv1beta1.DevfileRegistrySpec{
+					Storage: registryv1beta1.DevfileRegistryStorage{
 						Enabled: &storageDisabled,
 					},
 				},
@@ -103,8 +103,8 @@ func TestIsStorageEnabled(t *testing.T) {
 		},
 		{
 			name: "Case 3: Storage not set, default set to true",
-			cr: registryv1alpha1.DevfileRegistry{
-				Spec: registryv1alpha1.DevfileRegistrySpec{},
+			cr: registryv1beta1.DevfileRegistry{
+				Spec: registryv1beta1.DevfileRegistrySpec{},
 			},
 			want: true,
 		},
@@ -127,17 +127,17 @@ func TestGetDevfileRegistryVolumeSource(t *testing.T) {
 
 	tests := []struct {
 		name string
-		cr   registryv1alpha1.DevfileRegistry
+		cr   registryv1beta1.DevfileRegistry
 		want corev1.VolumeSource
 	}{
 		{
 			name: "Case 1: Storage enabled in DevfileRegistry CR",
-			cr: registryv1alpha1.DevfileRegistry{
+			cr: registryv1beta1.DevfileRegistry{
 				ObjectMeta: metav1.ObjectMeta{
 					Name: crName,
 				},
-				Spec: registryv1alpha1.DevfileRegistrySpec{
-					Storage: registryv1alpha1.DevfileRegistrySpecStorage{
+				Spec: registryv1beta1.DevfileRegistrySpec{
+					Storage: registryv1beta1.DevfileRegistryStorage{
 						Enabled: &storageEnabled,
 					},
 				},
@@ -150,12 +150,12 @@ func TestGetDevfileRegistryVolumeSource(t *testing.T) {
 		},
 		{
 			name: "Case 2: Storage disabled in DevfileRegistry CR",
-			cr: registryv1alpha1.DevfileRegistry{
+			cr: registryv1beta1.DevfileRegistry{
 				ObjectMeta: metav1.ObjectMeta{
 					Name: crName,
 				},
-				Spec: registryv1alpha1.DevfileRegistrySpec{
-					Storage: registryv1alpha1.DevfileRegistrySpecStorage{
+				Spec: registryv1beta1.DevfileRegistrySpec{
+					Storage: registryv1beta1.DevfileRegistryStorage{
 						Enabled: &storageDisabled,
 					},
 				},
@@ -164,11 +164,11 @@ func TestGetDevfileRegistryVolumeSource(t *testing.T) {
 		},
 		{
 			name: "Case 3: Storage not set, default set to true",
-			cr: registryv1alpha1.DevfileRegistry{
+			cr: registryv1beta1.DevfileRegistry{
 				ObjectMeta: metav1.ObjectMeta{
 					Name: crName,
 				},
-				Spec: registryv1alpha1.DevfileRegistrySpec{},
+				Spec: registryv1beta1.DevfileRegistrySpec{},
 			},
 			want: corev1.VolumeSource{
 				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
@@ -191,15 +191,15 @@ func TestGetDevfileRegistryVolumeSource(t *testing.T) {
 func TestGetDevfileRegistryVolumeSize(t *testing.T) {
 	tests := []struct {
 		name string
-		cr   registryv1alpha1.DevfileRegistry
+		cr   registryv1beta1.DevfileRegistry
 		want string
 	}{
 		{
 			name: "Case 1: Volume size set in DevfileRegistry CR",
-			cr: registryv1alpha1.DevfileRegistry{
-				Spec: registryv1alpha1.DevfileRegistrySpec{
-					Storage: registryv1alpha1.DevfileRegistrySpecStorage{
-						RegistryVolumeSize: "5Gi",
+			cr: registryv1beta1.DevfileRegistry{
+				Spec: registryv1beta1.DevfileRegistrySpec{
+					Storage: registryv1beta1.DevfileRegistryStorage{
+						Size: "5Gi",
 					},
 				},
 			},
@@ -207,15 +207,15 @@ func TestGetDevfileRegistryVolumeSize(t *testing.T) {
 		},
 		{
 			name: "Case 2: Volume size not set in DevfileRegistry CR",
-			cr: registryv1alpha1.DevfileRegistry{
-				Spec: registryv1alpha1.DevfileRegistrySpec{},
+			cr: registryv1beta1.DevfileRegistry{
+				Spec: registryv1beta1.DevfileRegistrySpec{},
 			},
 			want: DefaultDevfileRegistryVolumeSize,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			volSize := getDevfileRegistryVolumeSize(&tt.cr)
+			volSize := GetDevfileRegistryVolumeSize(&tt.cr)
 			if volSize != tt.want {
 				t.Errorf("TestGetDevfileRegistryVolumeSize error: storage size mismatch, expected: %v got: %v", tt.want, volSize)
 			}
@@ -223,3 +223,158 @@ func TestGetDevfileRegistryVolumeSize(t *testing.T) {
 	}
 
 }
+
+func TestGetDevfileIndexImage(t *testing.T) {
+	tests := []struct {
+		name string
+		cr   registryv1beta1.DevfileRegistry
+		want string
+	}{
+		{
+			name: "Case 1: Devfile index image set in DevfileRegistry CR",
+			cr: registryv1beta1.DevfileRegistry{
+				Spec: registryv1beta1.DevfileRegistrySpec{
+					DevfileIndex: registryv1beta1.DevfileRegistryDevfileIndex{
+						Image: "quay.io/test/devfile-index:latest",
+					},
+				},
+			},
+			want: "quay.io/test/devfile-index:latest",
+		},
+		{
+			name: "Case 2: Devfile index image not set in DevfileRegistry CR",
+			cr: registryv1beta1.DevfileRegistry{
+				Spec: registryv1beta1.DevfileRegistrySpec{},
+			},
+			want: DefaultDevfileIndexImage,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			image := GetDevfileIndexImage(&tt.cr)
+			if image != tt.want {
+				t.Errorf("TestGetDevfileIndexImage error: image mismatch, expected: %v got: %v", tt.want, image)
+			}
+		})
+	}
+
+}
+
+func TestSetDevfileRegistryDefaults(t *testing.T) {
+	enabled := true
+	disabled := false
+	threshold := int32(DefaultUsageAlertThreshold)
+	replicas := int32(DefaultReplicas)
+	userReplicas := int32(3)
+
+	tests := []struct {
+		name string
+		spec registryv1beta1.DevfileRegistrySpec
+		want registryv1beta1.DevfileRegistrySpec
+	}{
+		{
+			name: "Case 1: Empty spec gets every default",
+			spec: registryv1beta1.DevfileRegistrySpec{},
+			want: registryv1beta1.DevfileRegistrySpec{
+				DevfileIndex: registryv1beta1.DevfileRegistryDevfileIndex{
+					Image: DefaultDevfileIndexImage,
+				},
+				OCIRegistry: registryv1beta1.DevfileRegistryOCIRegistry{
+					Image: DefaultOCIRegistryImage,
+				},
+				Storage: registryv1beta1.DevfileRegistryStorage{
+					Enabled:             &enabled,
+					Size:                DefaultDevfileRegistryVolumeSize,
+					ReclaimPolicy:       DefaultReclaimPolicy,
+					AccessModes:         []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+					UsageAlertThreshold: &threshold,
+				},
+				TLS: registryv1beta1.DevfileRegistryTLS{
+					Enabled: &enabled,
+				},
+				Replicas: &replicas,
+			},
+		},
+		{
+			name: "Case 2: Values set by the user are kept",
+			spec: registryv1beta1.DevfileRegistrySpec{
+				DevfileIndex: registryv1beta1.DevfileRegistryDevfileIndex{
+					Image: "quay.io/test/devfile-index:latest",
+				},
+				OCIRegistry: registryv1beta1.DevfileRegistryOCIRegistry{
+					Image: "registry:latest",
+				},
+				Storage: registryv1beta1.DevfileRegistryStorage{
+					Enabled: &disabled,
+				},
+				TLS: registryv1beta1.DevfileRegistryTLS{
+					Enabled:    &disabled,
+					SecretName: "tls-secret",
+				},
+				Exposure: registryv1beta1.DevfileRegistryExposure{
+					Ingress: registryv1beta1.DevfileRegistryIngress{
+						Domain: "example.com",
+					},
+				},
+				Replicas: &userReplicas,
+			},
+			want: registryv1beta1.DevfileRegistrySpec{
+				DevfileIndex: registryv1beta1.DevfileRegistryDevfileIndex{
+					Image: "quay.io/test/devfile-index:latest",
+				},
+				OCIRegistry: registryv1beta1.DevfileRegistryOCIRegistry{
+					Image: "registry:latest",
+				},
+				Storage: registryv1beta1.DevfileRegistryStorage{
+					Enabled: &disabled,
+				},
+				TLS: registryv1beta1.DevfileRegistryTLS{
+					Enabled:    &disabled,
+					SecretName: "tls-secret",
+				},
+				Exposure: registryv1beta1.DevfileRegistryExposure{
+					Ingress: registryv1beta1.DevfileRegistryIngress{
+						Domain: "example.com",
+					},
+				},
+				Replicas: &userReplicas,
+			},
+		},
+		{
+			name: "Case 3: S3 storage doesn't get the persistent volume claim defaults",
+			spec: registryv1beta1.DevfileRegistrySpec{
+				Storage: registryv1beta1.DevfileRegistryStorage{
+					Type: registryv1beta1.StorageTypeS3,
+					S3:   registryv1beta1.DevfileRegistryS3Storage{Bucket: "devfiles", CredentialsSecret: "s3-credentials"},
+				},
+			},
+			want: registryv1beta1.DevfileRegistrySpec{
+				DevfileIndex: registryv1beta1.DevfileRegistryDevfileIndex{
+					Image: DefaultDevfileIndexImage,
+				},
+				OCIRegistry: registryv1beta1.DevfileRegistryOCIRegistry{
+					Image: DefaultOCIRegistryImage,
+				},
+				Storage: registryv1beta1.DevfileRegistryStorage{
+					Enabled: &enabled,
+					Type:    registryv1beta1.StorageTypeS3,
+					S3:      registryv1beta1.DevfileRegistryS3Storage{Bucket: "devfiles", CredentialsSecret: "s3-credentials"},
+				},
+				TLS: registryv1beta1.DevfileRegistryTLS{
+					Enabled: &enabled,
+				},
+				Replicas: &replicas,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cr := registryv1beta1.DevfileRegistry{Spec: tt.spec}
+			SetDevfileRegistryDefaults(&cr)
+			if !reflect.DeepEqual(cr.Spec, tt.want) {
+				t.Errorf("TestSetDevfileRegistryDefaults error: spec mismatch, expected: %+v got: %+v", tt.want, cr.Spec)
+			}
+		})
+	}
+
+}
@@ -12,19 +12,24 @@
 package registry
 
 import (
+	"crypto/sha256"
+	"fmt"
+	"sort"
+
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	ctrl "sigs.k8s.io/controller-runtime"
 
-	registryv1alpha1 "github.com/devfile/registry-operator/api/v1alpha1"
+	registryv1beta1 "github.com/devfile/registry-operator/api/v1beta1"
 )
 
-func GenerateDeployment(cr *registryv1alpha1.DevfileRegistry, scheme *runtime.Scheme, labels map[string]string) *appsv1.Deployment {
-	replicas := int32(1)
+// GenerateDeployment returns the deployment running the devfile registry. tlsSecret is the secret holding the
+// certificate the registry containers serve, when they serve TLS and it exists.
+func GenerateDeployment(cr *registryv1beta1.DevfileRegistry, tlsSecret *corev1.Secret, scheme *runtime.Scheme, labels map[string]string) *appsv1.Deployment {
+	replicas := GetReplicas(cr)
 
 	dep := &appsv1.Deployment{
 		ObjectMeta: generateObjectMeta(cr.Name, cr.Namespace, labels),
@@ -36,25 +41,20 @@ func GenerateDeployment(cr *registryv1alpha1.DevfileRegistry, scheme *runtime.Sc
 			Template: corev1.PodTemplateSpec{
 				ObjectMeta: metav1.ObjectMeta{
 					Labels: labels,
+					// Roll the pods when the OCI registry configuration changes, it's only read on start up
+					Annotations: map[string]string{
+						OCIRegistryConfigChecksumAnnotation: GetOCIRegistryConfigChecksum(cr),
+					},
 				},
 				Spec: corev1.PodSpec{
 					Containers: []corev1.Container{
 						{
-							Image: cr.Spec.DevfileIndexImage,
+							Image: GetDevfileIndexImage(cr),
 							Name:  "devfile-registry-bootstrap",
 							Ports: []corev1.ContainerPort{{
 								ContainerPort: DevfileIndexPort,
 							}},
-							Resources: corev1.ResourceRequirements{
-								Requests: corev1.ResourceList{
-									corev1.ResourceCPU:    resource.MustParse("100m"),
-									corev1.ResourceMemory: resource.MustParse("64Mi"),
-								},
-								Limits: corev1.ResourceList{
-									corev1.ResourceCPU:    resource.MustParse("250m"),
-									corev1.ResourceMemory: resource.MustParse("128Mi"),
-								},
-							},
+							Resources: GetDevfileIndexResources(cr),
 							LivenessProbe: &corev1.Probe{
 								Handler: corev1.Handler{
 									HTTPGet: &corev1.HTTPGetAction{
@@ -82,20 +82,17 @@ func GenerateDeployment(cr *registryv1alpha1.DevfileRegistry, scheme *runtime.Sc
 							Ports: []corev1.ContainerPort{{
 								ContainerPort: OCIRegistryPort,
 							}},
-							Resources: corev1.ResourceRequirements{
-								Requests: corev1.ResourceList{
-									corev1.ResourceCPU:    resource.MustParse("100m"),
-									corev1.ResourceMemory: resource.MustParse("64Mi"),
-								},
-								Limits: corev1.ResourceList{
-									corev1.ResourceCPU:    resource.MustParse("500m"),
-									corev1.ResourceMemory: resource.MustParse("256Mi"),
-								},
-							},
+							Resources: GetOCIRegistryResources(cr),
+							Env:       GetOCIRegistryEnv(cr),
 							VolumeMounts: []corev1.VolumeMount{
 								{
 									Name:      DevfileRegistryVolumeName,
-									MountPath: "/var/lib/registry",
+									MountPath: OCIRegistryStoragePath,
+								},
+								{
+									Name:      OCIRegistryConfigVolumeName,
+									MountPath: OCIRegistryConfigMountPath,
+									ReadOnly:  true,
 								},
 							},
 						},
@@ -105,12 +102,82 @@ func GenerateDeployment(cr *registryv1alpha1.DevfileRegistry, scheme *runtime.Sc
 							Name:         DevfileRegistryVolumeName,
 							VolumeSource: GetDevfileRegistryVolumeSource(cr),
 						},
+						{
+							Name: OCIRegistryConfigVolumeName,
+							VolumeSource: corev1.VolumeSource{
+								ConfigMap: &corev1.ConfigMapVolumeSource{
+									LocalObjectReference: corev1.LocalObjectReference{Name: OCIRegistryConfigName(cr.Name)},
+								},
+							},
+						},
 					},
 				},
 			},
 		},
 	}
+	if IsInPodTLSEnabled(cr) {
+		addTLSToDeployment(cr, tlsSecret, dep)
+	}
+	if IsReplicated(cr) {
+		addHighAvailabilityToDeployment(dep, labels)
+	}
+
 	// Set Memcached instance as the owner and controller
 	ctrl.SetControllerReference(cr, dep, scheme)
 	return dep
 }
+
+// addTLSToDeployment mounts the serving certificate of the DevfileRegistry into both registry containers, and makes the
+// devfile index server serve TLS with it. The OCI registry is configured through its configuration file. Both only
+// load the certificate on start up, so the pods are rolled when tlsSecret changes.
+func addTLSToDeployment(cr *registryv1beta1.DevfileRegistry, tlsSecret *corev1.Secret, dep *appsv1.Deployment) {
+	if tlsSecret != nil {
+		dep.Spec.Template.Annotations[TLSSecretChecksumAnnotation] = GetSecretChecksum(tlsSecret)
+	}
+	podSpec := &dep.Spec.Template.Spec
+	podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+		Name: DevfileRegistryTLSVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{SecretName: GetTLSSecretName(cr)},
+		},
+	})
+	certFile := DevfileRegistryTLSMountPath + "/" + corev1.TLSCertKey
+	keyFile := DevfileRegistryTLSMountPath + "/" + corev1.TLSPrivateKeyKey
+
+	for i := range podSpec.Containers {
+		container := &podSpec.Containers[i]
+		container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+			Name:      DevfileRegistryTLSVolumeName,
+			MountPath: DevfileRegistryTLSMountPath,
+			ReadOnly:  true,
+		})
+
+		if container.Name == "devfile-registry-bootstrap" {
+			container.Env = append(container.Env,
+				corev1.EnvVar{Name: DevfileIndexTLSCertEnv, Value: certFile},
+				corev1.EnvVar{Name: DevfileIndexTLSKeyEnv, Value: keyFile},
+			)
+			// The kubelet doesn't verify certificates when probing over HTTPS
+			container.LivenessProbe.HTTPGet.Scheme = corev1.URISchemeHTTPS
+			container.ReadinessProbe.HTTPGet.Scheme = corev1.URISchemeHTTPS
+		}
+	}
+}
+
+// GetSecretChecksum returns the checksum of the data of a secret
+func GetSecretChecksum(secret *corev1.Secret) string {
+	keys := make([]string, 0, len(secret.Data))
+	for key := range secret.Data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	hash := sha256.New()
+	for _, key := range keys {
+		hash.Write([]byte(key))
+		hash.Write([]byte{0})
+		hash.Write(secret.Data[key])
+		hash.Write([]byte{0})
+	}
+	return fmt.Sprintf("%x", hash.Sum(nil))
+}
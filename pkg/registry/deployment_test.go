@@ -0,0 +1,76 @@
+//
+// Copyright (c) 2020 Red Hat, Inc.
+// This program and the accompanying materials are made
+// available under the terms of the Eclipse Public License 2.0
+// which is available at https://www.eclipse.org/legal/epl-2.0/
+//
+// SPDX-License-Identifier: EPL-2.0
+//
+// Contributors:
+//   Red Hat, Inc. - initial API and implementation
+
+package registry
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	registryv1beta1 "github.com/devfile/registry-operator/api/v1beta1"
+)
+
+func TestGenerateDeploymentTLSChecksum(t *testing.T) {
+	secret := &corev1.Secret{Data: map[string][]byte{
+		corev1.TLSCertKey:       []byte("cert"),
+		corev1.TLSPrivateKeyKey: []byte("key"),
+	}}
+	renewed := &corev1.Secret{Data: map[string][]byte{
+		corev1.TLSCertKey:       []byte("renewed cert"),
+		corev1.TLSPrivateKeyKey: []byte("renewed key"),
+	}}
+	inPod := registryv1beta1.DevfileRegistryTLS{SelfSigned: true, InPod: true}
+
+	tests := []struct {
+		name         string
+		tls          registryv1beta1.DevfileRegistryTLS
+		secret       *corev1.Secret
+		wantChecksum string
+	}{
+		{
+			name:         "Case 1: TLS terminated outside the pod",
+			secret:       secret,
+			wantChecksum: "",
+		},
+		{
+			name:         "Case 2: Serving secret not created yet",
+			tls:          inPod,
+			wantChecksum: "",
+		},
+		{
+			name:         "Case 3: Checksum of the serving secret",
+			tls:          inPod,
+			secret:       secret,
+			wantChecksum: GetSecretChecksum(secret),
+		},
+		{
+			name:         "Case 4: Checksum of the renewed serving secret",
+			tls:          inPod,
+			secret:       renewed,
+			wantChecksum: GetSecretChecksum(renewed),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cr := &registryv1beta1.DevfileRegistry{Spec: registryv1beta1.DevfileRegistrySpec{TLS: tt.tls}}
+			cr.Name = "test-registry"
+			dep := GenerateDeployment(cr, tt.secret, runtime.NewScheme(), LabelsForDevfileRegistry(cr.Name))
+			if got := dep.Spec.Template.Annotations[TLSSecretChecksumAnnotation]; got != tt.wantChecksum {
+				t.Errorf("TestGenerateDeploymentTLSChecksum error: checksum mismatch, expected: %v got: %v", tt.wantChecksum, got)
+			}
+		})
+	}
+	if GetSecretChecksum(secret) == GetSecretChecksum(renewed) {
+		t.Errorf("TestGenerateDeploymentTLSChecksum error: renewing the certificate didn't change the checksum")
+	}
+}
@@ -0,0 +1,140 @@
+//
+// Copyright (c) 2020 Red Hat, Inc.
+// This program and the accompanying materials are made
+// available under the terms of the Eclipse Public License 2.0
+// which is available at https://www.eclipse.org/legal/epl-2.0/
+//
+// SPDX-License-Identifier: EPL-2.0
+//
+// Contributors:
+//   Red Hat, Inc. - initial API and implementation
+
+package registry
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	registryv1beta1 "github.com/devfile/registry-operator/api/v1beta1"
+	"github.com/devfile/registry-operator/pkg/archive"
+)
+
+const (
+	// ArchiveVolumeName and ArchiveMountPath are the volume holding the archive file in the archive job
+	ArchiveVolumeName = "archive"
+	ArchiveMountPath  = "/archive"
+	// ArchiveCAVolumeName and ArchiveCAMountPath are the volume holding the CA bundle of a registry with self-signed
+	// certificates in the archive job
+	ArchiveCAVolumeName = "ca-bundle"
+	ArchiveCAMountPath  = "/etc/devfile-registry/ca"
+
+	// archiveJobBackoffLimit is the number of times a failed archive job is retried
+	archiveJobBackoffLimit = 2
+)
+
+// GetExportMode returns the direction the DevfileRegistryExport copies the stacks in. If it's not set, it returns
+// Export.
+func GetExportMode(export *registryv1beta1.DevfileRegistryExport) registryv1beta1.DevfileRegistryExportMode {
+	if export.Spec.Mode != "" {
+		return export.Spec.Mode
+	}
+	return registryv1beta1.ExportModeExport
+}
+
+// GetArchiveFileName returns the name of the archive file of the DevfileRegistryExport. If it's not set, it returns
+// the name of the export with a .tar extension.
+func GetArchiveFileName(export *registryv1beta1.DevfileRegistryExport) string {
+	if export.Spec.Archive.FileName != "" {
+		return export.Spec.Archive.FileName
+	}
+	return export.Name + ".tar"
+}
+
+// GenerateArchiveJob returns the job copying the stacks of the DevfileRegistry into the archive of the
+// DevfileRegistryExport, or from the archive into the registry. The job runs the archive subcommand of the operator
+// image.
+func GenerateArchiveJob(export *registryv1beta1.DevfileRegistryExport, cr *registryv1beta1.DevfileRegistry, image string, scheme *runtime.Scheme) *batchv1.Job {
+	// The pod mustn't match the selector of the registry's service
+	labels := map[string]string{"app": "devfileregistry-archive", "devfileregistry_cr": cr.Name}
+	fileName := GetArchiveFileName(export)
+	args := []string{
+		strings.ToLower(string(GetExportMode(export))),
+		"--registry-url=" + fmt.Sprintf("%s://%s:%d", GetServiceScheme(cr), GetServiceHostname(cr), OCIRegistryPort),
+		"--archive=" + path.Join(ArchiveMountPath, fileName),
+	}
+
+	archiveVolume := corev1.Volume{Name: ArchiveVolumeName}
+	var env []corev1.EnvVar
+	if pvc := export.Spec.Archive.PersistentVolumeClaim; pvc != nil {
+		archiveVolume.VolumeSource = corev1.VolumeSource{
+			PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: pvc.ClaimName},
+		}
+	} else if s3 := export.Spec.Archive.S3; s3 != nil {
+		// The archive is only staged in the pod on its way to or from the bucket
+		archiveVolume.VolumeSource = corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}
+		region := s3.Region
+		if region == "" {
+			region = DefaultS3Region
+		}
+		args = append(args,
+			"--s3-bucket="+s3.Bucket,
+			"--s3-region="+region,
+			"--s3-key="+strings.TrimPrefix(path.Join(s3.RootDirectory, fileName), "/"))
+		if s3.Endpoint != "" {
+			args = append(args, "--s3-endpoint="+s3.Endpoint)
+		}
+		env = []corev1.EnvVar{
+			secretKeyEnv(archive.AccessKeyIDEnv, s3.CredentialsSecret, S3AccessKeyIDKey),
+			secretKeyEnv(archive.SecretAccessKeyEnv, s3.CredentialsSecret, S3SecretAccessKeyKey),
+		}
+	}
+	volumes := []corev1.Volume{archiveVolume}
+	volumeMounts := []corev1.VolumeMount{{Name: ArchiveVolumeName, MountPath: ArchiveMountPath}}
+
+	// Registries with certificates from other sources are verified against the system CAs
+	if IsInPodTLSEnabled(cr) && IsSelfSigned(cr) {
+		args = append(args, "--ca-file="+path.Join(ArchiveCAMountPath, CABundleKey))
+		volumes = append(volumes, corev1.Volume{
+			Name: ArchiveCAVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{LocalObjectReference: corev1.LocalObjectReference{Name: CABundleName(cr.Name)}},
+			},
+		})
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{Name: ArchiveCAVolumeName, MountPath: ArchiveCAMountPath, ReadOnly: true})
+	}
+
+	backoffLimit := int32(archiveJobBackoffLimit)
+	job := &batchv1.Job{
+		ObjectMeta: generateObjectMeta(ArchiveJobName(export.Name), export.Namespace, labels),
+		Spec: batchv1.JobSpec{
+			BackoffLimit: &backoffLimit,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: generateObjectMeta("", "", labels),
+				Spec: corev1.PodSpec{
+					RestartPolicy: corev1.RestartPolicyNever,
+					Containers: []corev1.Container{
+						{
+							Name:         "archive",
+							Image:        image,
+							Command:      []string{"/manager", archive.Command},
+							Args:         args,
+							Env:          env,
+							VolumeMounts: volumeMounts,
+						},
+					},
+					Volumes: volumes,
+				},
+			},
+		},
+	}
+
+	// Set DevfileRegistryExport instance as the owner and controller
+	ctrl.SetControllerReference(export, job, scheme)
+	return job
+}
@@ -0,0 +1,106 @@
+//
+// Copyright (c) 2020 Red Hat, Inc.
+// This program and the accompanying materials are made
+// available under the terms of the Eclipse Public License 2.0
+// which is available at https://www.eclipse.org/legal/epl-2.0/
+//
+// SPDX-License-Identifier: EPL-2.0
+//
+// Contributors:
+//   Red Hat, Inc. - initial API and implementation
+
+package registry
+
+import (
+	"reflect"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	registryv1beta1 "github.com/devfile/registry-operator/api/v1beta1"
+)
+
+func TestGenerateArchiveJob(t *testing.T) {
+	tests := []struct {
+		name     string
+		tls      registryv1beta1.DevfileRegistryTLS
+		spec     registryv1beta1.DevfileRegistryExportSpec
+		wantArgs []string
+		wantEnv  int
+	}{
+		{
+			name: "Case 1: Export to a persistent volume claim",
+			spec: registryv1beta1.DevfileRegistryExportSpec{
+				Archive: registryv1beta1.DevfileRegistryArchive{
+					PersistentVolumeClaim: &registryv1beta1.DevfileRegistryArchivePVC{ClaimName: "archives"},
+				},
+			},
+			wantArgs: []string{
+				"export",
+				"--registry-url=http://devfileregistry-sample.default.svc.cluster.local:5000",
+				"--archive=/archive/stacks-export.tar",
+			},
+		},
+		{
+			name: "Case 2: Import from an S3 bucket",
+			spec: registryv1beta1.DevfileRegistryExportSpec{
+				Mode: registryv1beta1.ExportModeImport,
+				Archive: registryv1beta1.DevfileRegistryArchive{
+					FileName: "stacks.tar",
+					S3: &registryv1beta1.DevfileRegistryS3Storage{
+						Bucket:            "devfile-archives",
+						RootDirectory:     "/backups",
+						Endpoint:          "http://minio:9000",
+						CredentialsSecret: "s3-credentials",
+					},
+				},
+			},
+			wantArgs: []string{
+				"import",
+				"--registry-url=http://devfileregistry-sample.default.svc.cluster.local:5000",
+				"--archive=/archive/stacks.tar",
+				"--s3-bucket=devfile-archives",
+				"--s3-region=us-east-1",
+				"--s3-key=backups/stacks.tar",
+				"--s3-endpoint=http://minio:9000",
+			},
+			wantEnv: 2,
+		},
+		{
+			name: "Case 3: Registry serving a self-signed certificate",
+			tls:  registryv1beta1.DevfileRegistryTLS{InPod: true, SelfSigned: true},
+			spec: registryv1beta1.DevfileRegistryExportSpec{
+				Archive: registryv1beta1.DevfileRegistryArchive{
+					PersistentVolumeClaim: &registryv1beta1.DevfileRegistryArchivePVC{ClaimName: "archives"},
+				},
+			},
+			wantArgs: []string{
+				"export",
+				"--registry-url=https://devfileregistry-sample.default.svc.cluster.local:5000",
+				"--archive=/archive/stacks-export.tar",
+				"--ca-file=/etc/devfile-registry/ca/ca.crt",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cr := &registryv1beta1.DevfileRegistry{
+				ObjectMeta: metav1.ObjectMeta{Name: "devfileregistry-sample", Namespace: "default"},
+				Spec:       registryv1beta1.DevfileRegistrySpec{TLS: tt.tls},
+			}
+			export := &registryv1beta1.DevfileRegistryExport{
+				ObjectMeta: metav1.ObjectMeta{Name: "stacks-export", Namespace: "default"},
+				Spec:       tt.spec,
+			}
+			job := GenerateArchiveJob(export, cr, "quay.io/devfile/registry-operator:next", runtime.NewScheme())
+			container := job.Spec.Template.Spec.Containers[0]
+			if !reflect.DeepEqual(container.Args, tt.wantArgs) {
+				t.Errorf("TestGenerateArchiveJob error: args mismatch, expected: %v got: %v", tt.wantArgs, container.Args)
+			}
+			if len(container.Env) != tt.wantEnv {
+				t.Errorf("TestGenerateArchiveJob error: env mismatch, expected: %v variables got: %v", tt.wantEnv, container.Env)
+			}
+		})
+	}
+}
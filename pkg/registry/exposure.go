@@ -0,0 +1,124 @@
+//
+// Copyright (c) 2020 Red Hat, Inc.
+// This program and the accompanying materials are made
+// available under the terms of the Eclipse Public License 2.0
+// which is available at https://www.eclipse.org/legal/epl-2.0/
+//
+// SPDX-License-Identifier: EPL-2.0
+//
+// Contributors:
+//   Red Hat, Inc. - initial API and implementation
+
+package registry
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+
+	registryv1beta1 "github.com/devfile/registry-operator/api/v1beta1"
+	"github.com/devfile/registry-operator/pkg/config"
+)
+
+// GetExposureType returns how the DevfileRegistry is exposed. If the CR doesn't set it, it's detected from the
+// cluster: Routes on OpenShift, an HTTPRoute when a Gateway is referenced and the Gateway API is served, and an
+// Ingress otherwise.
+func GetExposureType(cr *registryv1beta1.DevfileRegistry) registryv1beta1.DevfileRegistryExposureType {
+	if cr.Spec.Exposure.Type != "" {
+		return cr.Spec.Exposure.Type
+	}
+	if config.ControllerCfg.IsOpenShift() {
+		return registryv1beta1.ExposureTypeRoute
+	}
+	if cr.Spec.Exposure.Gateway.Name != "" && config.ControllerCfg.GatewayAPIVersion() != "" {
+		return registryv1beta1.ExposureTypeGateway
+	}
+	return registryv1beta1.ExposureTypeIngress
+}
+
+// GetServiceType returns the type of the Service in front of the DevfileRegistry. It's only reachable from outside the
+// cluster for the NodePort and LoadBalancer exposure types, every other type exposes a ClusterIP Service.
+func GetServiceType(cr *registryv1beta1.DevfileRegistry) corev1.ServiceType {
+	switch GetExposureType(cr) {
+	case registryv1beta1.ExposureTypeNodePort:
+		return corev1.ServiceTypeNodePort
+	case registryv1beta1.ExposureTypeLoadBalancer:
+		return corev1.ServiceTypeLoadBalancer
+	default:
+		return corev1.ServiceTypeClusterIP
+	}
+}
+
+// GetHostURL returns the URL of a DevfileRegistry exposed under hostname by a Route, Ingress or HTTPRoute, which
+// terminate TLS when it's enabled
+func GetHostURL(cr *registryv1beta1.DevfileRegistry, hostname string) string {
+	if IsTLSEnabled(cr) {
+		return "https://" + hostname
+	}
+	return "http://" + hostname
+}
+
+// GetServiceScheme returns the scheme the registry containers are served with
+func GetServiceScheme(cr *registryv1beta1.DevfileRegistry) string {
+	if IsInPodTLSEnabled(cr) {
+		return "https"
+	}
+	return "http"
+}
+
+// GetServiceHostname returns the fully qualified in-cluster hostname of the DevfileRegistry's Service
+func GetServiceHostname(cr *registryv1beta1.DevfileRegistry) string {
+	return ServiceName(cr.Name) + "." + cr.Namespace + ".svc.cluster.local"
+}
+
+// GetServiceURL returns the in-cluster URL of the DevfileRegistry's devfile index, served by its Service
+func GetServiceURL(cr *registryv1beta1.DevfileRegistry) string {
+	return fmt.Sprintf("%s://%s:%d", GetServiceScheme(cr), GetServiceHostname(cr), DevfileIndexPort)
+}
+
+// GetLoadBalancerURL returns the URL of the devfile index on the load balancer provisioned for svc, or an empty
+// string while the load balancer isn't provisioned yet
+func GetLoadBalancerURL(cr *registryv1beta1.DevfileRegistry, svc *corev1.Service) string {
+	for _, ingress := range svc.Status.LoadBalancer.Ingress {
+		host := ingress.Hostname
+		if host == "" {
+			host = ingress.IP
+		}
+		if host != "" {
+			return GetServiceScheme(cr) + "://" + net.JoinHostPort(host, strconv.Itoa(DevfileIndexPort))
+		}
+	}
+	return ""
+}
+
+// GetNodePortURL returns the URL of the devfile index on the node port allocated to svc, on the first of the nodes
+// that has an address. External addresses are preferred over internal ones. An empty string is returned while no
+// node port is allocated or no node has an address. Nodes are picked by name, so the URL stays the same between
+// reconciles.
+func GetNodePortURL(cr *registryv1beta1.DevfileRegistry, svc *corev1.Service, nodes []corev1.Node) string {
+	var nodePort int32
+	for _, port := range svc.Spec.Ports {
+		if port.Name == DevfileIndexPortName {
+			nodePort = port.NodePort
+		}
+	}
+	if nodePort == 0 {
+		return ""
+	}
+
+	sorted := append([]corev1.Node(nil), nodes...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Name < sorted[j].Name })
+	for _, addressType := range []corev1.NodeAddressType{corev1.NodeExternalIP, corev1.NodeInternalIP} {
+		for _, node := range sorted {
+			for _, address := range node.Status.Addresses {
+				if address.Type == addressType && address.Address != "" {
+					return GetServiceScheme(cr) + "://" + net.JoinHostPort(address.Address, strconv.Itoa(int(nodePort)))
+				}
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,173 @@
+//
+// Copyright (c) 2020 Red Hat, Inc.
+// This program and the accompanying materials are made
+// available under the terms of the Eclipse Public License 2.0
+// which is available at https://www.eclipse.org/legal/epl-2.0/
+//
+// SPDX-License-Identifier: EPL-2.0
+//
+// Contributors:
+//   Red Hat, Inc. - initial API and implementation
+
+package registry
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	registryv1beta1 "github.com/devfile/registry-operator/api/v1beta1"
+	"github.com/devfile/registry-operator/pkg/config"
+)
+
+func TestGetExposureType(t *testing.T) {
+	tests := []struct {
+		name              string
+		isOpenShift       bool
+		gatewayAPIVersion string
+		exposure          registryv1beta1.DevfileRegistryExposure
+		want              registryv1beta1.DevfileRegistryExposureType
+	}{
+		{
+			name:     "Case 1: Type set explicitly",
+			exposure: registryv1beta1.DevfileRegistryExposure{Type: registryv1beta1.ExposureTypeRoute},
+			want:     registryv1beta1.ExposureTypeRoute,
+		},
+		{
+			name:        "Case 2: Detected on OpenShift",
+			isOpenShift: true,
+			want:        registryv1beta1.ExposureTypeRoute,
+		},
+		{
+			name:              "Case 3: Gateway referenced on a cluster serving the Gateway API",
+			gatewayAPIVersion: "v1",
+			exposure: registryv1beta1.DevfileRegistryExposure{
+				Gateway: registryv1beta1.DevfileRegistryGateway{Name: "gateway"},
+			},
+			want: registryv1beta1.ExposureTypeGateway,
+		},
+		{
+			name: "Case 4: Gateway referenced on a cluster without the Gateway API",
+			exposure: registryv1beta1.DevfileRegistryExposure{
+				Gateway: registryv1beta1.DevfileRegistryGateway{Name: "gateway"},
+			},
+			want: registryv1beta1.ExposureTypeIngress,
+		},
+		{
+			name:              "Case 5: No gateway referenced",
+			gatewayAPIVersion: "v1",
+			want:              registryv1beta1.ExposureTypeIngress,
+		},
+		{
+			name:        "Case 6: In-cluster only on OpenShift",
+			isOpenShift: true,
+			exposure:    registryv1beta1.DevfileRegistryExposure{Type: registryv1beta1.ExposureTypeNone},
+			want:        registryv1beta1.ExposureTypeNone,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config.ControllerCfg.SetIsOpenShift(tt.isOpenShift)
+			config.ControllerCfg.SetGatewayAPIVersion(tt.gatewayAPIVersion)
+			defer config.ControllerCfg.SetIsOpenShift(false)
+			defer config.ControllerCfg.SetGatewayAPIVersion("")
+
+			exposureType := GetExposureType(&registryv1beta1.DevfileRegistry{Spec: registryv1beta1.DevfileRegistrySpec{Exposure: tt.exposure}})
+			if exposureType != tt.want {
+				t.Errorf("TestGetExposureType error: exposure type mismatch, expected: %v got: %v", tt.want, exposureType)
+			}
+		})
+	}
+}
+
+func TestGetNodePortURL(t *testing.T) {
+	svc := &corev1.Service{
+		Spec: corev1.ServiceSpec{
+			Ports: []corev1.ServicePort{
+				{Name: DevfileIndexPortName, Port: DevfileIndexPort, NodePort: 30080},
+				{Name: OCIRegistryPortName, Port: OCIRegistryPort, NodePort: 30500},
+			},
+		},
+	}
+	node := func(name string, addresses ...corev1.NodeAddress) corev1.Node {
+		return corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name}, Status: corev1.NodeStatus{Addresses: addresses}}
+	}
+
+	tests := []struct {
+		name  string
+		svc   *corev1.Service
+		nodes []corev1.Node
+		want  string
+	}{
+		{
+			name: "Case 1: External address preferred",
+			svc:  svc,
+			nodes: []corev1.Node{
+				node("node-a", corev1.NodeAddress{Type: corev1.NodeInternalIP, Address: "10.0.0.1"}),
+				node("node-b", corev1.NodeAddress{Type: corev1.NodeExternalIP, Address: "192.0.2.2"}),
+			},
+			want: "http://192.0.2.2:30080",
+		},
+		{
+			name: "Case 2: Internal addresses picked by node name",
+			svc:  svc,
+			nodes: []corev1.Node{
+				node("node-b", corev1.NodeAddress{Type: corev1.NodeInternalIP, Address: "10.0.0.2"}),
+				node("node-a", corev1.NodeAddress{Type: corev1.NodeInternalIP, Address: "10.0.0.1"}),
+			},
+			want: "http://10.0.0.1:30080",
+		},
+		{
+			name:  "Case 3: Node port not allocated yet",
+			svc:   &corev1.Service{Spec: corev1.ServiceSpec{Ports: []corev1.ServicePort{{Name: DevfileIndexPortName, Port: DevfileIndexPort}}}},
+			nodes: []corev1.Node{node("node-a", corev1.NodeAddress{Type: corev1.NodeInternalIP, Address: "10.0.0.1"})},
+			want:  "",
+		},
+		{
+			name: "Case 4: No node address",
+			svc:  svc,
+			want: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			url := GetNodePortURL(&registryv1beta1.DevfileRegistry{}, tt.svc, tt.nodes)
+			if url != tt.want {
+				t.Errorf("TestGetNodePortURL error: url mismatch, expected: %v got: %v", tt.want, url)
+			}
+		})
+	}
+}
+
+func TestGetLoadBalancerURL(t *testing.T) {
+	tests := []struct {
+		name    string
+		ingress []corev1.LoadBalancerIngress
+		want    string
+	}{
+		{
+			name:    "Case 1: Load balancer with a hostname",
+			ingress: []corev1.LoadBalancerIngress{{Hostname: "lb.example.com", IP: "192.0.2.1"}},
+			want:    "http://lb.example.com:8080",
+		},
+		{
+			name:    "Case 2: Load balancer with an IPv6 address",
+			ingress: []corev1.LoadBalancerIngress{{IP: "2001:db8::1"}},
+			want:    "http://[2001:db8::1]:8080",
+		},
+		{
+			name: "Case 3: Load balancer not provisioned yet",
+			want: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &corev1.Service{Status: corev1.ServiceStatus{LoadBalancer: corev1.LoadBalancerStatus{Ingress: tt.ingress}}}
+			url := GetLoadBalancerURL(&registryv1beta1.DevfileRegistry{}, svc)
+			if url != tt.want {
+				t.Errorf("TestGetLoadBalancerURL error: url mismatch, expected: %v got: %v", tt.want, url)
+			}
+		})
+	}
+}
@@ -0,0 +1,231 @@
+//
+// Copyright (c) 2020 Red Hat, Inc.
+// This program and the accompanying materials are made
+// available under the terms of the Eclipse Public License 2.0
+// which is available at https://www.eclipse.org/legal/epl-2.0/
+//
+// SPDX-License-Identifier: EPL-2.0
+//
+// Contributors:
+//   Red Hat, Inc. - initial API and implementation
+
+package registry
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	registryv1beta1 "github.com/devfile/registry-operator/api/v1beta1"
+)
+
+const (
+	// GarbageCollectionReadyKey is set to true in the OCI registry config map once every registry pod serves in
+	// read-only mode. The garbage collection job waits for it before collecting.
+	GarbageCollectionReadyKey = "gc-ready"
+
+	// gcJobDeadlineSeconds bounds how long the registry stays read-only for a garbage collection
+	gcJobDeadlineSeconds = 3600
+	// gcPollSeconds is how often the garbage collection job checks whether the registry is read-only yet
+	gcPollSeconds = 5
+)
+
+// garbageCollectionResult is the termination message of the garbage collection job
+type garbageCollectionResult struct {
+	FreedBytes int64 `json:"freedBytes"`
+}
+
+// LabelsForGarbageCollection returns the labels of the garbage collection jobs of the DevfileRegistry. They don't
+// match the selector of the registry's service.
+func LabelsForGarbageCollection(name string) map[string]string {
+	return map[string]string{"app": "devfileregistry-gc", "devfileregistry_cr": name}
+}
+
+// IsGarbageCollectionEnabled returns true if the garbage of the OCI registry is collected on a schedule
+func IsGarbageCollectionEnabled(cr *registryv1beta1.DevfileRegistry) bool {
+	return cr.Spec.OCIRegistry.GarbageCollection != nil
+}
+
+// IsReadOnly returns true while a garbage collection job is active, during which the OCI registry is in read-only mode
+func IsReadOnly(cr *registryv1beta1.DevfileRegistry) bool {
+	return cr.Status.GarbageCollection != nil && cr.Status.GarbageCollection.ActiveJob != ""
+}
+
+// IsReadOnlyRolledOut returns true once every pod of the deployment runs with the read-only configuration of the OCI
+// registry
+func IsReadOnlyRolledOut(cr *registryv1beta1.DevfileRegistry, dep *appsv1.Deployment) bool {
+	if !IsReadOnly(cr) || dep.Spec.Template.Annotations[OCIRegistryConfigChecksumAnnotation] != GetOCIRegistryConfigChecksum(cr) {
+		return false
+	}
+	replicas := int32(1)
+	if dep.Spec.Replicas != nil {
+		replicas = *dep.Spec.Replicas
+	}
+	status := dep.Status
+	return status.ObservedGeneration >= dep.Generation && status.Replicas == replicas && status.UpdatedReplicas == replicas && status.AvailableReplicas == replicas
+}
+
+// GetJobResult returns whether the job finished, whether it failed, and when it finished
+func GetJobResult(job *batchv1.Job) (bool, bool, metav1.Time) {
+	for _, condition := range job.Status.Conditions {
+		if condition.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch condition.Type {
+		case batchv1.JobComplete:
+			if job.Status.CompletionTime != nil {
+				return true, false, *job.Status.CompletionTime
+			}
+			return true, false, condition.LastTransitionTime
+		case batchv1.JobFailed:
+			return true, true, condition.LastTransitionTime
+		}
+	}
+	return false, false, metav1.Time{}
+}
+
+// GetFreedBytes returns the space the garbage collection freed, from the termination message of the pod that ran it.
+// It returns nil when the pod didn't report it, which is the case with S3 storage.
+func GetFreedBytes(pods []corev1.Pod) *int64 {
+	for _, pod := range pods {
+		for _, status := range pod.Status.ContainerStatuses {
+			terminated := status.State.Terminated
+			if terminated == nil || terminated.ExitCode != 0 || terminated.Message == "" {
+				continue
+			}
+			result := garbageCollectionResult{}
+			if err := json.Unmarshal([]byte(terminated.Message), &result); err != nil {
+				continue
+			}
+			return &result.FreedBytes
+		}
+	}
+	return nil
+}
+
+// generateGarbageCollectionScript returns the shell script run by the garbage collection job. It waits for the
+// registry to be read-only, then collects the garbage and reports the space freed on the persistent volume.
+func generateGarbageCollectionScript(cr *registryv1beta1.DevfileRegistry) string {
+	gcCommand := "registry garbage-collect"
+	if cr.Spec.OCIRegistry.GarbageCollection.DeleteUntagged {
+		gcCommand += " --delete-untagged"
+	}
+	gcCommand += " " + OCIRegistryConfigMountPath + "/" + OCIRegistryConfigKey
+
+	lines := []string{
+		"set -e",
+		fmt.Sprintf(`until [ "$(cat %s/%s 2>/dev/null)" = "true" ]; do sleep %d; done`, OCIRegistryConfigMountPath, GarbageCollectionReadyKey, gcPollSeconds),
+	}
+	if !IsPVCEnabled(cr) {
+		return strings.Join(append(lines, gcCommand), "\n")
+	}
+	used := fmt.Sprintf("du -sk %s | cut -f1", OCIRegistryStoragePath)
+	return strings.Join(append(lines,
+		"before=$("+used+")",
+		gcCommand,
+		"after=$("+used+")",
+		`echo "{\"freedBytes\": $(( (before - after) * 1024 ))}" > /dev/termination-log`,
+	), "\n")
+}
+
+// GenerateGarbageCollectionCronJob returns the cron job collecting the garbage of the OCI registry on the schedule of
+// the DevfileRegistry. Its jobs run the OCI registry image against the registry's storage and configuration.
+func GenerateGarbageCollectionCronJob(cr *registryv1beta1.DevfileRegistry, scheme *runtime.Scheme, labels map[string]string) *batchv1.CronJob {
+	jobLabels := LabelsForGarbageCollection(cr.Name)
+	backoffLimit := int32(0)
+	deadline := int64(gcJobDeadlineSeconds)
+	historyLimit := int32(1)
+
+	volumeMounts := []corev1.VolumeMount{{
+		Name:      OCIRegistryConfigVolumeName,
+		MountPath: OCIRegistryConfigMountPath,
+		ReadOnly:  true,
+	}}
+	volumes := []corev1.Volume{{
+		Name: OCIRegistryConfigVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			ConfigMap: &corev1.ConfigMapVolumeSource{
+				LocalObjectReference: corev1.LocalObjectReference{Name: OCIRegistryConfigName(cr.Name)},
+			},
+		},
+	}}
+	var affinity *corev1.Affinity
+	if IsPVCEnabled(cr) {
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{Name: DevfileRegistryVolumeName, MountPath: OCIRegistryStoragePath})
+		volumes = append(volumes, corev1.Volume{Name: DevfileRegistryVolumeName, VolumeSource: GetDevfileRegistryVolumeSource(cr)})
+		// The volume may only be attachable to the node the registry runs on
+		affinity = &corev1.Affinity{
+			PodAffinity: &corev1.PodAffinity{
+				RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{{
+					LabelSelector: &metav1.LabelSelector{MatchLabels: LabelsForDevfileRegistry(cr.Name)},
+					TopologyKey:   corev1.LabelHostname,
+				}},
+			},
+		}
+	}
+
+	cronJob := &batchv1.CronJob{
+		ObjectMeta: generateObjectMeta(GarbageCollectionCronJobName(cr.Name), cr.Namespace, labels),
+		Spec: batchv1.CronJobSpec{
+			Schedule:                   cr.Spec.OCIRegistry.GarbageCollection.Schedule,
+			ConcurrencyPolicy:          batchv1.ForbidConcurrent,
+			SuccessfulJobsHistoryLimit: &historyLimit,
+			FailedJobsHistoryLimit:     &historyLimit,
+			JobTemplate: batchv1.JobTemplateSpec{
+				ObjectMeta: generateObjectMeta("", "", jobLabels),
+				Spec: batchv1.JobSpec{
+					BackoffLimit:          &backoffLimit,
+					ActiveDeadlineSeconds: &deadline,
+					Template: corev1.PodTemplateSpec{
+						ObjectMeta: generateObjectMeta("", "", jobLabels),
+						Spec: corev1.PodSpec{
+							RestartPolicy: corev1.RestartPolicyNever,
+							Affinity:      affinity,
+							Containers: []corev1.Container{
+								{
+									Name:         "garbage-collect",
+									Image:        GetOCIRegistryImage(cr),
+									Command:      []string{"/bin/sh", "-c", generateGarbageCollectionScript(cr)},
+									Env:          GetOCIRegistryEnv(cr),
+									VolumeMounts: volumeMounts,
+								},
+							},
+							Volumes: volumes,
+						},
+					},
+				},
+			},
+		},
+	}
+
+	// Set DevfileRegistry instance as the owner and controller
+	ctrl.SetControllerReference(cr, cronJob, scheme)
+	return cronJob
+}
+
+// GenerateGarbageCollectionCronJobV1beta1 returns the same cron job as GenerateGarbageCollectionCronJob, through the
+// batch/v1beta1 API served by clusters that predate batch/v1 cron jobs
+func GenerateGarbageCollectionCronJobV1beta1(cr *registryv1beta1.DevfileRegistry, scheme *runtime.Scheme, labels map[string]string) *batchv1beta1.CronJob {
+	cronJob := GenerateGarbageCollectionCronJob(cr, scheme, labels)
+	return &batchv1beta1.CronJob{
+		ObjectMeta: cronJob.ObjectMeta,
+		Spec: batchv1beta1.CronJobSpec{
+			Schedule:                   cronJob.Spec.Schedule,
+			ConcurrencyPolicy:          batchv1beta1.ForbidConcurrent,
+			SuccessfulJobsHistoryLimit: cronJob.Spec.SuccessfulJobsHistoryLimit,
+			FailedJobsHistoryLimit:     cronJob.Spec.FailedJobsHistoryLimit,
+			JobTemplate: batchv1beta1.JobTemplateSpec{
+				ObjectMeta: cronJob.Spec.JobTemplate.ObjectMeta,
+				Spec:       cronJob.Spec.JobTemplate.Spec,
+			},
+		},
+	}
+}
@@ -0,0 +1,223 @@
+//
+// Copyright (c) 2020 Red Hat, Inc.
+// This program and the accompanying materials are made
+// available under the terms of the Eclipse Public License 2.0
+// which is available at https://www.eclipse.org/legal/epl-2.0/
+//
+// SPDX-License-Identifier: EPL-2.0
+//
+// Contributors:
+//   Red Hat, Inc. - initial API and implementation
+
+package registry
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	registryv1beta1 "github.com/devfile/registry-operator/api/v1beta1"
+)
+
+func TestIsReadOnlyRolledOut(t *testing.T) {
+	readOnly := &registryv1beta1.DevfileRegistry{
+		Status: registryv1beta1.DevfileRegistryStatus{
+			GarbageCollection: &registryv1beta1.DevfileRegistryGarbageCollectionStatus{ActiveJob: "devfileregistry-sample-gc-27049320"},
+		},
+	}
+	readOnlyChecksum := GetOCIRegistryConfigChecksum(readOnly)
+	replicas := int32(1)
+
+	tests := []struct {
+		name     string
+		cr       *registryv1beta1.DevfileRegistry
+		checksum string
+		status   appsv1.DeploymentStatus
+		want     bool
+	}{
+		{
+			name:     "Case 1: Rolled out in read-only mode",
+			cr:       readOnly,
+			checksum: readOnlyChecksum,
+			status:   appsv1.DeploymentStatus{ObservedGeneration: 2, Replicas: 1, UpdatedReplicas: 1, AvailableReplicas: 1},
+			want:     true,
+		},
+		{
+			name:     "Case 2: Old pod still running",
+			cr:       readOnly,
+			checksum: readOnlyChecksum,
+			status:   appsv1.DeploymentStatus{ObservedGeneration: 2, Replicas: 2, UpdatedReplicas: 1, AvailableReplicas: 1},
+			want:     false,
+		},
+		{
+			name:     "Case 3: Rollout not observed yet",
+			cr:       readOnly,
+			checksum: readOnlyChecksum,
+			status:   appsv1.DeploymentStatus{ObservedGeneration: 1, Replicas: 1, UpdatedReplicas: 1, AvailableReplicas: 1},
+			want:     false,
+		},
+		{
+			name:     "Case 4: Deployment not updated yet",
+			cr:       readOnly,
+			checksum: GetOCIRegistryConfigChecksum(&registryv1beta1.DevfileRegistry{}),
+			status:   appsv1.DeploymentStatus{ObservedGeneration: 2, Replicas: 1, UpdatedReplicas: 1, AvailableReplicas: 1},
+			want:     false,
+		},
+		{
+			name:     "Case 5: No active garbage collection",
+			cr:       &registryv1beta1.DevfileRegistry{},
+			checksum: GetOCIRegistryConfigChecksum(&registryv1beta1.DevfileRegistry{}),
+			status:   appsv1.DeploymentStatus{ObservedGeneration: 2, Replicas: 1, UpdatedReplicas: 1, AvailableReplicas: 1},
+			want:     false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dep := &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Generation: 2},
+				Spec: appsv1.DeploymentSpec{
+					Replicas: &replicas,
+					Template: corev1.PodTemplateSpec{
+						ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{OCIRegistryConfigChecksumAnnotation: tt.checksum}},
+					},
+				},
+				Status: tt.status,
+			}
+			if got := IsReadOnlyRolledOut(tt.cr, dep); got != tt.want {
+				t.Errorf("TestIsReadOnlyRolledOut error: expected: %v got: %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestGetFreedBytes(t *testing.T) {
+	terminated := func(exitCode int32, message string) corev1.Pod {
+		return corev1.Pod{
+			Status: corev1.PodStatus{
+				ContainerStatuses: []corev1.ContainerStatus{{
+					State: corev1.ContainerState{
+						Terminated: &corev1.ContainerStateTerminated{ExitCode: exitCode, Message: message},
+					},
+				}},
+			},
+		}
+	}
+	freed := int64(52428800)
+
+	tests := []struct {
+		name string
+		pods []corev1.Pod
+		want *int64
+	}{
+		{
+			name: "Case 1: Freed space reported",
+			pods: []corev1.Pod{terminated(0, `{"freedBytes": 52428800}`)},
+			want: &freed,
+		},
+		{
+			name: "Case 2: Nothing reported with S3 storage",
+			pods: []corev1.Pod{terminated(0, "")},
+			want: nil,
+		},
+		{
+			name: "Case 3: Failed pod",
+			pods: []corev1.Pod{terminated(1, "failed to read the configuration")},
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := GetFreedBytes(tt.pods)
+			if (got == nil) != (tt.want == nil) || (got != nil && *got != *tt.want) {
+				t.Errorf("TestGetFreedBytes error: expected: %v got: %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestGenerateGarbageCollectionCronJob(t *testing.T) {
+	tests := []struct {
+		name         string
+		gc           registryv1beta1.DevfileRegistryGarbageCollection
+		storage      registryv1beta1.DevfileRegistryStorage
+		wantCommand  string
+		wantAffinity bool
+		wantVolumes  int
+	}{
+		{
+			name:         "Case 1: Persistent volume claim",
+			gc:           registryv1beta1.DevfileRegistryGarbageCollection{Schedule: "0 3 * * 0"},
+			wantCommand:  "registry garbage-collect /etc/docker/registry/config.yml",
+			wantAffinity: true,
+			wantVolumes:  2,
+		},
+		{
+			name: "Case 2: S3 storage deleting untagged manifests",
+			gc:   registryv1beta1.DevfileRegistryGarbageCollection{Schedule: "0 3 * * 0", DeleteUntagged: true},
+			storage: registryv1beta1.DevfileRegistryStorage{
+				Type: registryv1beta1.StorageTypeS3,
+				S3:   registryv1beta1.DevfileRegistryS3Storage{Bucket: "devfiles", CredentialsSecret: "s3-credentials"},
+			},
+			wantCommand:  "registry garbage-collect --delete-untagged /etc/docker/registry/config.yml",
+			wantAffinity: false,
+			wantVolumes:  1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cr := &registryv1beta1.DevfileRegistry{
+				ObjectMeta: metav1.ObjectMeta{Name: "devfileregistry-sample", Namespace: "default"},
+				Spec: registryv1beta1.DevfileRegistrySpec{
+					OCIRegistry: registryv1beta1.DevfileRegistryOCIRegistry{GarbageCollection: &tt.gc},
+					Storage:     tt.storage,
+				},
+			}
+			cronJob := GenerateGarbageCollectionCronJob(cr, runtime.NewScheme(), LabelsForDevfileRegistry(cr.Name))
+			if cronJob.Spec.Schedule != tt.gc.Schedule {
+				t.Errorf("TestGenerateGarbageCollectionCronJob error: schedule mismatch, expected: %v got: %v", tt.gc.Schedule, cronJob.Spec.Schedule)
+			}
+			podSpec := cronJob.Spec.JobTemplate.Spec.Template.Spec
+			if script := podSpec.Containers[0].Command[2]; !strings.Contains(script, "\n"+tt.wantCommand+"\n") && !strings.HasSuffix(script, "\n"+tt.wantCommand) {
+				t.Errorf("TestGenerateGarbageCollectionCronJob error: command mismatch, expected: %v got: %v", tt.wantCommand, script)
+			}
+			if (podSpec.Affinity != nil) != tt.wantAffinity {
+				t.Errorf("TestGenerateGarbageCollectionCronJob error: affinity mismatch, expected: %v got: %v", tt.wantAffinity, podSpec.Affinity)
+			}
+			if len(podSpec.Volumes) != tt.wantVolumes {
+				t.Errorf("TestGenerateGarbageCollectionCronJob error: volumes mismatch, expected: %v got: %v", tt.wantVolumes, len(podSpec.Volumes))
+			}
+		})
+	}
+}
+
+func TestGenerateGarbageCollectionCronJobV1beta1(t *testing.T) {
+	cr := &registryv1beta1.DevfileRegistry{
+		ObjectMeta: metav1.ObjectMeta{Name: "devfileregistry-sample", Namespace: "default"},
+		Spec: registryv1beta1.DevfileRegistrySpec{
+			OCIRegistry: registryv1beta1.DevfileRegistryOCIRegistry{
+				GarbageCollection: &registryv1beta1.DevfileRegistryGarbageCollection{Schedule: "0 3 * * 0"},
+			},
+		},
+	}
+	labels := LabelsForDevfileRegistry(cr.Name)
+	want := GenerateGarbageCollectionCronJob(cr, runtime.NewScheme(), labels)
+	cronJob := GenerateGarbageCollectionCronJobV1beta1(cr, runtime.NewScheme(), labels)
+	if cronJob.Name != want.Name || !reflect.DeepEqual(cronJob.Labels, want.Labels) {
+		t.Errorf("TestGenerateGarbageCollectionCronJobV1beta1 error: metadata mismatch, expected: %v %v got: %v %v", want.Name, want.Labels, cronJob.Name, cronJob.Labels)
+	}
+	if cronJob.Spec.Schedule != want.Spec.Schedule {
+		t.Errorf("TestGenerateGarbageCollectionCronJobV1beta1 error: schedule mismatch, expected: %v got: %v", want.Spec.Schedule, cronJob.Spec.Schedule)
+	}
+	if cronJob.Spec.ConcurrencyPolicy != batchv1beta1.ForbidConcurrent {
+		t.Errorf("TestGenerateGarbageCollectionCronJobV1beta1 error: concurrency policy mismatch, expected: %v got: %v", batchv1beta1.ForbidConcurrent, cronJob.Spec.ConcurrencyPolicy)
+	}
+	if !reflect.DeepEqual(cronJob.Spec.JobTemplate.Spec, want.Spec.JobTemplate.Spec) {
+		t.Errorf("TestGenerateGarbageCollectionCronJobV1beta1 error: job template mismatch, expected: %+v got: %+v", want.Spec.JobTemplate.Spec, cronJob.Spec.JobTemplate.Spec)
+	}
+}
@@ -0,0 +1,86 @@
+//
+// Copyright (c) 2020 Red Hat, Inc.
+// This program and the accompanying materials are made
+// available under the terms of the Eclipse Public License 2.0
+// which is available at https://www.eclipse.org/legal/epl-2.0/
+//
+// SPDX-License-Identifier: EPL-2.0
+//
+// Contributors:
+//   Red Hat, Inc. - initial API and implementation
+
+package registry
+
+import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	registryv1beta1 "github.com/devfile/registry-operator/api/v1beta1"
+	"github.com/devfile/registry-operator/pkg/config"
+)
+
+// HTTPRouteGVK returns the kind of the HTTPRoutes exposing the registry, in the version of the Gateway API served by
+// the cluster. The Gateway API isn't part of the Kubernetes client libraries, so HTTPRoutes are handled as
+// unstructured objects.
+func HTTPRouteGVK() schema.GroupVersionKind {
+	return schema.GroupVersionKind{Group: "gateway.networking.k8s.io", Version: config.ControllerCfg.GatewayAPIVersion(), Kind: "HTTPRoute"}
+}
+
+// GenerateHTTPRoute returns a Gateway API HTTPRoute attaching the devfile index and the OCI registry to the Gateway
+// referenced by the DevfileRegistry
+func GenerateHTTPRoute(cr *registryv1beta1.DevfileRegistry, scheme *runtime.Scheme, labels map[string]string) *unstructured.Unstructured {
+	gateway := cr.Spec.Exposure.Gateway
+	parentRef := map[string]interface{}{
+		"name": gateway.Name,
+	}
+	if gateway.Namespace != "" {
+		parentRef["namespace"] = gateway.Namespace
+	}
+	if gateway.SectionName != "" {
+		parentRef["sectionName"] = gateway.SectionName
+	}
+
+	spec := map[string]interface{}{
+		"parentRefs": []interface{}{parentRef},
+		"rules": []interface{}{
+			httpRouteRule("/", ServiceName(cr.Name), DevfileIndexPort),
+			httpRouteRule("/v2", ServiceName(cr.Name), OCIRegistryPort),
+		},
+	}
+	if gateway.Hostname != "" {
+		spec["hostnames"] = []interface{}{gateway.Hostname}
+	}
+
+	route := &unstructured.Unstructured{Object: map[string]interface{}{"spec": spec}}
+	route.SetGroupVersionKind(HTTPRouteGVK())
+	route.SetName(HTTPRouteName(cr.Name))
+	route.SetNamespace(cr.Namespace)
+	route.SetLabels(labels)
+
+	// Set DevfileRegistry instance as the owner and controller
+	ctrl.SetControllerReference(cr, route, scheme)
+	return route
+}
+
+// httpRouteRule returns an HTTPRoute rule sending the requests under path to a port of a service. The Gateway picks
+// the rule with the longest matching prefix, so /v2 takes precedence over /.
+func httpRouteRule(path string, service string, port int64) map[string]interface{} {
+	return map[string]interface{}{
+		"matches": []interface{}{
+			map[string]interface{}{
+				"path": map[string]interface{}{
+					"type":  "PathPrefix",
+					"value": path,
+				},
+			},
+		},
+		"backendRefs": []interface{}{
+			map[string]interface{}{
+				"name": service,
+				"port": port,
+			},
+		},
+	}
+}
@@ -0,0 +1,110 @@
+//
+// Copyright (c) 2020 Red Hat, Inc.
+// This program and the accompanying materials are made
+// available under the terms of the Eclipse Public License 2.0
+// which is available at https://www.eclipse.org/legal/epl-2.0/
+//
+// SPDX-License-Identifier: EPL-2.0
+//
+// Contributors:
+//   Red Hat, Inc. - initial API and implementation
+
+package registry
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	registryv1beta1 "github.com/devfile/registry-operator/api/v1beta1"
+	"github.com/devfile/registry-operator/pkg/config"
+)
+
+func TestGenerateHTTPRoute(t *testing.T) {
+	tests := []struct {
+		name          string
+		gateway       registryv1beta1.DevfileRegistryGateway
+		wantParentRef map[string]interface{}
+		wantHostnames []interface{}
+	}{
+		{
+			name:          "Case 1: Gateway in the registry's namespace",
+			gateway:       registryv1beta1.DevfileRegistryGateway{Name: "gateway", Hostname: "registry.example.com"},
+			wantParentRef: map[string]interface{}{"name": "gateway"},
+			wantHostnames: []interface{}{"registry.example.com"},
+		},
+		{
+			name: "Case 2: Listener of a Gateway in another namespace",
+			gateway: registryv1beta1.DevfileRegistryGateway{
+				Name:        "gateway",
+				Namespace:   "gateway-system",
+				SectionName: "https",
+				Hostname:    "registry.example.com",
+			},
+			wantParentRef: map[string]interface{}{"name": "gateway", "namespace": "gateway-system", "sectionName": "https"},
+			wantHostnames: []interface{}{"registry.example.com"},
+		},
+		{
+			name:          "Case 3: Hostnames left to the Gateway",
+			gateway:       registryv1beta1.DevfileRegistryGateway{Name: "gateway"},
+			wantParentRef: map[string]interface{}{"name": "gateway"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config.ControllerCfg.SetGatewayAPIVersion("v1")
+			defer config.ControllerCfg.SetGatewayAPIVersion("")
+
+			cr := &registryv1beta1.DevfileRegistry{Spec: registryv1beta1.DevfileRegistrySpec{
+				Exposure: registryv1beta1.DevfileRegistryExposure{
+					Type:    registryv1beta1.ExposureTypeGateway,
+					Gateway: tt.gateway,
+				},
+			}}
+			cr.Name = "test-registry"
+			cr.Namespace = "test-namespace"
+
+			route := GenerateHTTPRoute(cr, runtime.NewScheme(), LabelsForDevfileRegistry(cr.Name))
+			if route.GetAPIVersion() != "gateway.networking.k8s.io/v1" || route.GetKind() != "HTTPRoute" {
+				t.Errorf("TestGenerateHTTPRoute error: kind mismatch, expected: gateway.networking.k8s.io/v1 HTTPRoute got: %v %v", route.GetAPIVersion(), route.GetKind())
+			}
+			if route.GetName() != HTTPRouteName(cr.Name) || route.GetNamespace() != cr.Namespace {
+				t.Errorf("TestGenerateHTTPRoute error: name mismatch, expected: %v/%v got: %v/%v", cr.Namespace, HTTPRouteName(cr.Name), route.GetNamespace(), route.GetName())
+			}
+
+			parentRefs, _, _ := unstructured.NestedSlice(route.Object, "spec", "parentRefs")
+			if wantParentRefs := []interface{}{tt.wantParentRef}; !reflect.DeepEqual(parentRefs, wantParentRefs) {
+				t.Errorf("TestGenerateHTTPRoute error: parentRefs mismatch, expected: %v got: %v", wantParentRefs, parentRefs)
+			}
+			hostnames, _, _ := unstructured.NestedSlice(route.Object, "spec", "hostnames")
+			if !reflect.DeepEqual(hostnames, tt.wantHostnames) {
+				t.Errorf("TestGenerateHTTPRoute error: hostnames mismatch, expected: %v got: %v", tt.wantHostnames, hostnames)
+			}
+
+			rules, _, _ := unstructured.NestedSlice(route.Object, "spec", "rules")
+			wantBackends := map[string]int64{"/": DevfileIndexPort, "/v2": OCIRegistryPort}
+			if len(rules) != len(wantBackends) {
+				t.Fatalf("TestGenerateHTTPRoute error: rules mismatch, expected: %v rules got: %v", len(wantBackends), rules)
+			}
+			for _, r := range rules {
+				rule := r.(map[string]interface{})
+				matches, _, _ := unstructured.NestedSlice(rule, "matches")
+				if len(matches) != 1 {
+					t.Fatalf("TestGenerateHTTPRoute error: expected one match per rule, got: %v", matches)
+				}
+				pathType, _, _ := unstructured.NestedString(matches[0].(map[string]interface{}), "path", "type")
+				path, _, _ := unstructured.NestedString(matches[0].(map[string]interface{}), "path", "value")
+				if pathType != "PathPrefix" {
+					t.Errorf("TestGenerateHTTPRoute error: path type mismatch for %v, expected: PathPrefix got: %v", path, pathType)
+				}
+				backendRefs, _, _ := unstructured.NestedSlice(rule, "backendRefs")
+				wantBackendRefs := []interface{}{map[string]interface{}{"name": ServiceName(cr.Name), "port": wantBackends[path]}}
+				if !reflect.DeepEqual(backendRefs, wantBackendRefs) {
+					t.Errorf("TestGenerateHTTPRoute error: backendRefs mismatch for %v, expected: %v got: %v", path, wantBackendRefs, backendRefs)
+				}
+			}
+		})
+	}
+}
@@ -12,33 +12,108 @@
 package registry
 
 import (
-	registryv1alpha1 "github.com/devfile/registry-operator/api/v1alpha1"
-	"k8s.io/api/extensions/v1beta1"
+	networkingv1 "k8s.io/api/networking/v1"
+	networkingv1beta1 "k8s.io/api/networking/v1beta1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	ctrl "sigs.k8s.io/controller-runtime"
+
+	registryv1beta1 "github.com/devfile/registry-operator/api/v1beta1"
 )
 
-func GenerateIngress(cr *registryv1alpha1.DevfileRegistry, host string, scheme *runtime.Scheme, labels map[string]string) *v1beta1.Ingress {
-	ingress := &v1beta1.Ingress{
+// IngressClassAnnotation selects the ingress controller of ingresses served through the networking.k8s.io/v1beta1 API,
+// on clusters that predate the ingressClassName field
+const IngressClassAnnotation = "kubernetes.io/ingress.class"
+
+// IngressBackendProtocolAnnotation tells the NGINX ingress controller to talk TLS to registries serving it themselves
+const IngressBackendProtocolAnnotation = "nginx.ingress.kubernetes.io/backend-protocol"
+
+// GenerateIngress returns a networking.k8s.io/v1 ingress exposing the devfile index and the OCI registry
+func GenerateIngress(cr *registryv1beta1.DevfileRegistry, host string, scheme *runtime.Scheme, labels map[string]string) *networkingv1.Ingress {
+	pathType := networkingv1.PathTypePrefix
+	ingress := &networkingv1.Ingress{
+		ObjectMeta: generateObjectMeta(IngressName(cr.Name), cr.Namespace, labels),
+		Spec: networkingv1.IngressSpec{
+			Rules: []networkingv1.IngressRule{
+				{
+					Host: host,
+					IngressRuleValue: networkingv1.IngressRuleValue{
+						HTTP: &networkingv1.HTTPIngressRuleValue{
+							Paths: []networkingv1.HTTPIngressPath{
+								{
+									Path:     "/",
+									PathType: &pathType,
+									Backend: networkingv1.IngressBackend{
+										Service: &networkingv1.IngressServiceBackend{
+											Name: ServiceName(cr.Name),
+											Port: networkingv1.ServiceBackendPort{Number: DevfileIndexPort},
+										},
+									},
+								},
+								{
+									Path:     "/v2",
+									PathType: &pathType,
+									Backend: networkingv1.IngressBackend{
+										Service: &networkingv1.IngressServiceBackend{
+											Name: ServiceName(cr.Name),
+											Port: networkingv1.ServiceBackendPort{Number: OCIRegistryPort},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	if annotations := getIngressAnnotations(cr); len(annotations) > 0 {
+		ingress.Annotations = annotations
+	}
+
+	if className := cr.Spec.Exposure.Ingress.ClassName; className != "" {
+		ingress.Spec.IngressClassName = &className
+	}
+
+	if secretName := GetTLSSecretName(cr); IsTLSEnabled(cr) && secretName != "" {
+		ingress.Spec.TLS = []networkingv1.IngressTLS{
+			{
+				Hosts:      []string{host},
+				SecretName: secretName,
+			},
+		}
+	}
+
+	// Set DevfileRegistry instance as the owner and controller
+	ctrl.SetControllerReference(cr, ingress, scheme)
+	return ingress
+}
+
+// GenerateIngressV1beta1 returns the same ingress as GenerateIngress, for clusters that don't serve the
+// networking.k8s.io/v1 API
+func GenerateIngressV1beta1(cr *registryv1beta1.DevfileRegistry, host string, scheme *runtime.Scheme, labels map[string]string) *networkingv1beta1.Ingress {
+	pathType := networkingv1beta1.PathTypePrefix
+	ingress := &networkingv1beta1.Ingress{
 		ObjectMeta: generateObjectMeta(IngressName(cr.Name), cr.Namespace, labels),
-		Spec: v1beta1.IngressSpec{
-			Rules: []v1beta1.IngressRule{
+		Spec: networkingv1beta1.IngressSpec{
+			Rules: []networkingv1beta1.IngressRule{
 				{
 					Host: host,
-					IngressRuleValue: v1beta1.IngressRuleValue{
-						HTTP: &v1beta1.HTTPIngressRuleValue{
-							Paths: []v1beta1.HTTPIngressPath{
+					IngressRuleValue: networkingv1beta1.IngressRuleValue{
+						HTTP: &networkingv1beta1.HTTPIngressRuleValue{
+							Paths: []networkingv1beta1.HTTPIngressPath{
 								{
-									Path: "/",
-									Backend: v1beta1.IngressBackend{
+									Path:     "/",
+									PathType: &pathType,
+									Backend: networkingv1beta1.IngressBackend{
 										ServiceName: ServiceName(cr.Name),
 										ServicePort: intstr.FromInt(int(DevfileIndexPort)),
 									},
 								},
 								{
-									Path: "/v2",
-									Backend: v1beta1.IngressBackend{
+									Path:     "/v2",
+									PathType: &pathType,
+									Backend: networkingv1beta1.IngressBackend{
 										ServiceName: ServiceName(cr.Name),
 										ServicePort: intstr.FromInt(int(OCIRegistryPort)),
 									},
@@ -51,11 +126,20 @@ func GenerateIngress(cr *registryv1alpha1.DevfileRegistry, host string, scheme *
 		},
 	}
 
-	if IsTLSEnabled(cr) && cr.Spec.TLS.SecretName != "" {
-		ingress.Spec.TLS = []v1beta1.IngressTLS{
+	// The ingress class is set through the annotation, which every ingress controller of that era understands
+	annotations := getIngressAnnotations(cr)
+	if className := cr.Spec.Exposure.Ingress.ClassName; className != "" {
+		annotations[IngressClassAnnotation] = className
+	}
+	if len(annotations) > 0 {
+		ingress.Annotations = annotations
+	}
+
+	if secretName := GetTLSSecretName(cr); IsTLSEnabled(cr) && secretName != "" {
+		ingress.Spec.TLS = []networkingv1beta1.IngressTLS{
 			{
 				Hosts:      []string{host},
-				SecretName: cr.Spec.TLS.SecretName,
+				SecretName: secretName,
 			},
 		}
 	}
@@ -65,6 +149,28 @@ func GenerateIngress(cr *registryv1alpha1.DevfileRegistry, host string, scheme *
 	return ingress
 }
 
-func GetDevfileRegistryIngress(cr *registryv1alpha1.DevfileRegistry) string {
-	return cr.Name + "." + cr.Spec.K8s.IngressDomain
+// GetIngressURL returns the URL of a DevfileRegistry exposed under host by an ingress. It's only served over HTTPS
+// when the ingress has a certificate to present.
+func GetIngressURL(cr *registryv1beta1.DevfileRegistry, host string) string {
+	if IsTLSEnabled(cr) && GetTLSSecretName(cr) != "" {
+		return "https://" + host
+	}
+	return "http://" + host
+}
+
+// getIngressAnnotations returns the annotations of the ingress: the ones set in the DevfileRegistry, along with the
+// backend protocol when the registry serves TLS itself, unless it's overridden
+func getIngressAnnotations(cr *registryv1beta1.DevfileRegistry) map[string]string {
+	annotations := map[string]string{}
+	if IsInPodTLSEnabled(cr) {
+		annotations[IngressBackendProtocolAnnotation] = "HTTPS"
+	}
+	for key, value := range cr.Spec.Exposure.Ingress.Annotations {
+		annotations[key] = value
+	}
+	return annotations
+}
+
+func GetDevfileRegistryIngress(cr *registryv1beta1.DevfileRegistry) string {
+	return cr.Name + "." + cr.Spec.Exposure.Ingress.Domain
 }
@@ -0,0 +1,191 @@
+//
+// Copyright (c) 2020 Red Hat, Inc.
+// This program and the accompanying materials are made
+// available under the terms of the Eclipse Public License 2.0
+// which is available at https://www.eclipse.org/legal/epl-2.0/
+//
+// SPDX-License-Identifier: EPL-2.0
+//
+// Contributors:
+//   Red Hat, Inc. - initial API and implementation
+
+package registry
+
+import (
+	"reflect"
+	"testing"
+
+	networkingv1 "k8s.io/api/networking/v1"
+	networkingv1beta1 "k8s.io/api/networking/v1beta1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	registryv1beta1 "github.com/devfile/registry-operator/api/v1beta1"
+)
+
+func TestGenerateIngress(t *testing.T) {
+	tlsDisabled := false
+	host := "test-registry.example.com"
+
+	tests := []struct {
+		name            string
+		tls             registryv1beta1.DevfileRegistryTLS
+		ingress         registryv1beta1.DevfileRegistryIngress
+		wantClassName   string
+		wantAnnotations map[string]string
+		wantTLSSecret   string
+	}{
+		{
+			name:          "Case 1: Plain HTTP ingress",
+			tls:           registryv1beta1.DevfileRegistryTLS{Enabled: &tlsDisabled},
+			ingress:       registryv1beta1.DevfileRegistryIngress{Domain: "example.com"},
+			wantTLSSecret: "",
+		},
+		{
+			name: "Case 2: Ingress class and annotations",
+			tls:  registryv1beta1.DevfileRegistryTLS{Enabled: &tlsDisabled},
+			ingress: registryv1beta1.DevfileRegistryIngress{
+				Domain:      "example.com",
+				ClassName:   "nginx",
+				Annotations: map[string]string{"nginx.ingress.kubernetes.io/proxy-body-size": "0"},
+			},
+			wantClassName:   "nginx",
+			wantAnnotations: map[string]string{"nginx.ingress.kubernetes.io/proxy-body-size": "0"},
+		},
+		{
+			name:          "Case 3: Certificate from a secret",
+			tls:           registryv1beta1.DevfileRegistryTLS{SecretName: "registry-cert"},
+			ingress:       registryv1beta1.DevfileRegistryIngress{Domain: "example.com"},
+			wantTLSSecret: "registry-cert",
+		},
+		{
+			name:            "Case 4: TLS served in the pod",
+			tls:             registryv1beta1.DevfileRegistryTLS{SelfSigned: true, InPod: true},
+			ingress:         registryv1beta1.DevfileRegistryIngress{Domain: "example.com"},
+			wantAnnotations: map[string]string{IngressBackendProtocolAnnotation: "HTTPS"},
+			wantTLSSecret:   CertificateSecretName("test-registry"),
+		},
+		{
+			name: "Case 5: Backend protocol overridden by the annotations",
+			tls:  registryv1beta1.DevfileRegistryTLS{SelfSigned: true, InPod: true},
+			ingress: registryv1beta1.DevfileRegistryIngress{
+				Domain:      "example.com",
+				Annotations: map[string]string{IngressBackendProtocolAnnotation: "GRPCS"},
+			},
+			wantAnnotations: map[string]string{IngressBackendProtocolAnnotation: "GRPCS"},
+			wantTLSSecret:   CertificateSecretName("test-registry"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cr := &registryv1beta1.DevfileRegistry{Spec: registryv1beta1.DevfileRegistrySpec{
+				TLS:      tt.tls,
+				Exposure: registryv1beta1.DevfileRegistryExposure{Ingress: tt.ingress},
+			}}
+			cr.Name = "test-registry"
+			cr.Namespace = "test-namespace"
+
+			ingress := GenerateIngress(cr, host, runtime.NewScheme(), LabelsForDevfileRegistry(cr.Name))
+			if ingress.Name != IngressName(cr.Name) || ingress.Namespace != cr.Namespace {
+				t.Errorf("TestGenerateIngress error: name mismatch, expected: %v/%v got: %v/%v", cr.Namespace, IngressName(cr.Name), ingress.Namespace, ingress.Name)
+			}
+			rules := ingress.Spec.Rules
+			if len(rules) != 1 || rules[0].Host != host || rules[0].HTTP == nil || len(rules[0].HTTP.Paths) != 2 {
+				t.Fatalf("TestGenerateIngress error: expected one rule for %v with two paths, got: %+v", host, rules)
+			}
+			wantPorts := map[string]int32{"/": DevfileIndexPort, "/v2": OCIRegistryPort}
+			for _, path := range rules[0].HTTP.Paths {
+				if path.PathType == nil || *path.PathType != networkingv1.PathTypePrefix {
+					t.Errorf("TestGenerateIngress error: path type mismatch for %v, expected: %v got: %v", path.Path, networkingv1.PathTypePrefix, path.PathType)
+				}
+				if path.Backend.Service == nil || path.Backend.Service.Name != ServiceName(cr.Name) || path.Backend.Service.Port.Number != wantPorts[path.Path] {
+					t.Errorf("TestGenerateIngress error: backend mismatch for %v, expected: %v:%v got: %+v", path.Path, ServiceName(cr.Name), wantPorts[path.Path], path.Backend.Service)
+				}
+			}
+			var className string
+			if ingress.Spec.IngressClassName != nil {
+				className = *ingress.Spec.IngressClassName
+			}
+			if className != tt.wantClassName {
+				t.Errorf("TestGenerateIngress error: ingress class mismatch, expected: %v got: %v", tt.wantClassName, className)
+			}
+			if len(ingress.Annotations) != len(tt.wantAnnotations) || (len(tt.wantAnnotations) > 0 && !reflect.DeepEqual(ingress.Annotations, tt.wantAnnotations)) {
+				t.Errorf("TestGenerateIngress error: annotations mismatch, expected: %v got: %v", tt.wantAnnotations, ingress.Annotations)
+			}
+			var wantTLS []networkingv1.IngressTLS
+			if tt.wantTLSSecret != "" {
+				wantTLS = []networkingv1.IngressTLS{{Hosts: []string{host}, SecretName: tt.wantTLSSecret}}
+			}
+			if !reflect.DeepEqual(ingress.Spec.TLS, wantTLS) {
+				t.Errorf("TestGenerateIngress error: TLS mismatch, expected: %+v got: %+v", wantTLS, ingress.Spec.TLS)
+			}
+		})
+	}
+}
+
+func TestGenerateIngressV1beta1(t *testing.T) {
+	host := "test-registry.example.com"
+
+	tests := []struct {
+		name            string
+		tls             registryv1beta1.DevfileRegistryTLS
+		ingress         registryv1beta1.DevfileRegistryIngress
+		wantAnnotations map[string]string
+		wantTLSSecret   string
+	}{
+		{
+			name:          "Case 1: Certificate from a secret",
+			tls:           registryv1beta1.DevfileRegistryTLS{SecretName: "registry-cert"},
+			ingress:       registryv1beta1.DevfileRegistryIngress{Domain: "example.com"},
+			wantTLSSecret: "registry-cert",
+		},
+		{
+			name: "Case 2: Ingress class set through the annotation",
+			tls:  registryv1beta1.DevfileRegistryTLS{SecretName: "registry-cert"},
+			ingress: registryv1beta1.DevfileRegistryIngress{
+				Domain:      "example.com",
+				ClassName:   "nginx",
+				Annotations: map[string]string{"nginx.ingress.kubernetes.io/proxy-body-size": "0"},
+			},
+			wantAnnotations: map[string]string{
+				IngressClassAnnotation:                        "nginx",
+				"nginx.ingress.kubernetes.io/proxy-body-size": "0",
+			},
+			wantTLSSecret: "registry-cert",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cr := &registryv1beta1.DevfileRegistry{Spec: registryv1beta1.DevfileRegistrySpec{
+				TLS:      tt.tls,
+				Exposure: registryv1beta1.DevfileRegistryExposure{Ingress: tt.ingress},
+			}}
+			cr.Name = "test-registry"
+			cr.Namespace = "test-namespace"
+
+			ingress := GenerateIngressV1beta1(cr, host, runtime.NewScheme(), LabelsForDevfileRegistry(cr.Name))
+			rules := ingress.Spec.Rules
+			if len(rules) != 1 || rules[0].Host != host || rules[0].HTTP == nil || len(rules[0].HTTP.Paths) != 2 {
+				t.Fatalf("TestGenerateIngressV1beta1 error: expected one rule for %v with two paths, got: %+v", host, rules)
+			}
+			wantPorts := map[string]int{"/": DevfileIndexPort, "/v2": OCIRegistryPort}
+			for _, path := range rules[0].HTTP.Paths {
+				if path.PathType == nil || *path.PathType != networkingv1beta1.PathTypePrefix {
+					t.Errorf("TestGenerateIngressV1beta1 error: path type mismatch for %v, expected: %v got: %v", path.Path, networkingv1beta1.PathTypePrefix, path.PathType)
+				}
+				if path.Backend.ServiceName != ServiceName(cr.Name) || path.Backend.ServicePort.IntValue() != wantPorts[path.Path] {
+					t.Errorf("TestGenerateIngressV1beta1 error: backend mismatch for %v, expected: %v:%v got: %+v", path.Path, ServiceName(cr.Name), wantPorts[path.Path], path.Backend)
+				}
+			}
+			if ingress.Spec.IngressClassName != nil {
+				t.Errorf("TestGenerateIngressV1beta1 error: expected no ingressClassName, got: %v", *ingress.Spec.IngressClassName)
+			}
+			if len(ingress.Annotations) != len(tt.wantAnnotations) || (len(tt.wantAnnotations) > 0 && !reflect.DeepEqual(ingress.Annotations, tt.wantAnnotations)) {
+				t.Errorf("TestGenerateIngressV1beta1 error: annotations mismatch, expected: %v got: %v", tt.wantAnnotations, ingress.Annotations)
+			}
+			wantTLS := []networkingv1beta1.IngressTLS{{Hosts: []string{host}, SecretName: tt.wantTLSSecret}}
+			if !reflect.DeepEqual(ingress.Spec.TLS, wantTLS) {
+				t.Errorf("TestGenerateIngressV1beta1 error: TLS mismatch, expected: %+v got: %+v", wantTLS, ingress.Spec.TLS)
+			}
+		})
+	}
+}
@@ -11,6 +11,12 @@
 
 package registry
 
+import (
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
 // DeploymentName returns the name of the deployment object associated with the DevfileRegistry CR
 // Just returns the CR name right now, but extracting to a function to avoid relying on that assumption
 func DeploymentName(devfileRegistryName string) string {
@@ -35,6 +41,12 @@ func IngressName(devfileRegistryName string) string {
 	return devfileRegistryName
 }
 
+// HTTPRouteName returns the name of the Gateway API HTTPRoute object associated with the DevfileRegistry CR
+// Just returns the CR name right now, but extracting to a function to avoid relying on that assumption
+func HTTPRouteName(devfileRegistryName string) string {
+	return devfileRegistryName
+}
+
 // DevfilesRouteName returns the name of the route object associated with the devfile index route
 func DevfilesRouteName(devfileRegistryName string) string {
 	return devfileRegistryName + "-devfiles"
@@ -44,3 +56,63 @@ func DevfilesRouteName(devfileRegistryName string) string {
 func OCIRouteName(devfileRegistryName string) string {
 	return devfileRegistryName + "-oci"
 }
+
+// VolumeSnapshotName returns the name of the volume snapshot taken of the registry storage when the DevfileRegistry is
+// deleted. It holds the UID of the DevfileRegistry, so that a registry recreated with the same name never mistakes the
+// snapshot of its predecessor for its own.
+func VolumeSnapshotName(devfileRegistryName string, uid types.UID) string {
+	return devfileRegistryName + "-snapshot-" + string(uid)
+}
+
+// BackupSnapshotName returns the name of the volume snapshot a DevfileRegistryBackup takes for the backup due at the
+// given time
+func BackupSnapshotName(backupName string, due time.Time) string {
+	return backupName + "-" + due.UTC().Format("20060102150405")
+}
+
+// ArchiveJobName returns the name of the job a DevfileRegistryExport runs to copy the registry stacks
+func ArchiveJobName(exportName string) string {
+	return exportName + "-archive"
+}
+
+// CertificateName returns the name of the cert-manager certificate requested for the ingress hostname
+// Just returns the CR name right now, but extracting to a function to avoid relying on that assumption
+func CertificateName(devfileRegistryName string) string {
+	return devfileRegistryName
+}
+
+// CertificateSecretName returns the name of the secret cert-manager stores the ingress certificate in
+func CertificateSecretName(devfileRegistryName string) string {
+	return devfileRegistryName + "-tls"
+}
+
+// CASecretName returns the name of the secret holding the CA generated by the operator for the DevfileRegistry
+func CASecretName(devfileRegistryName string) string {
+	return devfileRegistryName + "-ca"
+}
+
+// CABundleName returns the name of the config map publishing the CA generated by the operator for the DevfileRegistry
+func CABundleName(devfileRegistryName string) string {
+	return devfileRegistryName + "-ca-bundle"
+}
+
+// OCIRegistryConfigName returns the name of the config map holding the configuration of the OCI registry
+func OCIRegistryConfigName(devfileRegistryName string) string {
+	return devfileRegistryName + "-oci-config"
+}
+
+// GarbageCollectionCronJobName returns the name of the cron job collecting the garbage of the OCI registry
+func GarbageCollectionCronJobName(devfileRegistryName string) string {
+	return devfileRegistryName + "-gc"
+}
+
+// PodDisruptionBudgetName returns the name of the pod disruption budget of the devfile registry pods
+// Just returns the CR name right now, but extracting to a function to avoid relying on that assumption
+func PodDisruptionBudgetName(devfileRegistryName string) string {
+	return devfileRegistryName
+}
+
+// HTTPSecretName returns the name of the secret holding the HTTP secret shared by the OCI registry replicas
+func HTTPSecretName(devfileRegistryName string) string {
+	return devfileRegistryName + "-http-secret"
+}
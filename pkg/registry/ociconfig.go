@@ -0,0 +1,204 @@
+//
+// Copyright (c) 2020 Red Hat, Inc.
+// This program and the accompanying materials are made
+// available under the terms of the Eclipse Public License 2.0
+// which is available at https://www.eclipse.org/legal/epl-2.0/
+//
+// SPDX-License-Identifier: EPL-2.0
+//
+// Contributors:
+//   Red Hat, Inc. - initial API and implementation
+
+package registry
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	registryv1beta1 "github.com/devfile/registry-operator/api/v1beta1"
+)
+
+const (
+	// OCIRegistryConfigKey is the key of the configuration file in the OCI registry config map
+	OCIRegistryConfigKey = "config.yml"
+	// OCIRegistryConfigMountPath is where the distribution registry image reads its configuration file from
+	OCIRegistryConfigMountPath = "/etc/docker/registry"
+	// OCIRegistryConfigVolumeName is the name of the volume holding the OCI registry configuration
+	OCIRegistryConfigVolumeName = "oci-registry-config"
+	// OCIRegistryConfigChecksumAnnotation is set on the pod template to the checksum of the OCI registry
+	// configuration, so that the pods are rolled when it changes
+	OCIRegistryConfigChecksumAnnotation = "registry.devfile.io/oci-config-checksum"
+	// OCIRegistryStoragePath is where the OCI registry stores its content
+	OCIRegistryStoragePath = "/var/lib/registry"
+
+	DefaultOCIRegistryLogLevel = "info"
+	// ociRegistryHealthCheckThreshold is the number of failed storage health checks after which the OCI registry
+	// reports itself unhealthy
+	ociRegistryHealthCheckThreshold = 3
+)
+
+// ociRegistryConfig is the subset of the distribution registry configuration the operator sets. See
+// https://docs.docker.com/registry/configuration/ for the meaning of each field.
+type ociRegistryConfig struct {
+	Version string                  `yaml:"version"`
+	Log     ociRegistryLogConfig    `yaml:"log"`
+	Storage map[string]interface{}  `yaml:"storage"`
+	HTTP    ociRegistryHTTPConfig   `yaml:"http"`
+	Health  *ociRegistryHealthCheck `yaml:"health,omitempty"`
+}
+
+type ociRegistryLogConfig struct {
+	Level  string            `yaml:"level"`
+	Fields map[string]string `yaml:"fields,omitempty"`
+}
+
+type ociRegistryHTTPConfig struct {
+	Addr    string              `yaml:"addr"`
+	Headers map[string][]string `yaml:"headers"`
+	TLS     *ociRegistryTLS     `yaml:"tls,omitempty"`
+}
+
+type ociRegistryTLS struct {
+	Certificate string `yaml:"certificate"`
+	Key         string `yaml:"key"`
+}
+
+type ociRegistryHealthCheck struct {
+	StorageDriver ociRegistryStorageDriverHealthCheck `yaml:"storagedriver"`
+}
+
+type ociRegistryStorageDriverHealthCheck struct {
+	Enabled   bool   `yaml:"enabled"`
+	Interval  string `yaml:"interval"`
+	Threshold int    `yaml:"threshold"`
+}
+
+// GenerateOCIRegistryConfig renders the configuration file of the OCI registry from the DevfileRegistry
+func GenerateOCIRegistryConfig(cr *registryv1beta1.DevfileRegistry) string {
+	config := cr.Spec.OCIRegistry.Config
+
+	logLevel := config.LogLevel
+	if logLevel == "" {
+		logLevel = DefaultOCIRegistryLogLevel
+	}
+
+	registryConfig := ociRegistryConfig{
+		Version: "0.1",
+		Log: ociRegistryLogConfig{
+			Level:  logLevel,
+			Fields: map[string]string{"service": "registry"},
+		},
+		Storage: map[string]interface{}{
+			"cache":  map[string]interface{}{"blobdescriptor": "inmemory"},
+			"delete": map[string]interface{}{"enabled": config.DeleteEnabled},
+		},
+		HTTP: ociRegistryHTTPConfig{
+			Addr:    fmt.Sprintf(":%d", OCIRegistryPort),
+			Headers: map[string][]string{"X-Content-Type-Options": {"nosniff"}},
+		},
+	}
+	if IsS3Enabled(cr) {
+		registryConfig.Storage["s3"] = generateS3StorageConfig(cr)
+	} else {
+		registryConfig.Storage["filesystem"] = map[string]interface{}{"rootdirectory": OCIRegistryStoragePath}
+	}
+	if IsReadOnly(cr) {
+		// Pushes are rejected while the garbage is collected, so that no blob is deleted while a manifest is uploaded
+		// referencing it
+		registryConfig.Storage["maintenance"] = map[string]interface{}{
+			"readonly": map[string]interface{}{"enabled": true},
+		}
+	}
+	if IsInPodTLSEnabled(cr) {
+		registryConfig.HTTP.TLS = &ociRegistryTLS{
+			Certificate: DevfileRegistryTLSMountPath + "/" + corev1.TLSCertKey,
+			Key:         DevfileRegistryTLSMountPath + "/" + corev1.TLSPrivateKeyKey,
+		}
+	}
+	if config.StorageHealthCheckInterval != nil {
+		registryConfig.Health = &ociRegistryHealthCheck{
+			StorageDriver: ociRegistryStorageDriverHealthCheck{
+				Enabled:   true,
+				Interval:  config.StorageHealthCheckInterval.Duration.String(),
+				Threshold: ociRegistryHealthCheckThreshold,
+			},
+		}
+	}
+
+	// The config only holds strings, booleans, numbers and maps of them, which always marshal
+	data, _ := yaml.Marshal(registryConfig)
+	return string(data)
+}
+
+// generateS3StorageConfig returns the settings of the s3 storage driver of the OCI registry. The credentials are passed
+// through the environment, see GetOCIRegistryEnv.
+func generateS3StorageConfig(cr *registryv1beta1.DevfileRegistry) map[string]interface{} {
+	s3 := cr.Spec.Storage.S3
+	region := s3.Region
+	if region == "" {
+		region = DefaultS3Region
+	}
+
+	driver := map[string]interface{}{
+		"bucket": s3.Bucket,
+		"region": region,
+	}
+	if s3.Endpoint != "" {
+		// The driver addresses buckets by path on custom endpoints, which is what MinIO and most other
+		// S3-compatible services expect
+		driver["regionendpoint"] = s3.Endpoint
+		driver["secure"] = !strings.HasPrefix(s3.Endpoint, "http://")
+	}
+	if s3.RootDirectory != "" {
+		driver["rootdirectory"] = s3.RootDirectory
+	}
+	return driver
+}
+
+// GetOCIRegistryEnv returns the environment of the OCI registry container, which holds the settings that can't be
+// written to its configuration file
+func GetOCIRegistryEnv(cr *registryv1beta1.DevfileRegistry) []corev1.EnvVar {
+	var env []corev1.EnvVar
+	if IsS3Enabled(cr) {
+		env = append(env,
+			secretKeyEnv("REGISTRY_STORAGE_S3_ACCESSKEY", cr.Spec.Storage.S3.CredentialsSecret, S3AccessKeyIDKey),
+			secretKeyEnv("REGISTRY_STORAGE_S3_SECRETKEY", cr.Spec.Storage.S3.CredentialsSecret, S3SecretAccessKeyKey),
+		)
+	}
+	// The upload state is signed with the HTTP secret, and the parts of an upload may reach any of the replicas
+	if IsReplicated(cr) {
+		env = append(env, secretKeyEnv("REGISTRY_HTTP_SECRET", HTTPSecretName(cr.Name), HTTPSecretKey))
+	}
+	return env
+}
+
+// GetOCIRegistryConfigChecksum returns the checksum of the OCI registry configuration file
+func GetOCIRegistryConfigChecksum(cr *registryv1beta1.DevfileRegistry) string {
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(GenerateOCIRegistryConfig(cr))))
+}
+
+// GenerateOCIRegistryConfigMap returns the config map holding the configuration file of the OCI registry
+func GenerateOCIRegistryConfigMap(cr *registryv1beta1.DevfileRegistry, scheme *runtime.Scheme, labels map[string]string) *corev1.ConfigMap {
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: generateObjectMeta(OCIRegistryConfigName(cr.Name), cr.Namespace, labels),
+		Data: map[string]string{
+			OCIRegistryConfigKey: GenerateOCIRegistryConfig(cr),
+		},
+	}
+
+	// Signal the garbage collection job once the registry pods run with the read-only configuration. The checksum of
+	// the configuration file doesn't cover it, so the pods aren't rolled again.
+	if cr.Status.GarbageCollection != nil && cr.Status.GarbageCollection.ReadOnly {
+		configMap.Data[GarbageCollectionReadyKey] = "true"
+	}
+
+	// Set DevfileRegistry instance as the owner and controller
+	ctrl.SetControllerReference(cr, configMap, scheme)
+	return configMap
+}
@@ -0,0 +1,189 @@
+//
+// Copyright (c) 2020 Red Hat, Inc.
+// This program and the accompanying materials are made
+// available under the terms of the Eclipse Public License 2.0
+// which is available at https://www.eclipse.org/legal/epl-2.0/
+//
+// SPDX-License-Identifier: EPL-2.0
+//
+// Contributors:
+//   Red Hat, Inc. - initial API and implementation
+
+package registry
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"gopkg.in/yaml.v2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	registryv1beta1 "github.com/devfile/registry-operator/api/v1beta1"
+)
+
+func TestGenerateOCIRegistryConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  registryv1beta1.DevfileRegistryOCIRegistryConfig
+		tls     registryv1beta1.DevfileRegistryTLS
+		storage registryv1beta1.DevfileRegistryStorage
+		gc      *registryv1beta1.DevfileRegistryGarbageCollectionStatus
+		want    map[string]interface{}
+	}{
+		{
+			name: "Case 1: Default configuration",
+			want: map[string]interface{}{
+				"log.level":              "info",
+				"storage.delete.enabled": false,
+				"http.addr":              ":5000",
+				"http.tls":               nil,
+				"health":                 nil,
+				"storage.maintenance":    nil,
+			},
+		},
+		{
+			name: "Case 2: Custom configuration",
+			config: registryv1beta1.DevfileRegistryOCIRegistryConfig{
+				LogLevel:                   "debug",
+				DeleteEnabled:              true,
+				StorageHealthCheckInterval: &metav1.Duration{Duration: 30 * time.Second},
+			},
+			want: map[string]interface{}{
+				"log.level":                           "debug",
+				"storage.delete.enabled":              true,
+				"health.storagedriver.enabled":        true,
+				"health.storagedriver.interval":       "30s",
+				"health.storagedriver.threshold":      3,
+				"storage.filesystem.rootdirectory":    "/var/lib/registry",
+				"storage.cache.blobdescriptor":        "inmemory",
+				"http.headers.X-Content-Type-Options": []interface{}{"nosniff"},
+			},
+		},
+		{
+			name: "Case 3: TLS served in the pod",
+			tls:  registryv1beta1.DevfileRegistryTLS{SelfSigned: true, InPod: true},
+			want: map[string]interface{}{
+				"http.tls.certificate": "/etc/devfile-registry/tls/tls.crt",
+				"http.tls.key":         "/etc/devfile-registry/tls/tls.key",
+			},
+		},
+		{
+			name: "Case 4: S3 storage on a MinIO endpoint",
+			storage: registryv1beta1.DevfileRegistryStorage{
+				Type: registryv1beta1.StorageTypeS3,
+				S3: registryv1beta1.DevfileRegistryS3Storage{
+					Bucket:            "devfiles",
+					Endpoint:          "http://minio.minio.svc:9000",
+					RootDirectory:     "/registry",
+					CredentialsSecret: "minio-credentials",
+				},
+			},
+			want: map[string]interface{}{
+				"storage.s3.bucket":         "devfiles",
+				"storage.s3.region":         "us-east-1",
+				"storage.s3.regionendpoint": "http://minio.minio.svc:9000",
+				"storage.s3.secure":         false,
+				"storage.s3.rootdirectory":  "/registry",
+				"storage.filesystem":        nil,
+			},
+		},
+		{
+			name: "Case 5: S3 storage on AWS",
+			storage: registryv1beta1.DevfileRegistryStorage{
+				Type: registryv1beta1.StorageTypeS3,
+				S3: registryv1beta1.DevfileRegistryS3Storage{
+					Bucket:            "devfiles",
+					Region:            "eu-west-1",
+					CredentialsSecret: "aws-credentials",
+				},
+			},
+			want: map[string]interface{}{
+				"storage.s3.bucket":         "devfiles",
+				"storage.s3.region":         "eu-west-1",
+				"storage.s3.regionendpoint": nil,
+				"storage.s3.secure":         nil,
+			},
+		},
+		{
+			name: "Case 6: Read-only while the garbage is collected",
+			gc:   &registryv1beta1.DevfileRegistryGarbageCollectionStatus{ActiveJob: "devfileregistry-sample-gc-27049320"},
+			want: map[string]interface{}{
+				"storage.maintenance.readonly.enabled": true,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cr := &registryv1beta1.DevfileRegistry{
+				Spec: registryv1beta1.DevfileRegistrySpec{
+					OCIRegistry: registryv1beta1.DevfileRegistryOCIRegistry{Config: tt.config},
+					TLS:         tt.tls,
+					Storage:     tt.storage,
+				},
+				Status: registryv1beta1.DevfileRegistryStatus{GarbageCollection: tt.gc},
+			}
+			config := map[string]interface{}{}
+			if err := yaml.Unmarshal([]byte(GenerateOCIRegistryConfig(cr)), &config); err != nil {
+				t.Fatalf("TestGenerateOCIRegistryConfig error: unexpected error parsing the config: %v", err)
+			}
+			for path, want := range tt.want {
+				value := lookupConfig(config, path)
+				if !reflect.DeepEqual(value, want) {
+					t.Errorf("TestGenerateOCIRegistryConfig error: %s mismatch, expected: %v got: %v", path, want, value)
+				}
+			}
+		})
+	}
+}
+
+func TestGetOCIRegistryEnv(t *testing.T) {
+	cr := &registryv1beta1.DevfileRegistry{}
+	if env := GetOCIRegistryEnv(cr); len(env) != 0 {
+		t.Errorf("TestGetOCIRegistryEnv error: unexpected env with persistent volume storage, expected: none got: %v", env)
+	}
+
+	cr.Spec.Storage.Type = registryv1beta1.StorageTypeS3
+	cr.Spec.Storage.S3.CredentialsSecret = "minio-credentials"
+	env := GetOCIRegistryEnv(cr)
+	want := map[string]string{
+		"REGISTRY_STORAGE_S3_ACCESSKEY": S3AccessKeyIDKey,
+		"REGISTRY_STORAGE_S3_SECRETKEY": S3SecretAccessKeyKey,
+	}
+	if len(env) != len(want) {
+		t.Fatalf("TestGetOCIRegistryEnv error: env length mismatch, expected: %v got: %v", len(want), len(env))
+	}
+	for _, e := range env {
+		ref := e.ValueFrom.SecretKeyRef
+		if ref.Name != "minio-credentials" || ref.Key != want[e.Name] {
+			t.Errorf("TestGetOCIRegistryEnv error: %s secret key mismatch, expected: minio-credentials/%s got: %s/%s", e.Name, want[e.Name], ref.Name, ref.Key)
+		}
+	}
+}
+
+func TestGetOCIRegistryConfigChecksum(t *testing.T) {
+	cr := &registryv1beta1.DevfileRegistry{}
+	checksum := GetOCIRegistryConfigChecksum(cr)
+	if GetOCIRegistryConfigChecksum(cr) != checksum {
+		t.Errorf("TestGetOCIRegistryConfigChecksum error: checksum changed without the configuration changing")
+	}
+	cr.Spec.OCIRegistry.Config.DeleteEnabled = true
+	if GetOCIRegistryConfigChecksum(cr) == checksum {
+		t.Errorf("TestGetOCIRegistryConfigChecksum error: checksum didn't change with the configuration")
+	}
+}
+
+// lookupConfig returns the value at a dot separated path of a parsed configuration, or nil if there's none
+func lookupConfig(config map[string]interface{}, path string) interface{} {
+	keys := strings.Split(path, ".")
+	value := config[keys[0]]
+	for _, key := range keys[1:] {
+		m, ok := value.(map[interface{}]interface{})
+		if !ok {
+			return nil
+		}
+		value = m[key]
+	}
+	return value
+}
@@ -13,15 +13,17 @@ package registry
 
 import (
 	routev1 "github.com/openshift/api/route/v1"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	ctrl "sigs.k8s.io/controller-runtime"
 
-	registryv1alpha1 "github.com/devfile/registry-operator/api/v1alpha1"
+	registryv1beta1 "github.com/devfile/registry-operator/api/v1beta1"
 )
 
-// GenerateDevfilesRoute returns a route exposing the devfile registry index
-func GenerateDevfilesRoute(cr *registryv1alpha1.DevfileRegistry, scheme *runtime.Scheme, labels map[string]string) *routev1.Route {
+// GenerateDevfilesRoute returns a route exposing the devfile registry index. The certificate of the route is read from
+// tlsSecret when it's set.
+func GenerateDevfilesRoute(cr *registryv1beta1.DevfileRegistry, tlsSecret *corev1.Secret, scheme *runtime.Scheme, labels map[string]string) *routev1.Route {
 	weight := int32(100)
 
 	route := &routev1.Route{
@@ -38,18 +40,21 @@ func GenerateDevfilesRoute(cr *registryv1alpha1.DevfileRegistry, scheme *runtime
 			Path: "/",
 		},
 	}
-
-	if cr.Spec.TLS.Enabled != nil && *cr.Spec.TLS.Enabled {
-		route.Spec.TLS = &routev1.TLSConfig{Termination: routev1.TLSTerminationEdge}
+	// Passthrough routes can't have a path, the router can't see it
+	if GetTLSTermination(cr) == registryv1beta1.TLSTerminationPassthrough {
+		route.Spec.Path = ""
 	}
 
+	route.Spec.TLS = generateRouteTLSConfig(cr, tlsSecret)
+
 	// Set DevfileRegistry instance as the owner and controller
 	ctrl.SetControllerReference(cr, route, scheme)
 	return route
 }
 
-// GenerateOCIRoute returns a route object for the OCI registry server
-func GenerateOCIRoute(cr *registryv1alpha1.DevfileRegistry, host string, scheme *runtime.Scheme, labels map[string]string) *routev1.Route {
+// GenerateOCIRoute returns a route object for the OCI registry server. The certificate of the route is read from
+// tlsSecret when it's set.
+func GenerateOCIRoute(cr *registryv1beta1.DevfileRegistry, host string, tlsSecret *corev1.Secret, scheme *runtime.Scheme, labels map[string]string) *routev1.Route {
 	weight := int32(100)
 
 	route := &routev1.Route{
@@ -68,9 +73,7 @@ func GenerateOCIRoute(cr *registryv1alpha1.DevfileRegistry, host string, scheme
 		},
 	}
 
-	if cr.Spec.TLS.Enabled != nil && *cr.Spec.TLS.Enabled {
-		route.Spec.TLS = &routev1.TLSConfig{Termination: routev1.TLSTerminationEdge}
-	}
+	route.Spec.TLS = generateRouteTLSConfig(cr, tlsSecret)
 
 	if host != "" {
 		route.Spec.Host = host
@@ -80,3 +83,50 @@ func GenerateOCIRoute(cr *registryv1alpha1.DevfileRegistry, host string, scheme
 	ctrl.SetControllerReference(cr, route, scheme)
 	return route
 }
+
+// GetTLSTermination returns where the routes exposing the DevfileRegistry terminate TLS
+func GetTLSTermination(cr *registryv1beta1.DevfileRegistry) registryv1beta1.DevfileRegistryTLSTermination {
+	if cr.Spec.TLS.Termination != "" {
+		return cr.Spec.TLS.Termination
+	}
+	// The router has to talk TLS to registries serving it themselves
+	if IsInPodTLSEnabled(cr) {
+		return registryv1beta1.TLSTerminationReencrypt
+	}
+	return DefaultTLSTermination
+}
+
+// IsOCIRouteEnabled returns true if the OCI registry can be exposed through its own route, under the hostname of the
+// devfile index route. Passthrough routes can't be told apart by path.
+func IsOCIRouteEnabled(cr *registryv1beta1.DevfileRegistry) bool {
+	return !IsTLSEnabled(cr) || GetTLSTermination(cr) != registryv1beta1.TLSTerminationPassthrough
+}
+
+// generateRouteTLSConfig returns the TLS configuration of the routes exposing the DevfileRegistry, or nil if TLS is
+// disabled. Routes terminating TLS present the certificate from tlsSecret, or the router's default one when it has
+// none. Re-encrypting routes verify the registry against the CA in tlsSecret.
+func generateRouteTLSConfig(cr *registryv1beta1.DevfileRegistry, tlsSecret *corev1.Secret) *routev1.TLSConfig {
+	if !IsTLSEnabled(cr) {
+		return nil
+	}
+
+	termination := GetTLSTermination(cr)
+	tlsConfig := &routev1.TLSConfig{
+		Termination:                   routev1.TLSTerminationType(termination),
+		InsecureEdgeTerminationPolicy: routev1.InsecureEdgeTerminationPolicyType(cr.Spec.TLS.InsecureEdgeTerminationPolicy),
+	}
+	if tlsSecret == nil || termination == registryv1beta1.TLSTerminationPassthrough {
+		return tlsConfig
+	}
+
+	if len(tlsSecret.Data[corev1.TLSCertKey]) > 0 {
+		tlsConfig.Certificate = string(tlsSecret.Data[corev1.TLSCertKey])
+		tlsConfig.Key = string(tlsSecret.Data[corev1.TLSPrivateKeyKey])
+		tlsConfig.CACertificate = string(tlsSecret.Data[TLSSecretCAKey])
+	}
+	if termination == registryv1beta1.TLSTerminationReencrypt {
+		// The registry's own certificate is expected to be signed by the same CA
+		tlsConfig.DestinationCACertificate = string(tlsSecret.Data[TLSSecretCAKey])
+	}
+	return tlsConfig
+}
@@ -0,0 +1,121 @@
+//
+// Copyright (c) 2020 Red Hat, Inc.
+// This program and the accompanying materials are made
+// available under the terms of the Eclipse Public License 2.0
+// which is available at https://www.eclipse.org/legal/epl-2.0/
+//
+// SPDX-License-Identifier: EPL-2.0
+//
+// Contributors:
+//   Red Hat, Inc. - initial API and implementation
+
+package registry
+
+import (
+	"reflect"
+	"testing"
+
+	routev1 "github.com/openshift/api/route/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	registryv1beta1 "github.com/devfile/registry-operator/api/v1beta1"
+)
+
+func TestGenerateDevfilesRouteTLS(t *testing.T) {
+	tlsDisabled := false
+	tlsSecret := &corev1.Secret{
+		Data: map[string][]byte{
+			corev1.TLSCertKey:       []byte("cert"),
+			corev1.TLSPrivateKeyKey: []byte("key"),
+			TLSSecretCAKey:          []byte("ca"),
+		},
+	}
+
+	tests := []struct {
+		name      string
+		tls       registryv1beta1.DevfileRegistryTLS
+		tlsSecret *corev1.Secret
+		wantTLS   *routev1.TLSConfig
+		wantPath  string
+	}{
+		{
+			name:     "Case 1: Edge termination with the router's certificate",
+			wantTLS:  &routev1.TLSConfig{Termination: routev1.TLSTerminationEdge},
+			wantPath: "/",
+		},
+		{
+			name: "Case 2: Edge termination with a certificate from a secret",
+			tls: registryv1beta1.DevfileRegistryTLS{
+				SecretName:                    "registry-cert",
+				InsecureEdgeTerminationPolicy: registryv1beta1.InsecureEdgeTerminationPolicyRedirect,
+			},
+			tlsSecret: tlsSecret,
+			wantTLS: &routev1.TLSConfig{
+				Termination:                   routev1.TLSTerminationEdge,
+				InsecureEdgeTerminationPolicy: routev1.InsecureEdgeTerminationPolicyRedirect,
+				Certificate:                   "cert",
+				Key:                           "key",
+				CACertificate:                 "ca",
+			},
+			wantPath: "/",
+		},
+		{
+			name: "Case 3: Reencrypt termination",
+			tls: registryv1beta1.DevfileRegistryTLS{
+				SecretName:  "registry-cert",
+				Termination: registryv1beta1.TLSTerminationReencrypt,
+			},
+			tlsSecret: tlsSecret,
+			wantTLS: &routev1.TLSConfig{
+				Termination:              routev1.TLSTerminationReencrypt,
+				Certificate:              "cert",
+				Key:                      "key",
+				CACertificate:            "ca",
+				DestinationCACertificate: "ca",
+			},
+			wantPath: "/",
+		},
+		{
+			name: "Case 4: Passthrough termination",
+			tls: registryv1beta1.DevfileRegistryTLS{
+				SecretName:  "registry-cert",
+				Termination: registryv1beta1.TLSTerminationPassthrough,
+			},
+			tlsSecret: tlsSecret,
+			wantTLS:   &routev1.TLSConfig{Termination: routev1.TLSTerminationPassthrough},
+			wantPath:  "",
+		},
+		{
+			name: "Case 5: TLS served in the pod, with the router's certificate",
+			tls: registryv1beta1.DevfileRegistryTLS{
+				SelfSigned: true,
+				InPod:      true,
+			},
+			tlsSecret: &corev1.Secret{Data: map[string][]byte{TLSSecretCAKey: []byte("ca")}},
+			wantTLS: &routev1.TLSConfig{
+				Termination:              routev1.TLSTerminationReencrypt,
+				DestinationCACertificate: "ca",
+			},
+			wantPath: "/",
+		},
+		{
+			name:     "Case 6: TLS disabled",
+			tls:      registryv1beta1.DevfileRegistryTLS{Enabled: &tlsDisabled},
+			wantTLS:  nil,
+			wantPath: "/",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cr := &registryv1beta1.DevfileRegistry{Spec: registryv1beta1.DevfileRegistrySpec{TLS: tt.tls}}
+			route := GenerateDevfilesRoute(cr, tt.tlsSecret, runtime.NewScheme(), nil)
+			if !reflect.DeepEqual(route.Spec.TLS, tt.wantTLS) {
+				t.Errorf("TestGenerateDevfilesRouteTLS error: TLS config mismatch, expected: %v got: %v", tt.wantTLS, route.Spec.TLS)
+			}
+			if route.Spec.Path != tt.wantPath {
+				t.Errorf("TestGenerateDevfilesRouteTLS error: path mismatch, expected: %v got: %v", tt.wantPath, route.Spec.Path)
+			}
+		})
+	}
+}
@@ -0,0 +1,180 @@
+//
+// Copyright (c) 2020 Red Hat, Inc.
+// This program and the accompanying materials are made
+// available under the terms of the Eclipse Public License 2.0
+// which is available at https://www.eclipse.org/legal/epl-2.0/
+//
+// SPDX-License-Identifier: EPL-2.0
+//
+// Contributors:
+//   Red Hat, Inc. - initial API and implementation
+
+package registry
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	registryv1beta1 "github.com/devfile/registry-operator/api/v1beta1"
+	"github.com/devfile/registry-operator/pkg/util"
+)
+
+const (
+	// CABundleKey is the key of the PEM encoded CA certificates in the CA bundle config map
+	CABundleKey = "ca.crt"
+	// previousCAKey holds the CA certificate that was rotated out in the CA secret, so that it stays trusted until the
+	// certificates it signed are replaced
+	previousCAKey = "previous.crt"
+)
+
+// IsSelfSigned returns true if the operator has to generate the CA and serving certificate of the DevfileRegistry
+func IsSelfSigned(cr *registryv1beta1.DevfileRegistry) bool {
+	return IsTLSEnabled(cr) && cr.Spec.TLS.SelfSigned && cr.Spec.TLS.SecretName == "" && cr.Spec.TLS.Issuer.Name == ""
+}
+
+// IsSelfSignedCertificateServed returns true if the clients of the DevfileRegistry are presented its self-signed
+// certificate: by the ingress, or by the registry containers when they're reached through the service. Routes and
+// Gateways present their own certificate.
+func IsSelfSignedCertificateServed(cr *registryv1beta1.DevfileRegistry) bool {
+	if !IsSelfSigned(cr) {
+		return false
+	}
+	switch GetExposureType(cr) {
+	case registryv1beta1.ExposureTypeIngress:
+		return true
+	case registryv1beta1.ExposureTypeRoute, registryv1beta1.ExposureTypeGateway:
+		return false
+	}
+	return IsInPodTLSEnabled(cr)
+}
+
+// GetServingHostnames returns the hostnames the serving certificate of the DevfileRegistry is issued for: the DNS
+// names of its service, and the hostname it's exposed under when the operator knows it upfront
+func GetServingHostnames(cr *registryv1beta1.DevfileRegistry) []string {
+	var hosts []string
+	switch GetExposureType(cr) {
+	case registryv1beta1.ExposureTypeIngress:
+		hosts = append(hosts, GetDevfileRegistryIngress(cr))
+	case registryv1beta1.ExposureTypeGateway:
+		hosts = append(hosts, cr.Spec.Exposure.Gateway.Hostname)
+	}
+	// The devfile index server reaches the OCI registry on localhost
+	if IsInPodTLSEnabled(cr) {
+		hosts = append(hosts, "localhost")
+	}
+	svc := ServiceName(cr.Name)
+	return append(hosts,
+		svc,
+		svc+"."+cr.Namespace,
+		svc+"."+cr.Namespace+".svc",
+		svc+"."+cr.Namespace+".svc.cluster.local",
+	)
+}
+
+// GenerateCASecret returns the secret holding the CA generated for the DevfileRegistry. Its data is filled in by IssueCA.
+func GenerateCASecret(cr *registryv1beta1.DevfileRegistry, scheme *runtime.Scheme, labels map[string]string) *corev1.Secret {
+	return generateTLSSecret(cr, CASecretName(cr.Name), scheme, labels)
+}
+
+// GenerateServingSecret returns the secret holding the serving certificate generated for the DevfileRegistry. Its
+// data is filled in by IssueServingCertificate.
+func GenerateServingSecret(cr *registryv1beta1.DevfileRegistry, scheme *runtime.Scheme, labels map[string]string) *corev1.Secret {
+	return generateTLSSecret(cr, CertificateSecretName(cr.Name), scheme, labels)
+}
+
+func generateTLSSecret(cr *registryv1beta1.DevfileRegistry, name string, scheme *runtime.Scheme, labels map[string]string) *corev1.Secret {
+	secret := &corev1.Secret{
+		ObjectMeta: generateObjectMeta(name, cr.Namespace, labels),
+		Type:       corev1.SecretTypeTLS,
+	}
+
+	// Set DevfileRegistry instance as the owner and controller
+	ctrl.SetControllerReference(cr, secret, scheme)
+	return secret
+}
+
+// IssueCA returns the data of the CA secret, and whether it changed. A new CA is generated when the existing secret
+// doesn't hold one, or when it's due for renewal, in which case the old CA is kept as the previous one.
+func IssueCA(cr *registryv1beta1.DevfileRegistry, existing *corev1.Secret, now time.Time) (map[string][]byte, bool, error) {
+	var previous []byte
+	if existing != nil {
+		caCert, err := util.ParseCertificatePEM(existing.Data[corev1.TLSCertKey])
+		if err == nil && !util.NeedsRenewal(caCert, now) {
+			return existing.Data, false, nil
+		}
+		if err == nil && now.Before(caCert.NotAfter) {
+			previous = existing.Data[corev1.TLSCertKey]
+		}
+	}
+
+	certPEM, keyPEM, err := util.GenerateCA(fmt.Sprintf("%s/%s devfile registry CA", cr.Namespace, cr.Name), now)
+	if err != nil {
+		return nil, false, err
+	}
+	data := map[string][]byte{
+		corev1.TLSCertKey:       certPEM,
+		corev1.TLSPrivateKeyKey: keyPEM,
+	}
+	if previous != nil {
+		data[previousCAKey] = previous
+	}
+	return data, true, nil
+}
+
+// IssueServingCertificate returns the data of the serving certificate secret, and whether it changed. A new
+// certificate is issued when the existing one is due for renewal, wasn't issued for hosts or wasn't signed by the CA.
+func IssueServingCertificate(existing *corev1.Secret, ca *corev1.Secret, hosts []string, now time.Time) (map[string][]byte, bool, error) {
+	caCert, err := util.ParseCertificatePEM(ca.Data[corev1.TLSCertKey])
+	if err != nil {
+		return nil, false, err
+	}
+	if existing != nil {
+		cert, err := util.ParseCertificatePEM(existing.Data[corev1.TLSCertKey])
+		if err == nil && !util.NeedsRenewal(cert, now) && equalHosts(cert.DNSNames, hosts) && cert.CheckSignatureFrom(caCert) == nil {
+			return existing.Data, false, nil
+		}
+	}
+
+	certPEM, keyPEM, err := util.GenerateServingCertificate(ca.Data[corev1.TLSCertKey], ca.Data[corev1.TLSPrivateKeyKey], hosts, now)
+	if err != nil {
+		return nil, false, err
+	}
+	return map[string][]byte{
+		corev1.TLSCertKey:       certPEM,
+		corev1.TLSPrivateKeyKey: keyPEM,
+		TLSSecretCAKey:          ca.Data[corev1.TLSCertKey],
+	}, true, nil
+}
+
+// GenerateCABundle returns the config map publishing the certificates of the CA in the given secret, along with the
+// CA it replaced
+func GenerateCABundle(cr *registryv1beta1.DevfileRegistry, ca *corev1.Secret, scheme *runtime.Scheme, labels map[string]string) *corev1.ConfigMap {
+	bundle := bytes.Join([][]byte{ca.Data[corev1.TLSCertKey], ca.Data[previousCAKey]}, nil)
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: generateObjectMeta(CABundleName(cr.Name), cr.Namespace, labels),
+		Data: map[string]string{
+			CABundleKey: string(bundle),
+		},
+	}
+
+	// Set DevfileRegistry instance as the owner and controller
+	ctrl.SetControllerReference(cr, configMap, scheme)
+	return configMap
+}
+
+func equalHosts(a []string, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,195 @@
+//
+// Copyright (c) 2020 Red Hat, Inc.
+// This program and the accompanying materials are made
+// available under the terms of the Eclipse Public License 2.0
+// which is available at https://www.eclipse.org/legal/epl-2.0/
+//
+// SPDX-License-Identifier: EPL-2.0
+//
+// Contributors:
+//   Red Hat, Inc. - initial API and implementation
+
+package registry
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	registryv1beta1 "github.com/devfile/registry-operator/api/v1beta1"
+	"github.com/devfile/registry-operator/pkg/util"
+)
+
+func TestIssueCA(t *testing.T) {
+	cr := &registryv1beta1.DevfileRegistry{}
+	now := time.Now()
+	data, _, err := IssueCA(cr, nil, now)
+	if err != nil {
+		t.Fatalf("TestIssueCA error: unexpected error issuing the CA: %v", err)
+	}
+	existing := &corev1.Secret{Data: data}
+
+	tests := []struct {
+		name         string
+		existing     *corev1.Secret
+		now          time.Time
+		wantChanged  bool
+		wantPrevious bool
+	}{
+		{
+			name:        "Case 1: No CA yet",
+			now:         now,
+			wantChanged: true,
+		},
+		{
+			name:        "Case 2: Valid CA",
+			existing:    existing,
+			now:         now.Add(util.CAValidity / 2),
+			wantChanged: false,
+		},
+		{
+			name:         "Case 3: CA due for renewal",
+			existing:     existing,
+			now:          now.Add(util.CAValidity * 3 / 4),
+			wantChanged:  true,
+			wantPrevious: true,
+		},
+		{
+			name:        "Case 4: Expired CA",
+			existing:    existing,
+			now:         now.Add(util.CAValidity + time.Hour),
+			wantChanged: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, changed, err := IssueCA(cr, tt.existing, tt.now)
+			if err != nil {
+				t.Fatalf("TestIssueCA error: unexpected error issuing the CA: %v", err)
+			}
+			if changed != tt.wantChanged {
+				t.Errorf("TestIssueCA error: changed mismatch, expected: %v got: %v", tt.wantChanged, changed)
+			}
+			previous := data[previousCAKey] != nil
+			if previous != tt.wantPrevious {
+				t.Errorf("TestIssueCA error: previous CA mismatch, expected: %v got: %v", tt.wantPrevious, previous)
+			}
+		})
+	}
+}
+
+func TestIssueServingCertificate(t *testing.T) {
+	now := time.Now()
+	caData, _, err := IssueCA(&registryv1beta1.DevfileRegistry{}, nil, now)
+	if err != nil {
+		t.Fatalf("TestIssueServingCertificate error: unexpected error issuing the CA: %v", err)
+	}
+	ca := &corev1.Secret{Data: caData}
+	otherCAData, _, err := IssueCA(&registryv1beta1.DevfileRegistry{}, nil, now)
+	if err != nil {
+		t.Fatalf("TestIssueServingCertificate error: unexpected error issuing the CA: %v", err)
+	}
+	hosts := []string{"registry.example.com", "registry"}
+	data, _, err := IssueServingCertificate(nil, ca, hosts, now)
+	if err != nil {
+		t.Fatalf("TestIssueServingCertificate error: unexpected error issuing the certificate: %v", err)
+	}
+	existing := &corev1.Secret{Data: data}
+
+	tests := []struct {
+		name        string
+		ca          *corev1.Secret
+		hosts       []string
+		now         time.Time
+		wantChanged bool
+	}{
+		{
+			name:        "Case 1: Valid certificate",
+			ca:          ca,
+			hosts:       hosts,
+			now:         now,
+			wantChanged: false,
+		},
+		{
+			name:        "Case 2: Hostname changed",
+			ca:          ca,
+			hosts:       []string{"registry.example.org", "registry"},
+			now:         now,
+			wantChanged: true,
+		},
+		{
+			name:        "Case 3: CA rotated",
+			ca:          &corev1.Secret{Data: otherCAData},
+			hosts:       hosts,
+			now:         now,
+			wantChanged: true,
+		},
+		{
+			name:        "Case 4: Certificate due for renewal",
+			ca:          ca,
+			hosts:       hosts,
+			now:         now.Add(util.ServingCertificateValidity * 3 / 4),
+			wantChanged: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, changed, err := IssueServingCertificate(existing, tt.ca, tt.hosts, tt.now)
+			if err != nil {
+				t.Fatalf("TestIssueServingCertificate error: unexpected error issuing the certificate: %v", err)
+			}
+			if changed != tt.wantChanged {
+				t.Errorf("TestIssueServingCertificate error: changed mismatch, expected: %v got: %v", tt.wantChanged, changed)
+			}
+			if !changed && !bytes.Equal(data[corev1.TLSCertKey], existing.Data[corev1.TLSCertKey]) {
+				t.Errorf("TestIssueServingCertificate error: certificate reissued without reporting a change")
+			}
+		})
+	}
+}
+
+func TestIsSelfSignedCertificateServed(t *testing.T) {
+	tests := []struct {
+		name string
+		tls  registryv1beta1.DevfileRegistryTLS
+		typ  registryv1beta1.DevfileRegistryExposureType
+		want bool
+	}{
+		{
+			name: "Case 1: Certificate from a secret",
+			tls:  registryv1beta1.DevfileRegistryTLS{SecretName: "registry-cert"},
+			typ:  registryv1beta1.ExposureTypeIngress,
+			want: false,
+		},
+		{
+			name: "Case 2: Self-signed certificate served by the ingress",
+			tls:  registryv1beta1.DevfileRegistryTLS{SelfSigned: true},
+			typ:  registryv1beta1.ExposureTypeIngress,
+			want: true,
+		},
+		{
+			name: "Case 3: Router presenting its own certificate",
+			tls:  registryv1beta1.DevfileRegistryTLS{SelfSigned: true, InPod: true},
+			typ:  registryv1beta1.ExposureTypeRoute,
+			want: false,
+		},
+		{
+			name: "Case 4: Self-signed certificate served in the pod",
+			tls:  registryv1beta1.DevfileRegistryTLS{SelfSigned: true, InPod: true},
+			typ:  registryv1beta1.ExposureTypeNodePort,
+			want: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cr := &registryv1beta1.DevfileRegistry{}
+			cr.Spec.TLS = tt.tls
+			cr.Spec.Exposure.Type = tt.typ
+			if got := IsSelfSignedCertificateServed(cr); got != tt.want {
+				t.Errorf("TestIsSelfSignedCertificateServed error: expected: %v got: %v", tt.want, got)
+			}
+		})
+	}
+}
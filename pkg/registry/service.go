@@ -16,11 +16,11 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
 
-	registryv1alpha1 "github.com/devfile/registry-operator/api/v1alpha1"
+	registryv1beta1 "github.com/devfile/registry-operator/api/v1beta1"
 )
 
 // GenerateDevfileRegistryService returns a devfileregistry Service object
-func GenerateService(cr *registryv1alpha1.DevfileRegistry, scheme *runtime.Scheme, labels map[string]string) *corev1.Service {
+func GenerateService(cr *registryv1beta1.DevfileRegistry, scheme *runtime.Scheme, labels map[string]string) *corev1.Service {
 	svc := &corev1.Service{
 		ObjectMeta: generateObjectMeta(ServiceName(cr.Name), cr.Namespace, labels),
 		Spec: corev1.ServiceSpec{
@@ -35,6 +35,7 @@ func GenerateService(cr *registryv1alpha1.DevfileRegistry, scheme *runtime.Schem
 				},
 			},
 			Selector: labels,
+			Type:     GetServiceType(cr),
 		},
 	}
 
@@ -0,0 +1,61 @@
+//
+// Copyright (c) 2020 Red Hat, Inc.
+// This program and the accompanying materials are made
+// available under the terms of the Eclipse Public License 2.0
+// which is available at https://www.eclipse.org/legal/epl-2.0/
+//
+// SPDX-License-Identifier: EPL-2.0
+//
+// Contributors:
+//   Red Hat, Inc. - initial API and implementation
+
+package registry
+
+import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	registryv1beta1 "github.com/devfile/registry-operator/api/v1beta1"
+	"github.com/devfile/registry-operator/pkg/config"
+)
+
+// VolumeSnapshotGVK() returns the kind of the CSI volume snapshots taken of the registry storage, in the version of the
+// snapshot API served by the cluster. The snapshot API isn't part of the Kubernetes client libraries, so snapshots are
+// handled as unstructured objects.
+func VolumeSnapshotGVK() schema.GroupVersionKind {
+	return schema.GroupVersionKind{Group: "snapshot.storage.k8s.io", Version: config.ControllerCfg.VolumeSnapshotAPIVersion(), Kind: "VolumeSnapshot"}
+}
+
+// SnapshotSourceUIDLabel is set on the volume snapshot of a deleted DevfileRegistry to the UID of the registry
+const SnapshotSourceUIDLabel = "registry.devfile.io/source-uid"
+
+// GenerateVolumeSnapshot returns a volume snapshot of the registry's persistent volume claim. The snapshot isn't owned
+// by the DevfileRegistry, as it has to outlive it.
+func GenerateVolumeSnapshot(cr *registryv1beta1.DevfileRegistry, labels map[string]string) *unstructured.Unstructured {
+	snapshot := &unstructured.Unstructured{}
+	snapshot.SetGroupVersionKind(VolumeSnapshotGVK())
+	snapshot.SetName(VolumeSnapshotName(cr.Name, cr.UID))
+	snapshot.SetNamespace(cr.Namespace)
+	snapshotLabels := map[string]string{SnapshotSourceUIDLabel: string(cr.UID)}
+	for key, value := range labels {
+		snapshotLabels[key] = value
+	}
+	snapshot.SetLabels(snapshotLabels)
+	unstructured.SetNestedField(snapshot.Object, PVCName(cr.Name), "spec", "source", "persistentVolumeClaimName")
+	return snapshot
+}
+
+// IsVolumeSnapshotOf returns true if the volume snapshot was taken of the persistent volume claim of the DevfileRegistry
+// by the operator. Any other snapshot can't be trusted to hold the registry's content.
+func IsVolumeSnapshotOf(snapshot *unstructured.Unstructured, cr *registryv1beta1.DevfileRegistry) bool {
+	source, _, _ := unstructured.NestedString(snapshot.Object, "spec", "source", "persistentVolumeClaimName")
+	return snapshot.GetLabels()[SnapshotSourceUIDLabel] == string(cr.UID) && source == PVCName(cr.Name)
+}
+
+// IsVolumeSnapshotReady returns true once the volume snapshot can be used to restore a volume. If the snapshot
+// failed, it returns the error reported by the snapshot controller.
+func IsVolumeSnapshotReady(snapshot *unstructured.Unstructured) (bool, string) {
+	ready, _, _ := unstructured.NestedBool(snapshot.Object, "status", "readyToUse")
+	message, _, _ := unstructured.NestedString(snapshot.Object, "status", "error", "message")
+	return ready, message
+}
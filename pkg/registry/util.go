@@ -11,7 +11,10 @@
 
 package registry
 
-import metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
 
 func generateObjectMeta(name string, namespace string, labels map[string]string) metav1.ObjectMeta {
 	return metav1.ObjectMeta{
@@ -26,3 +29,16 @@ func generateObjectMeta(name string, namespace string, labels map[string]string)
 func LabelsForDevfileRegistry(name string) map[string]string {
 	return map[string]string{"app": "devfileregistry", "devfileregistry_cr": name}
 }
+
+// secretKeyEnv returns an environment variable set to a key of a secret
+func secretKeyEnv(name string, secretName string, key string) corev1.EnvVar {
+	return corev1.EnvVar{
+		Name: name,
+		ValueFrom: &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: secretName},
+				Key:                  key,
+			},
+		},
+	}
+}
@@ -0,0 +1,347 @@
+//
+// Copyright (c) 2020 Red Hat, Inc.
+// This program and the accompanying materials are made
+// available under the terms of the Eclipse Public License 2.0
+// which is available at https://www.eclipse.org/legal/epl-2.0/
+//
+// SPDX-License-Identifier: EPL-2.0
+//
+// Contributors:
+//   Red Hat, Inc. - initial API and implementation
+
+package registry
+
+import (
+	"net/url"
+	"reflect"
+	"regexp"
+
+	"github.com/robfig/cron/v3"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	apivalidation "k8s.io/apimachinery/pkg/api/validation"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	registryv1beta1 "github.com/devfile/registry-operator/api/v1beta1"
+	"github.com/devfile/registry-operator/pkg/config"
+)
+
+// imageReferenceRegexp matches container image references of the form [domain[:port]/]path[:tag][@digest]. It follows
+// the grammar used by github.com/docker/distribution/reference.
+var imageReferenceRegexp = regexp.MustCompile(`^` +
+	// Optional registry domain and port
+	`(?:(?:[a-zA-Z0-9]|[a-zA-Z0-9][a-zA-Z0-9-]*[a-zA-Z0-9])(?:\.(?:[a-zA-Z0-9]|[a-zA-Z0-9][a-zA-Z0-9-]*[a-zA-Z0-9]))*(?::[0-9]+)?/)?` +
+	// Repository path
+	`[a-z0-9]+(?:(?:[._]|__|[-]*)[a-z0-9]+)*(?:/[a-z0-9]+(?:(?:[._]|__|[-]*)[a-z0-9]+)*)*` +
+	// Optional tag
+	`(?::[\w][\w.-]{0,127})?` +
+	// Optional digest
+	`(?:@[A-Za-z][A-Za-z0-9]*(?:[-_+.][A-Za-z][A-Za-z0-9]*)*:[0-9a-fA-F]{32,})?` +
+	`$`)
+
+// ValidateDevfileRegistry checks the DevfileRegistry spec for values the operator can't deploy
+func ValidateDevfileRegistry(cr *registryv1beta1.DevfileRegistry) field.ErrorList {
+	var allErrs field.ErrorList
+	specPath := field.NewPath("spec")
+
+	allErrs = append(allErrs, validateImage(specPath.Child("devfileIndex", "image"), cr.Spec.DevfileIndex.Image)...)
+	allErrs = append(allErrs, validateImage(specPath.Child("ociRegistry", "image"), cr.Spec.OCIRegistry.Image)...)
+
+	if cr.Spec.Storage.Size != "" {
+		sizePath := specPath.Child("storage", "size")
+		size, err := resource.ParseQuantity(cr.Spec.Storage.Size)
+		if err != nil {
+			allErrs = append(allErrs, field.Invalid(sizePath, cr.Spec.Storage.Size, err.Error()))
+		} else if size.Sign() <= 0 {
+			allErrs = append(allErrs, field.Invalid(sizePath, cr.Spec.Storage.Size, "must be greater than zero"))
+		}
+	}
+
+	for i, mode := range cr.Spec.Storage.AccessModes {
+		if mode != corev1.ReadWriteOnce && mode != corev1.ReadWriteMany {
+			allErrs = append(allErrs, field.NotSupported(specPath.Child("storage", "accessModes").Index(i), mode,
+				[]string{string(corev1.ReadWriteOnce), string(corev1.ReadWriteMany)}))
+		}
+	}
+	if name := cr.Spec.Storage.StorageClassName; name != nil && *name != "" {
+		for _, msg := range validation.IsDNS1123Subdomain(*name) {
+			allErrs = append(allErrs, field.Invalid(specPath.Child("storage", "storageClassName"), *name, msg))
+		}
+	}
+
+	if restoreFrom := cr.Spec.Storage.RestoreFrom; restoreFrom != "" {
+		restorePath := specPath.Child("storage", "restoreFrom")
+		if !IsPVCEnabled(cr) {
+			allErrs = append(allErrs, field.Invalid(restorePath, restoreFrom, "the registry can only be restored into a persistent volume claim"))
+		}
+		if !config.ControllerCfg.HasVolumeSnapshots() {
+			allErrs = append(allErrs, field.Invalid(restorePath, restoreFrom, "the cluster must serve the VolumeSnapshot API to restore the registry from a snapshot"))
+		}
+	}
+
+	if IsS3Enabled(cr) {
+		allErrs = append(allErrs, validateS3(specPath.Child("storage", "s3"), &cr.Spec.Storage.S3)...)
+	}
+
+	if threshold := cr.Spec.Storage.UsageAlertThreshold; threshold != nil && (*threshold < 1 || *threshold > 100) {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("storage", "usageAlertThreshold"), *threshold, "must be a percentage between 1 and 100"))
+	}
+	if autoExpansion := cr.Spec.Storage.AutoExpansion; autoExpansion != nil {
+		autoExpansionPath := specPath.Child("storage", "autoExpansion")
+		maxSizePath := autoExpansionPath.Child("maxSize")
+		if !IsPVCEnabled(cr) {
+			allErrs = append(allErrs, field.Forbidden(autoExpansionPath, "only persistent volume claims can be expanded"))
+		}
+		maxSize, err := resource.ParseQuantity(autoExpansion.MaxSize)
+		if err != nil {
+			allErrs = append(allErrs, field.Invalid(maxSizePath, autoExpansion.MaxSize, err.Error()))
+		} else if size, err := resource.ParseQuantity(GetDevfileRegistryVolumeSize(cr)); err == nil && maxSize.Cmp(size) < 0 {
+			allErrs = append(allErrs, field.Invalid(maxSizePath, autoExpansion.MaxSize, "must be at least the size of the registry volume"))
+		}
+	}
+
+	if replicas := cr.Spec.Replicas; replicas != nil {
+		replicasPath := specPath.Child("replicas")
+		if *replicas < 1 {
+			allErrs = append(allErrs, field.Invalid(replicasPath, *replicas, "must be at least 1"))
+		} else if *replicas > 1 && !hasSharedStorage(cr) {
+			allErrs = append(allErrs, field.Invalid(replicasPath, *replicas,
+				"more than one replica needs storage shared by every pod: a persistent volume claim with the ReadWriteMany access mode, or an S3 bucket"))
+		}
+	}
+
+	if interval := cr.Spec.OCIRegistry.Config.StorageHealthCheckInterval; interval != nil && interval.Duration <= 0 {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("ociRegistry", "config", "storageHealthCheckInterval"), interval.Duration.String(), "must be greater than zero"))
+	}
+	if gc := cr.Spec.OCIRegistry.GarbageCollection; gc != nil {
+		gcPath := specPath.Child("ociRegistry", "garbageCollection")
+		if gc.Schedule == "" {
+			allErrs = append(allErrs, field.Required(gcPath.Child("schedule"), "a schedule must be set to collect garbage"))
+		} else if _, err := cron.ParseStandard(gc.Schedule); err != nil {
+			allErrs = append(allErrs, field.Invalid(gcPath.Child("schedule"), gc.Schedule, err.Error()))
+		}
+		// The garbage collection job can't reach the ephemeral storage of the registry pods
+		if !IsPVCEnabled(cr) && !IsS3Enabled(cr) {
+			allErrs = append(allErrs, field.Forbidden(gcPath, "garbage can only be collected from persistent storage"))
+		}
+	}
+
+	exposurePath := specPath.Child("exposure")
+	ingressPath := exposurePath.Child("ingress")
+	gatewayPath := exposurePath.Child("gateway")
+	switch GetExposureType(cr) {
+	case registryv1beta1.ExposureTypeRoute:
+		if !config.ControllerCfg.IsOpenShift() {
+			allErrs = append(allErrs, field.NotSupported(exposurePath.Child("type"), cr.Spec.Exposure.Type, supportedExposureTypes()))
+		}
+	case registryv1beta1.ExposureTypeIngress:
+		// Routes get their hostname from the OpenShift router, but Kubernetes ingresses need a domain to build it from
+		if cr.Spec.Exposure.Ingress.Domain == "" {
+			allErrs = append(allErrs, field.Required(ingressPath.Child("domain"), "an ingress domain must be set to expose the registry through an Ingress"))
+		}
+	case registryv1beta1.ExposureTypeGateway:
+		if config.ControllerCfg.GatewayAPIVersion() == "" {
+			allErrs = append(allErrs, field.NotSupported(exposurePath.Child("type"), cr.Spec.Exposure.Type, supportedExposureTypes()))
+		}
+		if cr.Spec.Exposure.Gateway.Name == "" {
+			allErrs = append(allErrs, field.Required(gatewayPath.Child("name"), "a gateway must be set to expose the registry through an HTTPRoute"))
+		}
+		// Gateways don't generate hostnames, and the registry URL is built from it
+		if cr.Spec.Exposure.Gateway.Hostname == "" {
+			allErrs = append(allErrs, field.Required(gatewayPath.Child("hostname"), "a hostname must be set to expose the registry through an HTTPRoute"))
+		}
+	}
+	if className := cr.Spec.Exposure.Ingress.ClassName; className != "" {
+		for _, msg := range validation.IsDNS1123Subdomain(className) {
+			allErrs = append(allErrs, field.Invalid(ingressPath.Child("className"), className, msg))
+		}
+	}
+	allErrs = append(allErrs, apivalidation.ValidateAnnotations(cr.Spec.Exposure.Ingress.Annotations, ingressPath.Child("annotations"))...)
+
+	if issuer := cr.Spec.TLS.Issuer; issuer.Name != "" {
+		issuerPath := specPath.Child("tls", "issuer")
+		if cr.Spec.TLS.SecretName != "" {
+			allErrs = append(allErrs, field.Forbidden(issuerPath, "an issuer cannot be set along with tls.secretName"))
+		}
+		if !config.ControllerCfg.HasCertManager() {
+			allErrs = append(allErrs, field.Invalid(issuerPath.Child("name"), issuer.Name, "cert-manager must be installed on the cluster to request a certificate"))
+		}
+	}
+	if GetTLSTermination(cr) == registryv1beta1.TLSTerminationPassthrough && cr.Spec.TLS.InsecureEdgeTerminationPolicy == registryv1beta1.InsecureEdgeTerminationPolicyAllow {
+		allErrs = append(allErrs, field.NotSupported(specPath.Child("tls", "insecureEdgeTerminationPolicy"), cr.Spec.TLS.InsecureEdgeTerminationPolicy,
+			[]string{string(registryv1beta1.InsecureEdgeTerminationPolicyNone), string(registryv1beta1.InsecureEdgeTerminationPolicyRedirect)}))
+	}
+	if IsInPodTLSEnabled(cr) {
+		inPodPath := specPath.Child("tls", "inPod")
+		if GetTLSSecretName(cr) == "" {
+			allErrs = append(allErrs, field.Invalid(inPodPath, true, "the registry needs a certificate from tls.secretName, tls.issuer or tls.selfSigned to serve TLS"))
+		}
+		if GetExposureType(cr) == registryv1beta1.ExposureTypeGateway {
+			allErrs = append(allErrs, field.Invalid(inPodPath, true, "TLS can't be served by the registry when it's exposed through a Gateway"))
+		}
+		if GetExposureType(cr) == registryv1beta1.ExposureTypeRoute && GetTLSTermination(cr) == registryv1beta1.TLSTerminationEdge {
+			allErrs = append(allErrs, field.Invalid(specPath.Child("tls", "termination"), cr.Spec.TLS.Termination, "edge routes can't reach a registry serving TLS"))
+		}
+	} else if IsTLSEnabled(cr) && GetTLSTermination(cr) == registryv1beta1.TLSTerminationPassthrough {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("tls", "termination"), cr.Spec.TLS.Termination, "passthrough routes need the registry to serve TLS, with tls.inPod"))
+	}
+	if cr.Spec.TLS.SelfSigned && (cr.Spec.TLS.SecretName != "" || cr.Spec.TLS.Issuer.Name != "") {
+		allErrs = append(allErrs, field.Forbidden(specPath.Child("tls", "selfSigned"), "a self-signed certificate cannot be used along with tls.secretName or tls.issuer"))
+	}
+	if IsSelfSigned(cr) {
+		selfSignedPath := specPath.Child("tls", "selfSigned")
+		switch GetExposureType(cr) {
+		case registryv1beta1.ExposureTypeGateway:
+			allErrs = append(allErrs, field.Forbidden(selfSignedPath, "a Gateway presents its own certificate, it can't serve a self-signed certificate"))
+		case registryv1beta1.ExposureTypeRoute:
+			// The generated certificate isn't issued for the route hostname, so it can only secure the router's
+			// connection to the registry
+			if !IsInPodTLSEnabled(cr) || GetTLSTermination(cr) != registryv1beta1.TLSTerminationReencrypt {
+				allErrs = append(allErrs, field.Forbidden(selfSignedPath, "routes present the router's certificate, a self-signed certificate can only be served by the registry to re-encrypting routes, with tls.inPod"))
+			}
+		}
+	}
+
+	return allErrs
+}
+
+// ValidateDevfileRegistryUpdate checks that the changes made to a DevfileRegistry can be applied to its existing
+// resources, in addition to the checks done by ValidateDevfileRegistry
+func ValidateDevfileRegistryUpdate(oldCR *registryv1beta1.DevfileRegistry, newCR *registryv1beta1.DevfileRegistry) field.ErrorList {
+	// A DevfileRegistry being deleted is only updated to remove its finalizer, which mustn't be blocked by a spec
+	// that became invalid in the meantime
+	if newCR.DeletionTimestamp != nil {
+		return nil
+	}
+
+	allErrs := ValidateDevfileRegistry(newCR)
+
+	// Persistent volume claims can only be expanded, so don't allow the volume size to be reduced
+	if IsPVCEnabled(oldCR) && IsPVCEnabled(newCR) {
+		oldSize, oldErr := resource.ParseQuantity(GetDevfileRegistryVolumeSize(oldCR))
+		newSize, newErr := resource.ParseQuantity(GetDevfileRegistryVolumeSize(newCR))
+		if oldErr == nil && newErr == nil && newSize.Cmp(oldSize) < 0 {
+			allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "storage", "size"),
+				"the registry volume cannot be shrunk from "+oldSize.String()+" to "+newSize.String()))
+		}
+		// The storage class and access modes of a claim are immutable
+		if !reflect.DeepEqual(oldCR.Spec.Storage.StorageClassName, newCR.Spec.Storage.StorageClassName) {
+			allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "storage", "storageClassName"),
+				"the storage class of the registry volume cannot be changed"))
+		}
+		if !reflect.DeepEqual(GetAccessModes(oldCR), GetAccessModes(newCR)) {
+			allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "storage", "accessModes"),
+				"the access modes of the registry volume cannot be changed"))
+		}
+	}
+
+	return allErrs
+}
+
+// ValidateDevfileRegistryBackup checks the DevfileRegistryBackup spec for values the operator can't take backups with
+func ValidateDevfileRegistryBackup(backup *registryv1beta1.DevfileRegistryBackup) field.ErrorList {
+	var allErrs field.ErrorList
+	specPath := field.NewPath("spec")
+
+	if backup.Spec.RegistryName == "" {
+		allErrs = append(allErrs, field.Required(specPath.Child("registryName"), "the DevfileRegistry to back up must be set"))
+	}
+	if backup.Spec.Schedule != "" {
+		if _, err := cron.ParseStandard(backup.Spec.Schedule); err != nil {
+			allErrs = append(allErrs, field.Invalid(specPath.Child("schedule"), backup.Spec.Schedule, err.Error()))
+		}
+	}
+	if retain := backup.Spec.Retain; retain != nil && *retain < 1 {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("retain"), *retain, "must be at least 1"))
+	}
+	return allErrs
+}
+
+// ValidateDevfileRegistryExport checks the DevfileRegistryExport spec for values the operator can't run the archive
+// job with
+func ValidateDevfileRegistryExport(export *registryv1beta1.DevfileRegistryExport) field.ErrorList {
+	var allErrs field.ErrorList
+	specPath := field.NewPath("spec")
+	archivePath := specPath.Child("archive")
+
+	if export.Spec.RegistryName == "" {
+		allErrs = append(allErrs, field.Required(specPath.Child("registryName"), "the DevfileRegistry to copy the stacks of must be set"))
+	}
+	if fileName := export.Spec.Archive.FileName; fileName != "" {
+		for _, msg := range validation.IsConfigMapKey(fileName) {
+			allErrs = append(allErrs, field.Invalid(archivePath.Child("fileName"), fileName, msg))
+		}
+	}
+	switch archive := export.Spec.Archive; {
+	case archive.PersistentVolumeClaim != nil && archive.S3 != nil:
+		allErrs = append(allErrs, field.Forbidden(archivePath, "only one of persistentVolumeClaim and s3 can be set"))
+	case archive.PersistentVolumeClaim != nil:
+		if archive.PersistentVolumeClaim.ClaimName == "" {
+			allErrs = append(allErrs, field.Required(archivePath.Child("persistentVolumeClaim", "claimName"), "the persistent volume claim storing the archive must be set"))
+		}
+	case archive.S3 != nil:
+		allErrs = append(allErrs, validateS3(archivePath.Child("s3"), archive.S3)...)
+	default:
+		allErrs = append(allErrs, field.Required(archivePath, "one of persistentVolumeClaim and s3 must be set"))
+	}
+	return allErrs
+}
+
+// supportedExposureTypes returns the exposure types that can be used on the cluster
+func supportedExposureTypes() []string {
+	supported := []string{
+		string(registryv1beta1.ExposureTypeNone),
+		string(registryv1beta1.ExposureTypeNodePort),
+		string(registryv1beta1.ExposureTypeLoadBalancer),
+		string(registryv1beta1.ExposureTypeIngress),
+	}
+	if config.ControllerCfg.IsOpenShift() {
+		supported = append(supported, string(registryv1beta1.ExposureTypeRoute))
+	}
+	if config.ControllerCfg.GatewayAPIVersion() != "" {
+		supported = append(supported, string(registryv1beta1.ExposureTypeGateway))
+	}
+	return supported
+}
+
+// validateImage checks that an optional image field holds a valid image reference
+func validateImage(path *field.Path, image string) field.ErrorList {
+	if image != "" && !imageReferenceRegexp.MatchString(image) {
+		return field.ErrorList{field.Invalid(path, image, "must be a valid container image reference")}
+	}
+	return nil
+}
+
+// hasSharedStorage returns true if every pod of the registry can mount its storage at the same time
+func hasSharedStorage(cr *registryv1beta1.DevfileRegistry) bool {
+	if IsS3Enabled(cr) {
+		return true
+	}
+	if !IsPVCEnabled(cr) {
+		return false
+	}
+	for _, mode := range GetAccessModes(cr) {
+		if mode == corev1.ReadWriteMany {
+			return true
+		}
+	}
+	return false
+}
+
+// validateS3 checks that an S3 bucket can be reached
+func validateS3(path *field.Path, s3 *registryv1beta1.DevfileRegistryS3Storage) field.ErrorList {
+	var allErrs field.ErrorList
+	if s3.Bucket == "" {
+		allErrs = append(allErrs, field.Required(path.Child("bucket"), "a bucket must be set"))
+	}
+	if s3.CredentialsSecret == "" {
+		allErrs = append(allErrs, field.Required(path.Child("credentialsSecret"), "a credentials secret must be set to access the bucket"))
+	}
+	if endpoint := s3.Endpoint; endpoint != "" {
+		if u, err := url.Parse(endpoint); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			allErrs = append(allErrs, field.Invalid(path.Child("endpoint"), endpoint, "must be an http or https URL"))
+		}
+	}
+	return allErrs
+}
@@ -0,0 +1,870 @@
+//
+// Copyright (c) 2020 Red Hat, Inc.
+// This program and the accompanying materials are made
+// available under the terms of the Eclipse Public License 2.0
+// which is available at https://www.eclipse.org/legal/epl-2.0/
+//
+// SPDX-License-Identifier: EPL-2.0
+//
+// Contributors:
+//   Red Hat, Inc. - initial API and implementation
+
+package registry
+
+import (
+	"testing"
+
+	registryv1beta1 "github.com/devfile/registry-operator/api/v1beta1"
+	"github.com/devfile/registry-operator/pkg/config"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestValidateDevfileRegistry(t *testing.T) {
+	storageDisabled := false
+	overThreshold := int32(120)
+	replicas := int32(3)
+
+	tests := []struct {
+		name                     string
+		isOpenShift              bool
+		gatewayAPIVersion        string
+		hasCertManager           bool
+		volumeSnapshotAPIVersion string
+		spec                     registryv1beta1.DevfileRegistrySpec
+		wantErr                  bool
+	}{
+		{
+			name: "Case 1: Valid DevfileRegistry on Kubernetes",
+			spec: registryv1beta1.DevfileRegistrySpec{
+				DevfileIndex: registryv1beta1.DevfileRegistryDevfileIndex{
+					Image: "quay.io/devfile/devfile-index:next",
+				},
+				OCIRegistry: registryv1beta1.DevfileRegistryOCIRegistry{
+					Image: "registry:2.7.1",
+				},
+				Storage: registryv1beta1.DevfileRegistryStorage{
+					Size: "5Gi",
+				},
+				Exposure: registryv1beta1.DevfileRegistryExposure{
+					Ingress: registryv1beta1.DevfileRegistryIngress{
+						Domain: "example.com",
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:        "Case 2: Missing ingress domain on OpenShift",
+			isOpenShift: true,
+			spec:        registryv1beta1.DevfileRegistrySpec{},
+			wantErr:     false,
+		},
+		{
+			name:    "Case 3: Missing ingress domain on Kubernetes",
+			spec:    registryv1beta1.DevfileRegistrySpec{},
+			wantErr: true,
+		},
+		{
+			name: "Case 4: Unparsable volume size",
+			spec: registryv1beta1.DevfileRegistrySpec{
+				Storage: registryv1beta1.DevfileRegistryStorage{
+					Size: "5 gigabytes",
+				},
+				Exposure: registryv1beta1.DevfileRegistryExposure{
+					Ingress: registryv1beta1.DevfileRegistryIngress{
+						Domain: "example.com",
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Case 5: Zero volume size",
+			spec: registryv1beta1.DevfileRegistrySpec{
+				Storage: registryv1beta1.DevfileRegistryStorage{
+					Size: "0",
+				},
+				Exposure: registryv1beta1.DevfileRegistryExposure{
+					Ingress: registryv1beta1.DevfileRegistryIngress{
+						Domain: "example.com",
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Case 6: Image referenced by digest",
+			spec: registryv1beta1.DevfileRegistrySpec{
+				DevfileIndex: registryv1beta1.DevfileRegistryDevfileIndex{
+					Image: "localhost:5000/devfile/index@sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef",
+				},
+				Exposure: registryv1beta1.DevfileRegistryExposure{
+					Ingress: registryv1beta1.DevfileRegistryIngress{
+						Domain: "example.com",
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Case 7: Malformed devfile index image",
+			spec: registryv1beta1.DevfileRegistrySpec{
+				DevfileIndex: registryv1beta1.DevfileRegistryDevfileIndex{
+					Image: "quay.io/Devfile/index:next",
+				},
+				Exposure: registryv1beta1.DevfileRegistryExposure{
+					Ingress: registryv1beta1.DevfileRegistryIngress{
+						Domain: "example.com",
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Case 8: Malformed OCI registry image",
+			spec: registryv1beta1.DevfileRegistrySpec{
+				OCIRegistry: registryv1beta1.DevfileRegistryOCIRegistry{
+					Image: "registry:2.7.1:latest",
+				},
+				Exposure: registryv1beta1.DevfileRegistryExposure{
+					Ingress: registryv1beta1.DevfileRegistryIngress{
+						Domain: "example.com",
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Case 9: Ingress class and annotations",
+			spec: registryv1beta1.DevfileRegistrySpec{
+				Exposure: registryv1beta1.DevfileRegistryExposure{
+					Ingress: registryv1beta1.DevfileRegistryIngress{
+						Domain:      "example.com",
+						ClassName:   "nginx",
+						Annotations: map[string]string{"nginx.ingress.kubernetes.io/proxy-body-size": "0"},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Case 10: Malformed ingress class",
+			spec: registryv1beta1.DevfileRegistrySpec{
+				Exposure: registryv1beta1.DevfileRegistryExposure{
+					Ingress: registryv1beta1.DevfileRegistryIngress{
+						Domain:    "example.com",
+						ClassName: "Nginx_Class",
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Case 11: Malformed ingress annotation",
+			spec: registryv1beta1.DevfileRegistrySpec{
+				Exposure: registryv1beta1.DevfileRegistryExposure{
+					Ingress: registryv1beta1.DevfileRegistryIngress{
+						Domain:      "example.com",
+						Annotations: map[string]string{"not a key": "value"},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Case 12: Route exposure on Kubernetes",
+			spec: registryv1beta1.DevfileRegistrySpec{
+				Exposure: registryv1beta1.DevfileRegistryExposure{
+					Type: registryv1beta1.ExposureTypeRoute,
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name:              "Case 13: Gateway exposure",
+			gatewayAPIVersion: "v1",
+			spec: registryv1beta1.DevfileRegistrySpec{
+				Exposure: registryv1beta1.DevfileRegistryExposure{
+					Type: registryv1beta1.ExposureTypeGateway,
+					Gateway: registryv1beta1.DevfileRegistryGateway{
+						Name:     "gateway",
+						Hostname: "registry.example.com",
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:              "Case 14: Gateway exposure without a hostname",
+			gatewayAPIVersion: "v1",
+			spec: registryv1beta1.DevfileRegistrySpec{
+				Exposure: registryv1beta1.DevfileRegistryExposure{
+					Type: registryv1beta1.ExposureTypeGateway,
+					Gateway: registryv1beta1.DevfileRegistryGateway{
+						Name: "gateway",
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Case 15: Gateway exposure on a cluster without the Gateway API",
+			spec: registryv1beta1.DevfileRegistrySpec{
+				Exposure: registryv1beta1.DevfileRegistryExposure{
+					Type: registryv1beta1.ExposureTypeGateway,
+					Gateway: registryv1beta1.DevfileRegistryGateway{
+						Name:     "gateway",
+						Hostname: "registry.example.com",
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Case 16: NodePort exposure without an ingress domain",
+			spec: registryv1beta1.DevfileRegistrySpec{
+				Exposure: registryv1beta1.DevfileRegistryExposure{
+					Type: registryv1beta1.ExposureTypeNodePort,
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:           "Case 17: Certificate issuer",
+			hasCertManager: true,
+			spec: registryv1beta1.DevfileRegistrySpec{
+				TLS: registryv1beta1.DevfileRegistryTLS{
+					Issuer: registryv1beta1.DevfileRegistryTLSIssuer{Name: "letsencrypt", Kind: "ClusterIssuer"},
+				},
+				Exposure: registryv1beta1.DevfileRegistryExposure{
+					Ingress: registryv1beta1.DevfileRegistryIngress{
+						Domain: "example.com",
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Case 18: Certificate issuer without cert-manager",
+			spec: registryv1beta1.DevfileRegistrySpec{
+				TLS: registryv1beta1.DevfileRegistryTLS{
+					Issuer: registryv1beta1.DevfileRegistryTLSIssuer{Name: "letsencrypt"},
+				},
+				Exposure: registryv1beta1.DevfileRegistryExposure{
+					Ingress: registryv1beta1.DevfileRegistryIngress{
+						Domain: "example.com",
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name:           "Case 19: Certificate issuer along with a secret",
+			hasCertManager: true,
+			spec: registryv1beta1.DevfileRegistrySpec{
+				TLS: registryv1beta1.DevfileRegistryTLS{
+					SecretName: "registry-cert",
+					Issuer:     registryv1beta1.DevfileRegistryTLSIssuer{Name: "letsencrypt"},
+				},
+				Exposure: registryv1beta1.DevfileRegistryExposure{
+					Ingress: registryv1beta1.DevfileRegistryIngress{
+						Domain: "example.com",
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Case 20: Self-signed certificate along with a secret",
+			spec: registryv1beta1.DevfileRegistrySpec{
+				TLS: registryv1beta1.DevfileRegistryTLS{
+					SecretName: "registry-cert",
+					SelfSigned: true,
+				},
+				Exposure: registryv1beta1.DevfileRegistryExposure{
+					Ingress: registryv1beta1.DevfileRegistryIngress{
+						Domain: "example.com",
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name:        "Case 21: Passthrough routes allowing plain HTTP",
+			isOpenShift: true,
+			spec: registryv1beta1.DevfileRegistrySpec{
+				TLS: registryv1beta1.DevfileRegistryTLS{
+					Termination:                   registryv1beta1.TLSTerminationPassthrough,
+					InsecureEdgeTerminationPolicy: registryv1beta1.InsecureEdgeTerminationPolicyAllow,
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name:        "Case 22: TLS served in the pod behind re-encrypting routes",
+			isOpenShift: true,
+			spec: registryv1beta1.DevfileRegistrySpec{
+				TLS: registryv1beta1.DevfileRegistryTLS{
+					SelfSigned: true,
+					InPod:      true,
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:        "Case 23: TLS served in the pod behind edge routes",
+			isOpenShift: true,
+			spec: registryv1beta1.DevfileRegistrySpec{
+				TLS: registryv1beta1.DevfileRegistryTLS{
+					SelfSigned:  true,
+					InPod:       true,
+					Termination: registryv1beta1.TLSTerminationEdge,
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Case 24: TLS served in the pod without a certificate",
+			spec: registryv1beta1.DevfileRegistrySpec{
+				TLS: registryv1beta1.DevfileRegistryTLS{
+					InPod: true,
+				},
+				Exposure: registryv1beta1.DevfileRegistryExposure{
+					Type: registryv1beta1.ExposureTypeNone,
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Case 25: Unsupported access mode",
+			spec: registryv1beta1.DevfileRegistrySpec{
+				Storage: registryv1beta1.DevfileRegistryStorage{
+					AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadOnlyMany},
+				},
+				Exposure: registryv1beta1.DevfileRegistryExposure{
+					Type: registryv1beta1.ExposureTypeNone,
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Case 26: S3 storage on a MinIO endpoint",
+			spec: registryv1beta1.DevfileRegistrySpec{
+				Storage: registryv1beta1.DevfileRegistryStorage{
+					Type: registryv1beta1.StorageTypeS3,
+					S3: registryv1beta1.DevfileRegistryS3Storage{
+						Bucket:            "devfiles",
+						Endpoint:          "http://minio.minio.svc:9000",
+						CredentialsSecret: "minio-credentials",
+					},
+				},
+				Exposure: registryv1beta1.DevfileRegistryExposure{
+					Type: registryv1beta1.ExposureTypeNone,
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Case 27: S3 storage without a bucket or credentials",
+			spec: registryv1beta1.DevfileRegistrySpec{
+				Storage: registryv1beta1.DevfileRegistryStorage{
+					Type: registryv1beta1.StorageTypeS3,
+				},
+				Exposure: registryv1beta1.DevfileRegistryExposure{
+					Type: registryv1beta1.ExposureTypeNone,
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Case 28: S3 storage with an invalid endpoint",
+			spec: registryv1beta1.DevfileRegistrySpec{
+				Storage: registryv1beta1.DevfileRegistryStorage{
+					Type: registryv1beta1.StorageTypeS3,
+					S3: registryv1beta1.DevfileRegistryS3Storage{
+						Bucket:            "devfiles",
+						Endpoint:          "minio:9000",
+						CredentialsSecret: "minio-credentials",
+					},
+				},
+				Exposure: registryv1beta1.DevfileRegistryExposure{
+					Type: registryv1beta1.ExposureTypeNone,
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name:                     "Case 29: Restore from a volume snapshot",
+			volumeSnapshotAPIVersion: "v1",
+			spec: registryv1beta1.DevfileRegistrySpec{
+				Storage: registryv1beta1.DevfileRegistryStorage{
+					RestoreFrom: "devfileregistry-sample-nightly-20210601020000",
+				},
+				Exposure: registryv1beta1.DevfileRegistryExposure{
+					Type: registryv1beta1.ExposureTypeNone,
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Case 30: Restore from a volume snapshot without the snapshot API",
+			spec: registryv1beta1.DevfileRegistrySpec{
+				Storage: registryv1beta1.DevfileRegistryStorage{
+					RestoreFrom: "devfileregistry-sample-nightly-20210601020000",
+				},
+				Exposure: registryv1beta1.DevfileRegistryExposure{
+					Type: registryv1beta1.ExposureTypeNone,
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Case 31: Scheduled garbage collection",
+			spec: registryv1beta1.DevfileRegistrySpec{
+				OCIRegistry: registryv1beta1.DevfileRegistryOCIRegistry{
+					GarbageCollection: &registryv1beta1.DevfileRegistryGarbageCollection{Schedule: "0 3 * * 0"},
+				},
+				Exposure: registryv1beta1.DevfileRegistryExposure{
+					Type: registryv1beta1.ExposureTypeNone,
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Case 32: Invalid garbage collection schedule",
+			spec: registryv1beta1.DevfileRegistrySpec{
+				OCIRegistry: registryv1beta1.DevfileRegistryOCIRegistry{
+					GarbageCollection: &registryv1beta1.DevfileRegistryGarbageCollection{Schedule: "weekly"},
+				},
+				Exposure: registryv1beta1.DevfileRegistryExposure{
+					Type: registryv1beta1.ExposureTypeNone,
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Case 33: Garbage collection on ephemeral storage",
+			spec: registryv1beta1.DevfileRegistrySpec{
+				OCIRegistry: registryv1beta1.DevfileRegistryOCIRegistry{
+					GarbageCollection: &registryv1beta1.DevfileRegistryGarbageCollection{Schedule: "0 3 * * 0"},
+				},
+				Storage: registryv1beta1.DevfileRegistryStorage{
+					Enabled: &storageDisabled,
+				},
+				Exposure: registryv1beta1.DevfileRegistryExposure{
+					Type: registryv1beta1.ExposureTypeNone,
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Case 34: Storage auto expansion",
+			spec: registryv1beta1.DevfileRegistrySpec{
+				Storage: registryv1beta1.DevfileRegistryStorage{
+					Size:          "5Gi",
+					AutoExpansion: &registryv1beta1.DevfileRegistryStorageAutoExpansion{MaxSize: "20Gi"},
+				},
+				Exposure: registryv1beta1.DevfileRegistryExposure{
+					Type: registryv1beta1.ExposureTypeNone,
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Case 35: Auto expansion limit below the volume size",
+			spec: registryv1beta1.DevfileRegistrySpec{
+				Storage: registryv1beta1.DevfileRegistryStorage{
+					Size:          "5Gi",
+					AutoExpansion: &registryv1beta1.DevfileRegistryStorageAutoExpansion{MaxSize: "2Gi"},
+				},
+				Exposure: registryv1beta1.DevfileRegistryExposure{
+					Type: registryv1beta1.ExposureTypeNone,
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Case 36: Usage alert threshold above 100%",
+			spec: registryv1beta1.DevfileRegistrySpec{
+				Storage: registryv1beta1.DevfileRegistryStorage{
+					UsageAlertThreshold: &overThreshold,
+				},
+				Exposure: registryv1beta1.DevfileRegistryExposure{
+					Type: registryv1beta1.ExposureTypeNone,
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Case 37: Multiple replicas sharing a ReadWriteMany volume",
+			spec: registryv1beta1.DevfileRegistrySpec{
+				Storage: registryv1beta1.DevfileRegistryStorage{
+					AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteMany},
+				},
+				Exposure: registryv1beta1.DevfileRegistryExposure{
+					Type: registryv1beta1.ExposureTypeNone,
+				},
+				Replicas: &replicas,
+			},
+			wantErr: false,
+		},
+		{
+			name: "Case 38: Multiple replicas sharing an S3 bucket",
+			spec: registryv1beta1.DevfileRegistrySpec{
+				Storage: registryv1beta1.DevfileRegistryStorage{
+					Type: registryv1beta1.StorageTypeS3,
+					S3: registryv1beta1.DevfileRegistryS3Storage{
+						Bucket:            "devfiles",
+						CredentialsSecret: "s3-credentials",
+					},
+				},
+				Exposure: registryv1beta1.DevfileRegistryExposure{
+					Type: registryv1beta1.ExposureTypeNone,
+				},
+				Replicas: &replicas,
+			},
+			wantErr: false,
+		},
+		{
+			name: "Case 39: Multiple replicas on a ReadWriteOnce volume",
+			spec: registryv1beta1.DevfileRegistrySpec{
+				Exposure: registryv1beta1.DevfileRegistryExposure{
+					Type: registryv1beta1.ExposureTypeNone,
+				},
+				Replicas: &replicas,
+			},
+			wantErr: true,
+		},
+		{
+			name: "Case 40: Multiple replicas on ephemeral storage",
+			spec: registryv1beta1.DevfileRegistrySpec{
+				Storage: registryv1beta1.DevfileRegistryStorage{
+					Enabled: &storageDisabled,
+				},
+				Exposure: registryv1beta1.DevfileRegistryExposure{
+					Type: registryv1beta1.ExposureTypeNone,
+				},
+				Replicas: &replicas,
+			},
+			wantErr: true,
+		},
+		{
+			name:        "Case 41: Self-signed certificate behind edge routes",
+			isOpenShift: true,
+			spec: registryv1beta1.DevfileRegistrySpec{
+				TLS: registryv1beta1.DevfileRegistryTLS{
+					SelfSigned: true,
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name:        "Case 42: Self-signed certificate behind passthrough routes",
+			isOpenShift: true,
+			spec: registryv1beta1.DevfileRegistrySpec{
+				TLS: registryv1beta1.DevfileRegistryTLS{
+					SelfSigned:  true,
+					InPod:       true,
+					Termination: registryv1beta1.TLSTerminationPassthrough,
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name:              "Case 43: Self-signed certificate behind a Gateway",
+			gatewayAPIVersion: "v1",
+			spec: registryv1beta1.DevfileRegistrySpec{
+				TLS: registryv1beta1.DevfileRegistryTLS{
+					SelfSigned: true,
+				},
+				Exposure: registryv1beta1.DevfileRegistryExposure{
+					Type: registryv1beta1.ExposureTypeGateway,
+					Gateway: registryv1beta1.DevfileRegistryGateway{
+						Name:     "gateway",
+						Hostname: "registry.example.com",
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Case 44: Self-signed certificate served by the ingress",
+			spec: registryv1beta1.DevfileRegistrySpec{
+				TLS: registryv1beta1.DevfileRegistryTLS{
+					SelfSigned: true,
+				},
+				Exposure: registryv1beta1.DevfileRegistryExposure{
+					Ingress: registryv1beta1.DevfileRegistryIngress{
+						Domain: "example.com",
+					},
+				},
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config.ControllerCfg.SetHasCertManager(tt.hasCertManager)
+			defer config.ControllerCfg.SetHasCertManager(false)
+			config.ControllerCfg.SetVolumeSnapshotAPIVersion(tt.volumeSnapshotAPIVersion)
+			defer config.ControllerCfg.SetVolumeSnapshotAPIVersion("")
+			config.ControllerCfg.SetIsOpenShift(tt.isOpenShift)
+			config.ControllerCfg.SetGatewayAPIVersion(tt.gatewayAPIVersion)
+			defer config.ControllerCfg.SetIsOpenShift(false)
+			defer config.ControllerCfg.SetGatewayAPIVersion("")
+
+			errs := ValidateDevfileRegistry(&registryv1beta1.DevfileRegistry{Spec: tt.spec})
+			if (len(errs) > 0) != tt.wantErr {
+				t.Errorf("TestValidateDevfileRegistry error: expected error: %v got: %v", tt.wantErr, errs)
+			}
+		})
+	}
+}
+
+func TestValidateDevfileRegistryUpdate(t *testing.T) {
+	storageDisabled := false
+	standardClass := "standard"
+	fastClass := "fast"
+	now := metav1.Now()
+
+	tests := []struct {
+		name              string
+		oldSpec           registryv1beta1.DevfileRegistrySpec
+		newSpec           registryv1beta1.DevfileRegistrySpec
+		deletionTimestamp *metav1.Time
+		wantErr           bool
+	}{
+		{
+			name: "Case 1: Volume size increased",
+			oldSpec: registryv1beta1.DevfileRegistrySpec{
+				Storage: registryv1beta1.DevfileRegistryStorage{
+					Size: "1Gi",
+				},
+			},
+			newSpec: registryv1beta1.DevfileRegistrySpec{
+				Storage: registryv1beta1.DevfileRegistryStorage{
+					Size: "5Gi",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Case 2: Volume size decreased",
+			oldSpec: registryv1beta1.DevfileRegistrySpec{
+				Storage: registryv1beta1.DevfileRegistryStorage{
+					Size: "5Gi",
+				},
+			},
+			newSpec: registryv1beta1.DevfileRegistrySpec{
+				Storage: registryv1beta1.DevfileRegistryStorage{
+					Size: "2Gi",
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Case 3: Volume size unset, falling back to a smaller default",
+			oldSpec: registryv1beta1.DevfileRegistrySpec{
+				Storage: registryv1beta1.DevfileRegistryStorage{
+					Size: "5Gi",
+				},
+			},
+			newSpec: registryv1beta1.DevfileRegistrySpec{},
+			wantErr: true,
+		},
+		{
+			name: "Case 4: Volume size decreased while storage was disabled",
+			oldSpec: registryv1beta1.DevfileRegistrySpec{
+				Storage: registryv1beta1.DevfileRegistryStorage{
+					Enabled: &storageDisabled,
+					Size:    "5Gi",
+				},
+			},
+			newSpec: registryv1beta1.DevfileRegistrySpec{
+				Storage: registryv1beta1.DevfileRegistryStorage{
+					Size: "2Gi",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Case 5: Storage class changed",
+			oldSpec: registryv1beta1.DevfileRegistrySpec{
+				Storage: registryv1beta1.DevfileRegistryStorage{
+					StorageClassName: &standardClass,
+				},
+			},
+			newSpec: registryv1beta1.DevfileRegistrySpec{
+				Storage: registryv1beta1.DevfileRegistryStorage{
+					StorageClassName: &fastClass,
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name:    "Case 6: Default access modes set explicitly",
+			oldSpec: registryv1beta1.DevfileRegistrySpec{},
+			newSpec: registryv1beta1.DevfileRegistrySpec{
+				Storage: registryv1beta1.DevfileRegistryStorage{
+					AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:    "Case 7: Access modes changed",
+			oldSpec: registryv1beta1.DevfileRegistrySpec{},
+			newSpec: registryv1beta1.DevfileRegistrySpec{
+				Storage: registryv1beta1.DevfileRegistryStorage{
+					AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteMany},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Case 8: Finalizer removed from a registry with an invalid spec",
+			oldSpec: registryv1beta1.DevfileRegistrySpec{
+				Storage: registryv1beta1.DevfileRegistryStorage{
+					Size: "5Gi",
+				},
+			},
+			newSpec: registryv1beta1.DevfileRegistrySpec{
+				Storage: registryv1beta1.DevfileRegistryStorage{
+					Size:        "2Gi",
+					AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteMany},
+				},
+			},
+			deletionTimestamp: &now,
+			wantErr:           false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.oldSpec.Exposure.Ingress.Domain = "example.com"
+			tt.newSpec.Exposure.Ingress.Domain = "example.com"
+
+			newCR := &registryv1beta1.DevfileRegistry{Spec: tt.newSpec}
+			newCR.DeletionTimestamp = tt.deletionTimestamp
+			errs := ValidateDevfileRegistryUpdate(&registryv1beta1.DevfileRegistry{Spec: tt.oldSpec}, newCR)
+			if (len(errs) > 0) != tt.wantErr {
+				t.Errorf("TestValidateDevfileRegistryUpdate error: expected error: %v got: %v", tt.wantErr, errs)
+			}
+		})
+	}
+}
+
+func TestValidateDevfileRegistryBackup(t *testing.T) {
+	zero := int32(0)
+
+	tests := []struct {
+		name    string
+		spec    registryv1beta1.DevfileRegistryBackupSpec
+		wantErr bool
+	}{
+		{
+			name:    "Case 1: Scheduled backup",
+			spec:    registryv1beta1.DevfileRegistryBackupSpec{RegistryName: "devfileregistry-sample", Schedule: "0 2 * * *"},
+			wantErr: false,
+		},
+		{
+			name:    "Case 2: Invalid schedule",
+			spec:    registryv1beta1.DevfileRegistryBackupSpec{RegistryName: "devfileregistry-sample", Schedule: "every night"},
+			wantErr: true,
+		},
+		{
+			name:    "Case 3: No snapshot retained",
+			spec:    registryv1beta1.DevfileRegistryBackupSpec{RegistryName: "devfileregistry-sample", Retain: &zero},
+			wantErr: true,
+		},
+		{
+			name:    "Case 4: Missing registry name",
+			spec:    registryv1beta1.DevfileRegistryBackupSpec{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := ValidateDevfileRegistryBackup(&registryv1beta1.DevfileRegistryBackup{Spec: tt.spec})
+			if (len(errs) > 0) != tt.wantErr {
+				t.Errorf("TestValidateDevfileRegistryBackup error: expected error: %v got: %v", tt.wantErr, errs)
+			}
+		})
+	}
+}
+
+func TestValidateDevfileRegistryExport(t *testing.T) {
+	pvcArchive := registryv1beta1.DevfileRegistryArchive{
+		PersistentVolumeClaim: &registryv1beta1.DevfileRegistryArchivePVC{ClaimName: "devfileregistry-archives"},
+	}
+	s3Archive := registryv1beta1.DevfileRegistryArchive{
+		S3: &registryv1beta1.DevfileRegistryS3Storage{Bucket: "devfile-archives", CredentialsSecret: "s3-credentials"},
+	}
+
+	tests := []struct {
+		name    string
+		spec    registryv1beta1.DevfileRegistryExportSpec
+		wantErr bool
+	}{
+		{
+			name:    "Case 1: Export to a persistent volume claim",
+			spec:    registryv1beta1.DevfileRegistryExportSpec{RegistryName: "devfileregistry-sample", Archive: pvcArchive},
+			wantErr: false,
+		},
+		{
+			name:    "Case 2: Import from an S3 bucket",
+			spec:    registryv1beta1.DevfileRegistryExportSpec{RegistryName: "devfileregistry-sample", Mode: registryv1beta1.ExportModeImport, Archive: s3Archive},
+			wantErr: false,
+		},
+		{
+			name: "Case 3: Both archive locations set",
+			spec: registryv1beta1.DevfileRegistryExportSpec{
+				RegistryName: "devfileregistry-sample",
+				Archive: registryv1beta1.DevfileRegistryArchive{
+					PersistentVolumeClaim: pvcArchive.PersistentVolumeClaim,
+					S3:                    s3Archive.S3,
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name:    "Case 4: No archive location set",
+			spec:    registryv1beta1.DevfileRegistryExportSpec{RegistryName: "devfileregistry-sample"},
+			wantErr: true,
+		},
+		{
+			name: "Case 5: File name with a path",
+			spec: registryv1beta1.DevfileRegistryExportSpec{
+				RegistryName: "devfileregistry-sample",
+				Archive: registryv1beta1.DevfileRegistryArchive{
+					FileName:              "../stacks.tar",
+					PersistentVolumeClaim: pvcArchive.PersistentVolumeClaim,
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Case 6: S3 archive without credentials",
+			spec: registryv1beta1.DevfileRegistryExportSpec{
+				RegistryName: "devfileregistry-sample",
+				Archive: registryv1beta1.DevfileRegistryArchive{
+					S3: &registryv1beta1.DevfileRegistryS3Storage{Bucket: "devfile-archives"},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name:    "Case 7: Missing registry name",
+			spec:    registryv1beta1.DevfileRegistryExportSpec{Archive: pvcArchive},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := ValidateDevfileRegistryExport(&registryv1beta1.DevfileRegistryExport{Spec: tt.spec})
+			if (len(errs) > 0) != tt.wantErr {
+				t.Errorf("TestValidateDevfileRegistryExport error: expected error: %v got: %v", tt.wantErr, errs)
+			}
+		})
+	}
+}
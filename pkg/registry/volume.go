@@ -12,25 +12,30 @@
 package registry
 
 import (
+	"reflect"
+
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
 
-	registryv1alpha1 "github.com/devfile/registry-operator/api/v1alpha1"
+	registryv1beta1 "github.com/devfile/registry-operator/api/v1beta1"
 )
 
-// GenerateDevfileRegistryPVC returns a PVC for providing storage on the OCI registry container
-func GeneratePVC(cr *registryv1alpha1.DevfileRegistry, scheme *runtime.Scheme, labels map[string]string) *corev1.PersistentVolumeClaim {
+// GeneratePVC returns a PVC for providing storage on the OCI registry container. The claim requests no storage when
+// its size doesn't parse, see ParsePVCSize.
+func GeneratePVC(cr *registryv1beta1.DevfileRegistry, scheme *runtime.Scheme, labels map[string]string) *corev1.PersistentVolumeClaim {
+	resources := corev1.ResourceRequirements{}
+	if size, err := ParsePVCSize(cr); err == nil {
+		resources.Requests = corev1.ResourceList{corev1.ResourceStorage: size}
+	}
 	pvc := &corev1.PersistentVolumeClaim{
 		ObjectMeta: generateObjectMeta(cr.Name, cr.Namespace, labels),
 		Spec: corev1.PersistentVolumeClaimSpec{
-			AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
-			Resources: corev1.ResourceRequirements{
-				Requests: corev1.ResourceList{
-					corev1.ResourceStorage: resource.MustParse(getDevfileRegistryVolumeSize(cr)),
-				},
-			},
+			AccessModes:      GetAccessModes(cr),
+			StorageClassName: cr.Spec.Storage.StorageClassName,
+			DataSource:       GetPVCDataSource(cr),
+			Resources:        resources,
 		},
 	}
 
@@ -38,3 +43,81 @@ func GeneratePVC(cr *registryv1alpha1.DevfileRegistry, scheme *runtime.Scheme, l
 	ctrl.SetControllerReference(cr, pvc, scheme)
 	return pvc
 }
+
+// GetPVCDataSource returns the volume snapshot the persistent volume claim is restored from, or nil if the claim
+// starts out empty
+func GetPVCDataSource(cr *registryv1beta1.DevfileRegistry) *corev1.TypedLocalObjectReference {
+	if cr.Spec.Storage.RestoreFrom == "" {
+		return nil
+	}
+	snapshotGVK := VolumeSnapshotGVK()
+	return &corev1.TypedLocalObjectReference{
+		APIGroup: &snapshotGVK.Group,
+		Kind:     snapshotGVK.Kind,
+		Name:     cr.Spec.Storage.RestoreFrom,
+	}
+}
+
+// GetPVCResizeStatus reports whether the persistent volume claim is still being resized to the size it requests, and
+// how far the resize got
+func GetPVCResizeStatus(pvc *corev1.PersistentVolumeClaim) (bool, string) {
+	for _, condition := range pvc.Status.Conditions {
+		if condition.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch condition.Type {
+		case corev1.PersistentVolumeClaimResizing:
+			return true, "the volume is being expanded"
+		case corev1.PersistentVolumeClaimFileSystemResizePending:
+			return true, "the file system is waiting to be expanded"
+		}
+	}
+
+	requested := pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+	capacity, ok := pvc.Status.Capacity[corev1.ResourceStorage]
+	if ok && capacity.Cmp(requested) < 0 {
+		return true, "the capacity is " + capacity.String() + " out of " + requested.String()
+	}
+	return false, ""
+}
+
+// IsPVCSpecImmutableChanged returns true if the DevfileRegistry asks for a storage class or access modes the existing
+// persistent volume claim can't be changed to
+func IsPVCSpecImmutableChanged(cr *registryv1beta1.DevfileRegistry, pvc *corev1.PersistentVolumeClaim) bool {
+	if name := cr.Spec.Storage.StorageClassName; name != nil && (pvc.Spec.StorageClassName == nil || *name != *pvc.Spec.StorageClassName) {
+		return true
+	}
+	return !reflect.DeepEqual(GetAccessModes(cr), pvc.Spec.AccessModes)
+}
+
+// GetAutoExpansionSize returns the size a nearly full persistent volume claim requesting current is expanded to: half
+// again its size, rounded up to a whole mebibyte and capped at spec.storage.autoExpansion.maxSize. It returns false
+// when the claim can't grow any further.
+func GetAutoExpansionSize(cr *registryv1beta1.DevfileRegistry, current resource.Quantity) (resource.Quantity, bool) {
+	if cr.Spec.Storage.AutoExpansion == nil {
+		return resource.Quantity{}, false
+	}
+	maxSize, err := resource.ParseQuantity(cr.Spec.Storage.AutoExpansion.MaxSize)
+	if err != nil || current.Cmp(maxSize) >= 0 {
+		return resource.Quantity{}, false
+	}
+
+	const mebibyte = 1024 * 1024
+	bytes := current.Value() + current.Value()/2
+	bytes = (bytes + mebibyte - 1) / mebibyte * mebibyte
+	next := resource.NewQuantity(bytes, resource.BinarySI)
+	if next.Cmp(maxSize) > 0 {
+		return maxSize, true
+	}
+	return *next, true
+}
+
+// IsAutoExpandedSize returns true if a persistent volume claim requesting size may have been expanded to it by the
+// operator: it's larger than spec.storage.size, but not larger than spec.storage.autoExpansion.maxSize
+func IsAutoExpandedSize(cr *registryv1beta1.DevfileRegistry, size resource.Quantity) bool {
+	if cr.Spec.Storage.AutoExpansion == nil {
+		return false
+	}
+	maxSize, err := resource.ParseQuantity(cr.Spec.Storage.AutoExpansion.MaxSize)
+	return err == nil && size.Cmp(maxSize) <= 0
+}
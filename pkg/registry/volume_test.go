@@ -0,0 +1,196 @@
+//
+// Copyright (c) 2020 Red Hat, Inc.
+// This program and the accompanying materials are made
+// available under the terms of the Eclipse Public License 2.0
+// which is available at https://www.eclipse.org/legal/epl-2.0/
+//
+// SPDX-License-Identifier: EPL-2.0
+//
+// Contributors:
+//   Red Hat, Inc. - initial API and implementation
+
+package registry
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	registryv1beta1 "github.com/devfile/registry-operator/api/v1beta1"
+)
+
+func TestGetPVCResizeStatus(t *testing.T) {
+	tests := []struct {
+		name       string
+		capacity   string
+		conditions []corev1.PersistentVolumeClaimCondition
+		want       bool
+	}{
+		{
+			name:     "Case 1: Capacity matches the request",
+			capacity: "5Gi",
+			want:     false,
+		},
+		{
+			name:     "Case 2: Volume being expanded",
+			capacity: "1Gi",
+			conditions: []corev1.PersistentVolumeClaimCondition{
+				{Type: corev1.PersistentVolumeClaimResizing, Status: corev1.ConditionTrue},
+			},
+			want: true,
+		},
+		{
+			name:     "Case 3: File system waiting for the pod to restart",
+			capacity: "1Gi",
+			conditions: []corev1.PersistentVolumeClaimCondition{
+				{Type: corev1.PersistentVolumeClaimFileSystemResizePending, Status: corev1.ConditionTrue},
+			},
+			want: true,
+		},
+		{
+			name:     "Case 4: Resize requested but not picked up yet",
+			capacity: "1Gi",
+			want:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pvc := &corev1.PersistentVolumeClaim{
+				Spec: corev1.PersistentVolumeClaimSpec{
+					Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("5Gi")},
+					},
+				},
+				Status: corev1.PersistentVolumeClaimStatus{
+					Capacity:   corev1.ResourceList{corev1.ResourceStorage: resource.MustParse(tt.capacity)},
+					Conditions: tt.conditions,
+				},
+			}
+			if resizing, message := GetPVCResizeStatus(pvc); resizing != tt.want {
+				t.Errorf("TestGetPVCResizeStatus error: resizing mismatch, expected: %v got: %v (%s)", tt.want, resizing, message)
+			}
+		})
+	}
+}
+
+func TestGetAutoExpansionSize(t *testing.T) {
+	tests := []struct {
+		name    string
+		current string
+		maxSize string
+		want    string
+		wantOK  bool
+	}{
+		{
+			name:    "Case 1: First expansion",
+			current: "2Gi",
+			maxSize: "10Gi",
+			want:    "3Gi",
+			wantOK:  true,
+		},
+		{
+			name:    "Case 2: Expansion from an already expanded size",
+			current: "3Gi",
+			maxSize: "10Gi",
+			want:    "4608Mi",
+			wantOK:  true,
+		},
+		{
+			name:    "Case 3: Expansion capped at the maximum size",
+			current: "8Gi",
+			maxSize: "10Gi",
+			want:    "10Gi",
+			wantOK:  true,
+		},
+		{
+			name:    "Case 4: Maximum size reached",
+			current: "10Gi",
+			maxSize: "10Gi",
+			wantOK:  false,
+		},
+		{
+			name:    "Case 5: Auto expansion disabled",
+			current: "2Gi",
+			wantOK:  false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cr := &registryv1beta1.DevfileRegistry{
+				Spec: registryv1beta1.DevfileRegistrySpec{
+					Storage: registryv1beta1.DevfileRegistryStorage{Size: "2Gi"},
+				},
+			}
+			if tt.maxSize != "" {
+				cr.Spec.Storage.AutoExpansion = &registryv1beta1.DevfileRegistryStorageAutoExpansion{MaxSize: tt.maxSize}
+			}
+			size, ok := GetAutoExpansionSize(cr, resource.MustParse(tt.current))
+			if ok != tt.wantOK {
+				t.Fatalf("TestGetAutoExpansionSize error: expected: %v got: %v", tt.wantOK, ok)
+			}
+			if ok && size.Cmp(resource.MustParse(tt.want)) != 0 {
+				t.Errorf("TestGetAutoExpansionSize error: size mismatch, expected: %v got: %v", tt.want, size.String())
+			}
+		})
+	}
+}
+
+func TestIsAutoExpandedSize(t *testing.T) {
+	tests := []struct {
+		name    string
+		size    string
+		maxSize string
+		want    bool
+	}{
+		{
+			name:    "Case 1: Expanded size",
+			size:    "3Gi",
+			maxSize: "10Gi",
+			want:    true,
+		},
+		{
+			name:    "Case 2: Larger than the maximum size",
+			size:    "20Gi",
+			maxSize: "10Gi",
+			want:    false,
+		},
+		{
+			name: "Case 3: Auto expansion disabled",
+			size: "3Gi",
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cr := &registryv1beta1.DevfileRegistry{
+				Spec: registryv1beta1.DevfileRegistrySpec{
+					Storage: registryv1beta1.DevfileRegistryStorage{Size: "2Gi"},
+				},
+			}
+			if tt.maxSize != "" {
+				cr.Spec.Storage.AutoExpansion = &registryv1beta1.DevfileRegistryStorageAutoExpansion{MaxSize: tt.maxSize}
+			}
+			if got := IsAutoExpandedSize(cr, resource.MustParse(tt.size)); got != tt.want {
+				t.Errorf("TestIsAutoExpandedSize error: expected: %v got: %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestGeneratePVCInvalidSize(t *testing.T) {
+	cr := &registryv1beta1.DevfileRegistry{
+		Spec: registryv1beta1.DevfileRegistrySpec{
+			Storage: registryv1beta1.DevfileRegistryStorage{Size: "1 gigabyte"},
+		},
+	}
+	if _, err := ParsePVCSize(cr); err == nil {
+		t.Errorf("TestGeneratePVCInvalidSize error: expected an error parsing size %q", cr.Spec.Storage.Size)
+	}
+	// Generating the claim must not panic the operator
+	pvc := GeneratePVC(cr, runtime.NewScheme(), LabelsForDevfileRegistry(cr.Name))
+	if _, ok := pvc.Spec.Resources.Requests[corev1.ResourceStorage]; ok {
+		t.Errorf("TestGeneratePVCInvalidSize error: unexpected storage request %v", pvc.Spec.Resources.Requests)
+	}
+}
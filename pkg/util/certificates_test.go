@@ -0,0 +1,128 @@
+//
+// Copyright (c) 2020 Red Hat, Inc.
+// This program and the accompanying materials are made
+// available under the terms of the Eclipse Public License 2.0
+// which is available at https://www.eclipse.org/legal/epl-2.0/
+//
+// SPDX-License-Identifier: EPL-2.0
+//
+// Contributors:
+//   Red Hat, Inc. - initial API and implementation
+
+package util
+
+import (
+	"crypto/x509"
+	"testing"
+	"time"
+)
+
+func TestGenerateServingCertificate(t *testing.T) {
+	now := time.Now()
+	caCertPEM, caKeyPEM, err := GenerateCA("test CA", now)
+	if err != nil {
+		t.Fatalf("TestGenerateServingCertificate error: unexpected error generating the CA: %v", err)
+	}
+	otherCACertPEM, _, err := GenerateCA("other CA", now)
+	if err != nil {
+		t.Fatalf("TestGenerateServingCertificate error: unexpected error generating the CA: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		roots   []byte
+		host    string
+		wantErr bool
+	}{
+		{
+			name:    "Case 1: Verified against the signing CA",
+			roots:   caCertPEM,
+			host:    "registry.example.com",
+			wantErr: false,
+		},
+		{
+			name:    "Case 2: Verified for an IP address",
+			roots:   caCertPEM,
+			host:    "192.0.2.1",
+			wantErr: false,
+		},
+		{
+			name:    "Case 3: Verified for a host it wasn't issued for",
+			roots:   caCertPEM,
+			host:    "other.example.com",
+			wantErr: true,
+		},
+		{
+			name:    "Case 4: Verified against another CA",
+			roots:   otherCACertPEM,
+			host:    "registry.example.com",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			certPEM, _, err := GenerateServingCertificate(caCertPEM, caKeyPEM, []string{"registry.example.com", "192.0.2.1"}, now)
+			if err != nil {
+				t.Fatalf("TestGenerateServingCertificate error: unexpected error generating the certificate: %v", err)
+			}
+			cert, err := ParseCertificatePEM(certPEM)
+			if err != nil {
+				t.Fatalf("TestGenerateServingCertificate error: unexpected error parsing the certificate: %v", err)
+			}
+
+			roots := x509.NewCertPool()
+			roots.AppendCertsFromPEM(tt.roots)
+			_, err = cert.Verify(x509.VerifyOptions{DNSName: tt.host, Roots: roots})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("TestGenerateServingCertificate error: expected error: %v got: %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestNeedsRenewal(t *testing.T) {
+	issued := time.Now()
+	certPEM, _, err := GenerateCA("test CA", issued)
+	if err != nil {
+		t.Fatalf("TestNeedsRenewal error: unexpected error generating the CA: %v", err)
+	}
+	cert, err := ParseCertificatePEM(certPEM)
+	if err != nil {
+		t.Fatalf("TestNeedsRenewal error: unexpected error parsing the CA: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		now  time.Time
+		want bool
+	}{
+		{
+			name: "Case 1: Freshly issued",
+			now:  issued,
+			want: false,
+		},
+		{
+			name: "Case 2: Half of the validity left",
+			now:  issued.Add(CAValidity / 2),
+			want: false,
+		},
+		{
+			name: "Case 3: A quarter of the validity left",
+			now:  issued.Add(CAValidity * 3 / 4),
+			want: true,
+		},
+		{
+			name: "Case 4: Expired",
+			now:  issued.Add(CAValidity + time.Hour),
+			want: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			renew := NeedsRenewal(cert, tt.now)
+			if renew != tt.want {
+				t.Errorf("TestNeedsRenewal error: expected: %v got: %v", tt.want, renew)
+			}
+		})
+	}
+}
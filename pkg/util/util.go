@@ -13,8 +13,13 @@
 package util
 
 import (
+	"context"
 	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
 	"net/http"
+	"sync"
 	"time"
 
 	"k8s.io/apimachinery/pkg/util/wait"
@@ -38,3 +43,117 @@ func WaitForServer(url string, timeout time.Duration) error {
 		return false, nil
 	})
 }
+
+// NewProbeClient returns an HTTP client for probing devfile registry servers, giving up on requests after timeout.
+// If caFile is set, server certificates are verified against the CAs it contains in addition to the system ones,
+// otherwise they aren't verified at all.
+func NewProbeClient(caFile string, timeout time.Duration) (*http.Client, error) {
+	tlsConfig := &tls.Config{InsecureSkipVerify: true}
+	if caFile != "" {
+		pem, err := ioutil.ReadFile(caFile)
+		if err != nil {
+			return nil, err
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in %s", caFile)
+		}
+		tlsConfig = &tls.Config{RootCAs: pool}
+	}
+
+	return &http.Client{
+		Transport: &http.Transport{TLSClientConfig: tlsConfig},
+		Timeout:   timeout,
+	}, nil
+}
+
+// WithRootCAs returns a copy of the probe client that verifies server certificates against the PEM encoded CAs in
+// caBundle, in addition to the system ones. If serverName is set, certificates are verified against it rather than
+// against the host of the requested URL.
+func WithRootCAs(client *http.Client, caBundle []byte, serverName string) (*http.Client, error) {
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(caBundle) {
+		return nil, fmt.Errorf("no certificates found in the CA bundle")
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if t, ok := client.Transport.(*http.Transport); ok {
+		transport = t.Clone()
+	}
+	transport.TLSClientConfig = &tls.Config{RootCAs: pool, ServerName: serverName}
+
+	withCAs := *client
+	withCAs.Transport = transport
+	return &withCAs, nil
+}
+
+// RootCAClients caches the clients derived by WithRootCAs, so that their certificate pools and connections are reused
+// across requests while the CA bundle and server name they were derived for stay the same. The zero value is ready to
+// use, and it's safe for concurrent use.
+type RootCAClients struct {
+	mu      sync.Mutex
+	clients map[string]*rootCAClient
+}
+
+type rootCAClient struct {
+	caBundle   string
+	serverName string
+	client     *http.Client
+}
+
+// Get returns the client cached under key, deriving it from client through WithRootCAs when there's none or when it
+// was derived for another CA bundle or server name
+func (c *RootCAClients) Get(key string, client *http.Client, caBundle []byte, serverName string) (*http.Client, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if cached, ok := c.clients[key]; ok {
+		if cached.caBundle == string(caBundle) && cached.serverName == serverName {
+			return cached.client, nil
+		}
+		cached.client.CloseIdleConnections()
+		delete(c.clients, key)
+	}
+
+	withCAs, err := WithRootCAs(client, caBundle, serverName)
+	if err != nil {
+		return nil, err
+	}
+	if c.clients == nil {
+		c.clients = map[string]*rootCAClient{}
+	}
+	c.clients[key] = &rootCAClient{caBundle: string(caBundle), serverName: serverName, client: withCAs}
+	return withCAs, nil
+}
+
+// Delete drops the client cached under key and closes its idle connections
+func (c *RootCAClients) Delete(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if cached, ok := c.clients[key]; ok {
+		cached.client.CloseIdleConnections()
+		delete(c.clients, key)
+	}
+}
+
+// ProbeServer sends a single request to the server at url, and returns an error unless it responds with 200 OK
+func ProbeServer(ctx context.Context, client *http.Client, url string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("server responded with %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,200 @@
+//
+// Copyright (c) 2020 Red Hat, Inc.
+// This program and the accompanying materials are made
+// available under the terms of the Eclipse Public License 2.0
+// which is available at https://www.eclipse.org/legal/epl-2.0/
+//
+// SPDX-License-Identifier: EPL-2.0
+//
+// Contributors:
+//   Red Hat, Inc. - initial API and implementation
+
+package util
+
+import (
+	"context"
+	"encoding/pem"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestProbeServer(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		wantErr bool
+	}{
+		{
+			name: "Case 1: Server responding",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			},
+			wantErr: false,
+		},
+		{
+			name: "Case 2: Server returning an error",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusServiceUnavailable)
+			},
+			wantErr: true,
+		},
+		{
+			name: "Case 3: Server slower than the timeout",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				time.Sleep(500 * time.Millisecond)
+				w.WriteHeader(http.StatusOK)
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(tt.handler)
+			defer server.Close()
+
+			client, err := NewProbeClient("", 100*time.Millisecond)
+			if err != nil {
+				t.Fatalf("TestProbeServer error: unexpected error creating the client: %v", err)
+			}
+			err = ProbeServer(context.Background(), client, server.URL)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("TestProbeServer error: expected error: %v got: %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestNewProbeClient(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	dir, err := ioutil.TempDir("", "probe-client")
+	if err != nil {
+		t.Fatalf("TestNewProbeClient error: unexpected error creating a temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	serverCA := filepath.Join(dir, "server-ca.pem")
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := ioutil.WriteFile(serverCA, caPEM, 0600); err != nil {
+		t.Fatalf("TestNewProbeClient error: unexpected error writing the CA file: %v", err)
+	}
+	invalidCA := filepath.Join(dir, "invalid-ca.pem")
+	if err := ioutil.WriteFile(invalidCA, []byte("not a certificate"), 0600); err != nil {
+		t.Fatalf("TestNewProbeClient error: unexpected error writing the CA file: %v", err)
+	}
+
+	tests := []struct {
+		name          string
+		caFile        string
+		wantClientErr bool
+		wantProbeErr  bool
+	}{
+		{
+			name:         "Case 1: No CA, certificates aren't verified",
+			caFile:       "",
+			wantProbeErr: false,
+		},
+		{
+			name:         "Case 2: CA that signed the server certificate",
+			caFile:       serverCA,
+			wantProbeErr: false,
+		},
+		{
+			name:          "Case 3: CA file without certificates",
+			caFile:        invalidCA,
+			wantClientErr: true,
+		},
+		{
+			name:          "Case 4: Missing CA file",
+			caFile:        filepath.Join(dir, "missing.pem"),
+			wantClientErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := NewProbeClient(tt.caFile, time.Second)
+			if (err != nil) != tt.wantClientErr {
+				t.Fatalf("TestNewProbeClient error: expected client error: %v got: %v", tt.wantClientErr, err)
+			}
+			if err != nil {
+				return
+			}
+			err = ProbeServer(context.Background(), client, server.URL)
+			if (err != nil) != tt.wantProbeErr {
+				t.Errorf("TestNewProbeClient error: expected probe error: %v got: %v", tt.wantProbeErr, err)
+			}
+		})
+	}
+}
+
+func TestRootCAClients(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	base := &http.Client{Timeout: time.Second}
+
+	tests := []struct {
+		name       string
+		key        string
+		caBundle   []byte
+		serverName string
+		wantSame   bool
+		wantErr    bool
+	}{
+		{
+			name:     "Case 1: Same CA bundle and server name reuse the client",
+			key:      "test-namespace/test-registry",
+			caBundle: caPEM,
+			wantSame: true,
+		},
+		{
+			name:       "Case 2: Another server name derives a new client",
+			key:        "test-namespace/test-registry",
+			caBundle:   caPEM,
+			serverName: "test-registry.test-namespace.svc",
+			wantSame:   false,
+		},
+		{
+			name:     "Case 3: Another key derives a new client",
+			key:      "test-namespace/other-registry",
+			caBundle: caPEM,
+			wantSame: false,
+		},
+		{
+			name:     "Case 4: CA bundle without certificates",
+			key:      "test-namespace/test-registry",
+			caBundle: []byte("not a certificate"),
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clients := &RootCAClients{}
+			cached, err := clients.Get("test-namespace/test-registry", base, caPEM, "")
+			if err != nil {
+				t.Fatalf("TestRootCAClients error: unexpected error deriving the client: %v", err)
+			}
+			if err := ProbeServer(context.Background(), cached, server.URL); err != nil {
+				t.Fatalf("TestRootCAClients error: unexpected probe error: %v", err)
+			}
+
+			got, err := clients.Get(tt.key, base, tt.caBundle, tt.serverName)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("TestRootCAClients error: expected error: %v got: %v", tt.wantErr, err)
+			}
+			if err == nil && (got == cached) != tt.wantSame {
+				t.Errorf("TestRootCAClients error: client reuse mismatch, expected: %v got: %v", tt.wantSame, got == cached)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+//
+// Copyright (c) 2020 Red Hat, Inc.
+// This program and the accompanying materials are made
+// available under the terms of the Eclipse Public License 2.0
+// which is available at https://www.eclipse.org/legal/epl-2.0/
+//
+// SPDX-License-Identifier: EPL-2.0
+//
+// Contributors:
+//   Red Hat, Inc. - initial API and implementation
+
+package util
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/rest"
+)
+
+// statsSummary is the subset of the kubelet stats summary holding the volume stats of the pods
+type statsSummary struct {
+	Pods []struct {
+		PodRef struct {
+			Name      string `json:"name"`
+			Namespace string `json:"namespace"`
+		} `json:"podRef"`
+		Volumes []struct {
+			Name          string  `json:"name"`
+			UsedBytes     *uint64 `json:"usedBytes"`
+			CapacityBytes *uint64 `json:"capacityBytes"`
+		} `json:"volume"`
+	} `json:"pods"`
+}
+
+// GetVolumeStats returns the used bytes and capacity of a volume of the pod, as reported by the kubelet of the node the
+// pod runs on. The kubelet is reached through the node proxy of the API server.
+func GetVolumeStats(ctx context.Context, client rest.Interface, pod *corev1.Pod, volumeName string) (int64, int64, error) {
+	data, err := client.Get().Resource("nodes").Name(pod.Spec.NodeName).SubResource("proxy").Suffix("stats", "summary").DoRaw(ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+	return ParseVolumeStats(data, pod.Namespace, pod.Name, volumeName)
+}
+
+// ParseVolumeStats returns the used bytes and capacity of a volume of a pod from a kubelet stats summary
+func ParseVolumeStats(data []byte, namespace string, podName string, volumeName string) (int64, int64, error) {
+	summary := statsSummary{}
+	if err := json.Unmarshal(data, &summary); err != nil {
+		return 0, 0, err
+	}
+	for _, pod := range summary.Pods {
+		if pod.PodRef.Namespace != namespace || pod.PodRef.Name != podName {
+			continue
+		}
+		for _, volume := range pod.Volumes {
+			if volume.Name != volumeName {
+				continue
+			}
+			// The kubelet only reports the stats of a volume once it measured it
+			if volume.UsedBytes == nil || volume.CapacityBytes == nil || *volume.CapacityBytes == 0 {
+				return 0, 0, fmt.Errorf("volume %s of pod %s wasn't measured yet", volumeName, podName)
+			}
+			return int64(*volume.UsedBytes), int64(*volume.CapacityBytes), nil
+		}
+		return 0, 0, fmt.Errorf("no stats for volume %s of pod %s", volumeName, podName)
+	}
+	return 0, 0, fmt.Errorf("no stats for pod %s", podName)
+}
@@ -0,0 +1,84 @@
+//
+// Copyright (c) 2020 Red Hat, Inc.
+// This program and the accompanying materials are made
+// available under the terms of the Eclipse Public License 2.0
+// which is available at https://www.eclipse.org/legal/epl-2.0/
+//
+// SPDX-License-Identifier: EPL-2.0
+//
+// Contributors:
+//   Red Hat, Inc. - initial API and implementation
+
+package util
+
+import (
+	"testing"
+)
+
+const statsSummaryJSON = `{
+  "node": {"nodeName": "worker-0"},
+  "pods": [
+    {
+      "podRef": {"name": "devfileregistry-sample-7d9c5b8f4-x2k8p", "namespace": "default"},
+      "volume": [
+        {"name": "config", "usedBytes": 4096, "capacityBytes": 8192},
+        {"name": "devfile-registry-storage", "usedBytes": 858993459, "capacityBytes": 1073741824,
+         "pvcRef": {"name": "devfileregistry-sample", "namespace": "default"}}
+      ]
+    },
+    {
+      "podRef": {"name": "devfileregistry-other-5f8d7c6b9-q4w7e", "namespace": "default"},
+      "volume": [
+        {"name": "devfile-registry-storage"}
+      ]
+    }
+  ]
+}`
+
+func TestParseVolumeStats(t *testing.T) {
+	tests := []struct {
+		name         string
+		pod          string
+		volume       string
+		wantUsed     int64
+		wantCapacity int64
+		wantErr      bool
+	}{
+		{
+			name:         "Case 1: Measured volume",
+			pod:          "devfileregistry-sample-7d9c5b8f4-x2k8p",
+			volume:       "devfile-registry-storage",
+			wantUsed:     858993459,
+			wantCapacity: 1073741824,
+		},
+		{
+			name:    "Case 2: Volume not measured yet",
+			pod:     "devfileregistry-other-5f8d7c6b9-q4w7e",
+			volume:  "devfile-registry-storage",
+			wantErr: true,
+		},
+		{
+			name:    "Case 3: Unknown volume",
+			pod:     "devfileregistry-sample-7d9c5b8f4-x2k8p",
+			volume:  "tls",
+			wantErr: true,
+		},
+		{
+			name:    "Case 4: Pod on another node",
+			pod:     "devfileregistry-sample-7d9c5b8f4-zz9xv",
+			volume:  "devfile-registry-storage",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			used, capacity, err := ParseVolumeStats([]byte(statsSummaryJSON), "default", tt.pod, tt.volume)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("TestParseVolumeStats error: expected error: %v got: %v", tt.wantErr, err)
+			}
+			if used != tt.wantUsed || capacity != tt.wantCapacity {
+				t.Errorf("TestParseVolumeStats error: stats mismatch, expected: %v/%v got: %v/%v", tt.wantUsed, tt.wantCapacity, used, capacity)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+//
+// Copyright (c) 2020 Red Hat, Inc.
+// This program and the accompanying materials are made
+// available under the terms of the Eclipse Public License 2.0
+// which is available at https://www.eclipse.org/legal/epl-2.0/
+//
+// SPDX-License-Identifier: EPL-2.0
+//
+// Contributors:
+//   Red Hat, Inc. - initial API and implementation
+
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	registryv1beta1 "github.com/devfile/registry-operator/api/v1beta1"
+	"github.com/devfile/registry-operator/pkg/registry"
+)
+
+const mutateDevfileRegistryPath = "/mutate-registry-devfile-io-v1beta1-devfileregistry"
+
+// +kubebuilder:webhook:path=/mutate-registry-devfile-io-v1beta1-devfileregistry,mutating=true,failurePolicy=fail,sideEffects=None,groups=registry.devfile.io,resources=devfileregistries,verbs=create;update,versions=v1beta1,name=mdevfileregistry.kb.io,admissionReviewVersions={v1,v1beta1}
+
+// DevfileRegistryDefaulter writes the operator's defaults into the spec of DevfileRegistry resources
+type DevfileRegistryDefaulter struct {
+	decoder *admission.Decoder
+}
+
+// Handle defaults DevfileRegistry creates and updates
+func (d *DevfileRegistryDefaulter) Handle(ctx context.Context, req admission.Request) admission.Response {
+	cr := &registryv1beta1.DevfileRegistry{}
+	if err := d.decoder.Decode(req, cr); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+
+	registry.SetDevfileRegistryDefaults(cr)
+
+	defaulted, err := json.Marshal(cr)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	return admission.PatchResponseFromRaw(req.Object.Raw, defaulted)
+}
+
+// InjectDecoder injects the decoder into the defaulter
+func (d *DevfileRegistryDefaulter) InjectDecoder(decoder *admission.Decoder) error {
+	d.decoder = decoder
+	return nil
+}
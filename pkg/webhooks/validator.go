@@ -0,0 +1,63 @@
+//
+// Copyright (c) 2020 Red Hat, Inc.
+// This program and the accompanying materials are made
+// available under the terms of the Eclipse Public License 2.0
+// which is available at https://www.eclipse.org/legal/epl-2.0/
+//
+// SPDX-License-Identifier: EPL-2.0
+//
+// Contributors:
+//   Red Hat, Inc. - initial API and implementation
+
+package webhooks
+
+import (
+	"context"
+	"net/http"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	registryv1beta1 "github.com/devfile/registry-operator/api/v1beta1"
+	"github.com/devfile/registry-operator/pkg/registry"
+)
+
+const validateDevfileRegistryPath = "/validate-registry-devfile-io-v1beta1-devfileregistry"
+
+// +kubebuilder:webhook:path=/validate-registry-devfile-io-v1beta1-devfileregistry,mutating=false,failurePolicy=fail,sideEffects=None,groups=registry.devfile.io,resources=devfileregistries,verbs=create;update,versions=v1beta1,name=vdevfileregistry.kb.io,admissionReviewVersions={v1,v1beta1}
+
+// DevfileRegistryValidator rejects DevfileRegistry resources the operator wouldn't be able to deploy
+type DevfileRegistryValidator struct {
+	decoder *admission.Decoder
+}
+
+// Handle validates DevfileRegistry creates and updates
+func (v *DevfileRegistryValidator) Handle(ctx context.Context, req admission.Request) admission.Response {
+	cr := &registryv1beta1.DevfileRegistry{}
+	if err := v.decoder.Decode(req, cr); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+
+	var errs field.ErrorList
+	if req.Operation == admissionv1.Update {
+		oldCR := &registryv1beta1.DevfileRegistry{}
+		if err := v.decoder.DecodeRaw(req.OldObject, oldCR); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		errs = registry.ValidateDevfileRegistryUpdate(oldCR, cr)
+	} else {
+		errs = registry.ValidateDevfileRegistry(cr)
+	}
+
+	if len(errs) > 0 {
+		return invalidResponse(cr, errs)
+	}
+	return admission.Allowed("")
+}
+
+// InjectDecoder injects the decoder into the validator
+func (v *DevfileRegistryValidator) InjectDecoder(d *admission.Decoder) error {
+	v.decoder = d
+	return nil
+}
@@ -0,0 +1,46 @@
+//
+// Copyright (c) 2020 Red Hat, Inc.
+// This program and the accompanying materials are made
+// available under the terms of the Eclipse Public License 2.0
+// which is available at https://www.eclipse.org/legal/epl-2.0/
+//
+// SPDX-License-Identifier: EPL-2.0
+//
+// Contributors:
+//   Red Hat, Inc. - initial API and implementation
+
+package webhooks
+
+import (
+	admissionv1 "k8s.io/api/admission/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	registryv1beta1 "github.com/devfile/registry-operator/api/v1beta1"
+)
+
+// SetupWebhooks registers the DevfileRegistry admission and conversion webhooks with the manager's webhook server
+func SetupWebhooks(mgr ctrl.Manager) error {
+	server := mgr.GetWebhookServer()
+	server.Register(mutateDevfileRegistryPath, &webhook.Admission{Handler: &DevfileRegistryDefaulter{}})
+	server.Register(validateDevfileRegistryPath, &webhook.Admission{Handler: &DevfileRegistryValidator{}})
+
+	// The conversion webhook is served on /convert for every version that converts to the v1beta1 hub
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&registryv1beta1.DevfileRegistry{}).
+		Complete()
+}
+
+// invalidResponse denies an admission request with the same status the API server uses for its own validation errors
+func invalidResponse(cr *registryv1beta1.DevfileRegistry, errs field.ErrorList) admission.Response {
+	invalid := apierrors.NewInvalid(registryv1beta1.GroupVersion.WithKind("DevfileRegistry").GroupKind(), cr.Name, errs)
+	return admission.Response{
+		AdmissionResponse: admissionv1.AdmissionResponse{
+			Allowed: false,
+			Result:  &invalid.ErrStatus,
+		},
+	}
+}